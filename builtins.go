@@ -4,7 +4,10 @@ import (
 	"encoding/xml"
 )
 
-const xmlNs = "http://www.w3.org/2001/XMLSchema"
+const (
+	xmlNs = "http://www.w3.org/2001/XMLSchema"
+	xsiNs = "http://www.w3.org/2001/XMLSchema-instance"
+)
 
 var xmlTypes = map[xml.Name]TypeDefinition{
 	anyType.name:          anyType,
@@ -16,6 +19,23 @@ var xmlTypes = map[xml.Name]TypeDefinition{
 	anyURIPrimitive.name:  anyURIPrimitive,
 	qNamePrimitive.name:   qNamePrimitive,
 
+	base64BinaryPrimitive.name: base64BinaryPrimitive,
+	hexBinaryPrimitive.name:    hexBinaryPrimitive,
+
+	dateTimePrimitive.name:   dateTimePrimitive,
+	datePrimitive.name:       datePrimitive,
+	timePrimitive.name:       timePrimitive,
+	gYearMonthPrimitive.name: gYearMonthPrimitive,
+	gYearPrimitive.name:      gYearPrimitive,
+	gMonthDayPrimitive.name:  gMonthDayPrimitive,
+	gDayPrimitive.name:       gDayPrimitive,
+	gMonthPrimitive.name:     gMonthPrimitive,
+	dateTimeStampType.name:   dateTimeStampType,
+
+	durationPrimitive.name:     durationPrimitive,
+	dayTimeDurationType.name:   dayTimeDurationType,
+	yearMonthDurationType.name: yearMonthDurationType,
+
 	normalizedStringDataType.name: normalizedStringDataType,
 	tokenDataType.name:            tokenDataType,
 	nmTokenDataType.name:          nmTokenDataType,
@@ -25,6 +45,16 @@ var xmlTypes = map[xml.Name]TypeDefinition{
 	integerType.name:              integerType,
 	nonNegativeIntegerType.name:   nonNegativeIntegerType,
 	positiveIntegerType.name:      positiveIntegerType,
+	nonPositiveIntegerType.name:   nonPositiveIntegerType,
+	negativeIntegerType.name:      negativeIntegerType,
+	longType.name:                 longType,
+	intType.name:                  intType,
+	shortType.name:                shortType,
+	byteType.name:                 byteType,
+	unsignedLongType.name:         unsignedLongType,
+	unsignedIntType.name:          unsignedIntType,
+	unsignedShortType.name:        unsignedShortType,
+	unsignedByteType.name:         unsignedByteType,
 }
 
 var anyType = &complexTypeDefinition{
@@ -55,7 +85,7 @@ var anyType = &complexTypeDefinition{
 		},
 	},
 	attributeUses: []*attributeUse{},
-	attributeWildcard: wildcard{
+	attributeWildcard: &wildcard{
 		namespaceConstraint: wildcardNamespaceConstraint{
 			variety:         "any",
 			namespaces:      []string{},
@@ -128,7 +158,7 @@ var integerType = &simpleTypeDefinition{
 	variety:            "atomic",
 	facets: []ConstrainingFacet{
 		&whiteSpaceFacet{value: "collapse", fixed: true},
-		&fractionDigitsFacet{value: 0, fixed: true},
+		&fractionDigitsFacet{numFacet{value: 0, fixed: true}},
 	},
 	fundamentalFacets: []FundamentalFacet{
 		&orderedFacet{string: "false"},
@@ -149,8 +179,8 @@ var nonNegativeIntegerType = &simpleTypeDefinition{
 	variety:            "atomic",
 	facets: []ConstrainingFacet{
 		&whiteSpaceFacet{value: "collapse", fixed: true},
-		&fractionDigitsFacet{value: 0, fixed: true},
-		&minInclusiveFacet{value: "0"},
+		&fractionDigitsFacet{numFacet{value: 0, fixed: true}},
+		&minInclusiveFacet{boundFacet{value: "0"}},
 	},
 	fundamentalFacets: []FundamentalFacet{
 		&orderedFacet{string: "false"},
@@ -171,8 +201,8 @@ var positiveIntegerType = &simpleTypeDefinition{
 	variety:            "atomic",
 	facets: []ConstrainingFacet{
 		&whiteSpaceFacet{value: "collapse", fixed: true},
-		&fractionDigitsFacet{value: 0, fixed: true},
-		&minInclusiveFacet{value: "1"},
+		&fractionDigitsFacet{numFacet{value: 0, fixed: true}},
+		&minInclusiveFacet{boundFacet{value: "1"}},
 	},
 	fundamentalFacets: []FundamentalFacet{
 		&orderedFacet{string: "false"},
@@ -186,6 +216,19 @@ var positiveIntegerType = &simpleTypeDefinition{
 	goType: "uint",
 }
 
+var nonPositiveIntegerType = newIntegerType("nonPositiveInteger", integerType, "int", "", "0")
+var negativeIntegerType = newIntegerType("negativeInteger", nonPositiveIntegerType, "int", "", "-1")
+
+var longType = newIntegerType("long", integerType, "int64", "-9223372036854775808", "9223372036854775807")
+var intType = newIntegerType("int", longType, "int32", "-2147483648", "2147483647")
+var shortType = newIntegerType("short", intType, "int16", "-32768", "32767")
+var byteType = newIntegerType("byte", shortType, "int8", "-128", "127")
+
+var unsignedLongType = newIntegerType("unsignedLong", nonNegativeIntegerType, "uint64", "0", "18446744073709551615")
+var unsignedIntType = newIntegerType("unsignedInt", unsignedLongType, "uint32", "0", "4294967295")
+var unsignedShortType = newIntegerType("unsignedShort", unsignedIntType, "uint16", "0", "65535")
+var unsignedByteType = newIntegerType("unsignedByte", unsignedShortType, "uint8", "0", "255")
+
 var booleanPrimitive = newPrimitive(
 	"boolean",
 	[]ConstrainingFacet{
@@ -226,6 +269,117 @@ var qNamePrimitive = newPrimitive(
 	},
 )
 
+var base64BinaryPrimitive = newPrimitive(
+	"base64Binary",
+	[]ConstrainingFacet{
+		&whiteSpaceFacet{value: "collapse", fixed: true},
+	},
+	[]FundamentalFacet{
+		&orderedFacet{string: "false"},
+		&boundedFacet{bool: false},
+		&cardinalityFacet{string: "countably infinite"},
+		&numericFacet{bool: false},
+	},
+)
+
+var hexBinaryPrimitive = newPrimitive(
+	"hexBinary",
+	[]ConstrainingFacet{
+		&whiteSpaceFacet{value: "collapse", fixed: true},
+	},
+	[]FundamentalFacet{
+		&orderedFacet{string: "false"},
+		&boundedFacet{bool: false},
+		&cardinalityFacet{string: "countably infinite"},
+		&numericFacet{bool: false},
+	},
+)
+
+var dateTimePrimitive = newDateTimePrimitive("dateTime")
+var datePrimitive = newDateTimePrimitive("date")
+var timePrimitive = newDateTimePrimitive("time")
+var gYearMonthPrimitive = newDateTimePrimitive("gYearMonth")
+var gYearPrimitive = newDateTimePrimitive("gYear")
+var gMonthDayPrimitive = newDateTimePrimitive("gMonthDay")
+var gDayPrimitive = newDateTimePrimitive("gDay")
+var gMonthPrimitive = newDateTimePrimitive("gMonth")
+
+var dateTimeStampType = &simpleTypeDefinition{
+	name:               xml.Name{Space: xmlNs, Local: "dateTimeStamp"},
+	baseTypeDefinition: dateTimePrimitive,
+	final:              []string{},
+	variety:            "atomic",
+	facets: []ConstrainingFacet{
+		&whiteSpaceFacet{value: "collapse", fixed: true},
+		&explicitTimezoneFacet{value: "required", fixed: true},
+	},
+	fundamentalFacets: []FundamentalFacet{
+		&orderedFacet{string: "partial"},
+		&boundedFacet{bool: false},
+		&cardinalityFacet{string: "countably infinite"},
+		&numericFacet{bool: false},
+	},
+	annotatedComponent: annotatedComponent{
+		annotations: []annotation{},
+	},
+	goType: "xs.DateTimeStamp",
+}
+
+var durationPrimitive = newPrimitive(
+	"duration",
+	[]ConstrainingFacet{
+		&whiteSpaceFacet{value: "collapse", fixed: true},
+	},
+	[]FundamentalFacet{
+		&orderedFacet{string: "partial"},
+		&boundedFacet{bool: false},
+		&cardinalityFacet{string: "countably infinite"},
+		&numericFacet{bool: false},
+	},
+)
+
+var dayTimeDurationType = &simpleTypeDefinition{
+	name:               xml.Name{Space: xmlNs, Local: "dayTimeDuration"},
+	baseTypeDefinition: durationPrimitive,
+	final:              []string{},
+	variety:            "atomic",
+	facets: []ConstrainingFacet{
+		&whiteSpaceFacet{value: "collapse", fixed: true},
+		&patternFacet{value: []string{`[^YM]*(T.*)?`}},
+	},
+	fundamentalFacets: []FundamentalFacet{
+		&orderedFacet{string: "partial"},
+		&boundedFacet{bool: false},
+		&cardinalityFacet{string: "countably infinite"},
+		&numericFacet{bool: false},
+	},
+	annotatedComponent: annotatedComponent{
+		annotations: []annotation{},
+	},
+	goType: "xs.Duration",
+}
+
+var yearMonthDurationType = &simpleTypeDefinition{
+	name:               xml.Name{Space: xmlNs, Local: "yearMonthDuration"},
+	baseTypeDefinition: durationPrimitive,
+	final:              []string{},
+	variety:            "atomic",
+	facets: []ConstrainingFacet{
+		&whiteSpaceFacet{value: "collapse", fixed: true},
+		&patternFacet{value: []string{`[^DT]*`}},
+	},
+	fundamentalFacets: []FundamentalFacet{
+		&orderedFacet{string: "partial"},
+		&boundedFacet{bool: false},
+		&cardinalityFacet{string: "countably infinite"},
+		&numericFacet{bool: false},
+	},
+	annotatedComponent: annotatedComponent{
+		annotations: []annotation{},
+	},
+	goType: "xs.Duration",
+}
+
 var normalizedStringDataType = &simpleTypeDefinition{
 	name:               xml.Name{Space: xmlNs, Local: "normalizedString"},
 	baseTypeDefinition: stringPrimitive,
@@ -272,7 +426,7 @@ var nmTokenDataType = &simpleTypeDefinition{
 	final:              []string{},
 	variety:            "atomic",
 	facets: []ConstrainingFacet{
-		&patternFacet{value: "\\c+"},
+		&patternFacet{value: []string{`\c+`}},
 		&whiteSpaceFacet{value: "collapse"},
 	},
 	fundamentalFacets: []FundamentalFacet{
@@ -349,10 +503,21 @@ var idDataType = &simpleTypeDefinition{
 
 func init() {
 	stringPrimitive.goType = "string"
-	decimalPrimitive.goType = "float64"
+	decimalPrimitive.goType = "xs.Decimal"
 	anyURIPrimitive.goType = "string"
 	qNamePrimitive.goType = "string"
 	booleanPrimitive.goType = "bool"
+	base64BinaryPrimitive.goType = "xs.Base64Binary"
+	hexBinaryPrimitive.goType = "xs.HexBinary"
+	dateTimePrimitive.goType = "xs.DateTime"
+	datePrimitive.goType = "xs.Date"
+	timePrimitive.goType = "xs.Time"
+	gYearMonthPrimitive.goType = "xs.GYearMonth"
+	gYearPrimitive.goType = "xs.GYear"
+	gMonthDayPrimitive.goType = "xs.GMonthDay"
+	gDayPrimitive.goType = "xs.GDay"
+	gMonthPrimitive.goType = "xs.GMonth"
+	durationPrimitive.goType = "xs.Duration"
 }
 
 // newPrimitive creates a new primitive type by a template.
@@ -371,3 +536,57 @@ func newPrimitive(name string, facets []ConstrainingFacet, fundamentalFacets []F
 	t.primitiveTypeDefinition = t
 	return t
 }
+
+// newDateTimePrimitive creates one of the date/time primitive types, which share their facets.
+func newDateTimePrimitive(name string) *simpleTypeDefinition {
+	return newPrimitive(
+		name,
+		[]ConstrainingFacet{
+			&whiteSpaceFacet{value: "collapse", fixed: true},
+			&explicitTimezoneFacet{value: "optional"},
+		},
+		[]FundamentalFacet{
+			&orderedFacet{string: "partial"},
+			&boundedFacet{bool: false},
+			&cardinalityFacet{string: "countably infinite"},
+			&numericFacet{bool: false},
+		},
+	)
+}
+
+// newIntegerType creates a built-in type derived from xs:integer by bounds. An empty bound is not restricted.
+func newIntegerType(name string, base *simpleTypeDefinition, goType string, min string, max string) *simpleTypeDefinition {
+	facets := []ConstrainingFacet{
+		&whiteSpaceFacet{value: "collapse", fixed: true},
+		&fractionDigitsFacet{numFacet{value: 0, fixed: true}},
+	}
+	bounded := min != "" && max != ""
+	if min != "" {
+		facets = append(facets, &minInclusiveFacet{boundFacet{value: min}})
+	}
+	if max != "" {
+		facets = append(facets, &maxInclusiveFacet{boundFacet{value: max}})
+	}
+	cardinality := "countably infinite"
+	if bounded {
+		cardinality = "finite"
+	}
+
+	return &simpleTypeDefinition{
+		name:               xml.Name{Space: xmlNs, Local: name},
+		baseTypeDefinition: base,
+		final:              []string{},
+		variety:            "atomic",
+		facets:             facets,
+		fundamentalFacets: []FundamentalFacet{
+			&orderedFacet{string: "total"},
+			&boundedFacet{bool: bounded},
+			&cardinalityFacet{string: cardinality},
+			&numericFacet{bool: true},
+		},
+		annotatedComponent: annotatedComponent{
+			annotations: []annotation{},
+		},
+		goType: goType,
+	}
+}
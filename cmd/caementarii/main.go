@@ -0,0 +1,356 @@
+// Command caementarii generates Go packages from XML Schema documents.
+//
+// Usage:
+//
+//	caementarii [flags] schema.xsd...
+//
+// Each schema document is generated into a Go file of the same name with the .go extension. Documents which it
+// imports and includes are resolved relative to it, or by XML Catalogs. The command is meant to be run by go generate:
+//
+//	//go:generate caementarii -catalog catalog.xml order.xsd
+//
+// The flags are:
+//
+//	-o dir
+//		The output directory, the current directory by default.
+//	-pkg name
+//		The package name, $GOPACKAGE or the name of the output directory or of the import path by default.
+//	-ns namespace=dir
+//		Generate schema documents of the target namespace into the directory, which is relative to the output
+//		directory. The name of the directory is the package name. The flag could be repeated.
+//	-import path
+//		The import path of the output directory. If it's set, imported namespaces are generated into packages of
+//		their own, see goxsd.Generator.GeneratePackages. Namespaces which are not mapped by -ns are generated into
+//		subdirectories named after them.
+//	-catalog file
+//		Resolve schema documents by an OASIS XML Catalog. The flag could be repeated.
+//	-decimal float64|exact|rat
+//		The Go type of xs:decimal, see goxsd.DecimalType.
+//	-integer int|big
+//		The Go type of xs:integer, see goxsd.IntegerType.
+//	-mixed elements|ordered
+//		The representation of mixed content, see goxsd.MixedContent.
+//	-check
+//		Don't write files, but fail if any of them is out of date, or if the output directory has generated files
+//		which are not generated anymore.
+//
+// Packages of imported namespaces are generated once, even if several schema documents import them, and their files
+// are not attributed to any of the documents.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/resolver"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// errStale is returned in the check mode if generated files are out of date.
+var errStale = errors.New("generated files are out of date")
+
+// generatedPrefix starts the header of generated files.
+const generatedPrefix = "// Code generated by caementarii"
+
+// header returns the header of a generated file, which names the schema document it's generated from, if any.
+func header(source string) string {
+	if source == "" {
+		return generatedPrefix + ". DO NOT EDIT.\n\n"
+	}
+	return generatedPrefix + " from " + source + ". DO NOT EDIT.\n\n"
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stderr)
+	switch err {
+	case nil, flag.ErrHelp:
+	default:
+		fmt.Fprintln(os.Stderr, "caementarii:", err)
+		os.Exit(1)
+	}
+}
+
+// stringList is a flag which could be repeated.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// namespaceMap is a flag of namespace=dir pairs which could be repeated.
+type namespaceMap map[string]string
+
+func (m namespaceMap) String() string {
+	pairs := make([]string, 0, len(m))
+	for ns, dir := range m {
+		pairs = append(pairs, ns+"="+dir)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (m namespaceMap) Set(value string) error {
+	// A namespace is a URI, which could contain '=', so the directory is after the last one.
+	i := strings.LastIndex(value, "=")
+	if i < 0 || value[i+1:] == "" {
+		return fmt.Errorf("%q is not a namespace=dir pair", value)
+	}
+	m[value[:i]] = filepath.FromSlash(value[i+1:])
+	return nil
+}
+
+// enumFlag is a flag whose value is one of the names of options.
+type enumFlag struct {
+	value   string
+	options map[string]int
+}
+
+func (f *enumFlag) String() string {
+	return f.value
+}
+
+func (f *enumFlag) Set(value string) error {
+	if _, ok := f.options[value]; !ok {
+		names := make([]string, 0, len(f.options))
+		for name := range f.options {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(names, ", "))
+	}
+	f.value = value
+	return nil
+}
+
+func (f *enumFlag) Option() int {
+	return f.options[f.value]
+}
+
+func run(args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("caementarii", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: caementarii [flags] schema.xsd...")
+		flags.PrintDefaults()
+	}
+
+	out := flags.String("o", ".", "the output `dir`ectory")
+	pkgName := flags.String("pkg", "", "the package `name`, $GOPACKAGE or the name of the output directory by default")
+	namespaces := namespaceMap{}
+	flags.Var(namespaces, "ns", "generate documents of a target namespace into a directory, as `namespace=dir`")
+	importPath := flags.String("import", "", "the import `path` of the output directory, which enables a package per namespace")
+	var catalogs stringList
+	flags.Var(&catalogs, "catalog", "resolve schema documents by an XML Catalog `file`")
+	decimal := &enumFlag{value: "float64", options: map[string]int{
+		"float64": int(goxsd.DecimalFloat64),
+		"exact":   int(goxsd.DecimalExact),
+		"rat":     int(goxsd.DecimalBigRat),
+	}}
+	flags.Var(decimal, "decimal", "the Go `type` of xs:decimal: float64, exact or rat")
+	integer := &enumFlag{value: "int", options: map[string]int{
+		"int": int(goxsd.IntegerInt),
+		"big": int(goxsd.IntegerBigInt),
+	}}
+	flags.Var(integer, "integer", "the Go `type` of xs:integer: int or big")
+	mixed := &enumFlag{value: "elements", options: map[string]int{
+		"elements": int(goxsd.MixedElements),
+		"ordered":  int(goxsd.MixedOrdered),
+	}}
+	flags.Var(mixed, "mixed", "the `representation` of mixed content: elements or ordered")
+	check := flags.Bool("check", false, "don't write files, but fail if any of them is out of date or not generated anymore")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no schema documents")
+	}
+
+	if *pkgName == "" {
+		*pkgName = os.Getenv("GOPACKAGE")
+	}
+	if *pkgName == "" && *importPath == "" {
+		dir, err := filepath.Abs(*out)
+		if err != nil {
+			return err
+		}
+		*pkgName = filepath.Base(dir)
+	}
+
+	r := resolver.New(nil)
+	for _, c := range catalogs {
+		if err := r.AddCatalog(c); err != nil {
+			return err
+		}
+	}
+
+	packages := make(map[string]string, len(namespaces))
+	for ns, dir := range namespaces {
+		packages[ns] = path.Join(*importPath, filepath.ToSlash(dir))
+	}
+
+	// Files of all schema documents are collected first, so a package which several documents import is generated
+	// once.
+	files := map[string][]byte{}
+	add := func(name string, data []byte) error {
+		if existing, ok := files[name]; ok && !bytes.Equal(existing, data) {
+			return fmt.Errorf("%s is generated differently from several schema documents", name)
+		}
+		files[name] = data
+		return nil
+	}
+	documents := map[string]bool{}
+	for _, location := range flags.Args() {
+		s, err := r.Load(location)
+		if err != nil {
+			return err
+		}
+
+		dir, pkg := *out, *pkgName
+		if d, ok := namespaces[s.TargetNamespace]; ok {
+			dir, pkg = filepath.Join(*out, d), filepath.Base(d)
+		}
+
+		g := goxsd.Generator{
+			PkgName:        pkg,
+			ImportResolver: r.Resolve,
+			Decimal:        goxsd.DecimalType(decimal.Option()),
+			Integer:        goxsd.IntegerType(integer.Option()),
+			Mixed:          goxsd.MixedContent(mixed.Option()),
+		}
+		base := filepath.Base(location)
+		documents[base] = true
+		name := filepath.Join(dir, strings.TrimSuffix(base, filepath.Ext(base))+".go")
+
+		if *importPath == "" {
+			buf := new(bytes.Buffer)
+			if err := g.Generate(s, buf); err != nil {
+				return fmt.Errorf("%s: %w", location, err)
+			}
+			if err := add(name, append([]byte(header(base)), buf.Bytes()...)); err != nil {
+				return err
+			}
+		} else {
+			g.ImportPath, g.Packages = *importPath, packages
+			if d, ok := namespaces[s.TargetNamespace]; ok {
+				g.ImportPath = path.Join(*importPath, filepath.ToSlash(d))
+			}
+			sources, err := g.GeneratePackages(s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", location, err)
+			}
+			for p, source := range sources {
+				// The package of the schema document is named after it, and other packages after their directories.
+				var err error
+				if p == g.ImportPath {
+					err = add(name, append([]byte(header(base)), source...))
+				} else if !strings.HasPrefix(p, *importPath+"/") {
+					err = fmt.Errorf("%s: package %s is not in %s", location, p, *importPath)
+				} else {
+					d := filepath.Join(*out, filepath.FromSlash(strings.TrimPrefix(p, *importPath+"/")))
+					err = add(filepath.Join(d, path.Base(p)+".go"), append([]byte(header("")), source...))
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if *check {
+		return checkFiles(*out, names, files, documents, stderr)
+	}
+	for _, name := range names {
+		if err := os.MkdirAll(filepath.Dir(name), 0777); err != nil {
+			return err
+		}
+		if err := os.WriteFile(name, files[name], 0666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFiles reports generated files which are out of date, and files in the output directory which were generated
+// from the same schema documents or for imported namespaces, but are not generated anymore.
+func checkFiles(out string, names []string, files map[string][]byte, documents map[string]bool, stderr io.Writer) error {
+	stale := false
+	for _, name := range names {
+		existing, err := os.ReadFile(name)
+		if err != nil || !bytes.Equal(existing, files[name]) {
+			fmt.Fprintf(stderr, "%s is out of date\n", name)
+			stale = true
+		}
+	}
+
+	err := filepath.WalkDir(out, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// Directories which the go command ignores are skipped.
+			if base := d.Name(); name != out && (strings.HasPrefix(base, ".") || strings.HasPrefix(base, "_") || base == "testdata" || base == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if _, ok := files[name]; ok || filepath.Ext(name) != ".go" {
+			return nil
+		}
+		source, ok, err := generatedFrom(name)
+		if err != nil || !ok || source != "" && !documents[source] {
+			return err
+		}
+		fmt.Fprintf(stderr, "%s is not generated anymore\n", name)
+		stale = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if stale {
+		return errStale
+	}
+	return nil
+}
+
+// generatedFrom reports whether a file is generated by the command, and the schema document it's generated from.
+func generatedFrom(name string) (string, bool, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReader(f).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", false, err
+	}
+	line = strings.TrimSuffix(line, "\n")
+	if !strings.HasPrefix(line, generatedPrefix) || !strings.HasSuffix(line, ". DO NOT EDIT.") {
+		return "", false, nil
+	}
+	source := strings.TrimSuffix(strings.TrimPrefix(line, generatedPrefix), ". DO NOT EDIT.")
+	return strings.TrimPrefix(source, " from "), true, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	stderr := new(bytes.Buffer)
+	assert.NoError(t, run([]string{"-o", dir, "-pkg", "simple27", "../../tests/simple27/simple27.xsd"}, stderr))
+
+	data, err := os.ReadFile(filepath.Join(dir, "simple27.go"))
+	assert.NoError(t, err)
+	expected, err := os.ReadFile("../../tests/simple27/simple27.go")
+	assert.NoError(t, err)
+	assert.Equal(t, "// Code generated by caementarii from simple27.xsd. DO NOT EDIT.\n\n"+string(expected), string(data))
+
+	assert.NoError(t, run([]string{"-o", dir, "-pkg", "simple27", "-check", "../../tests/simple27/simple27.xsd"}, stderr))
+	assert.Equal(t, "", stderr.String())
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "simple27.go"), data[1:], 0666))
+	err = run([]string{"-o", dir, "-pkg", "simple27", "-check", "../../tests/simple27/simple27.xsd"}, stderr)
+	assert.Equal(t, errStale, err)
+	assert.True(t, strings.HasSuffix(stderr.String(), "simple27.go is out of date\n"))
+}
+
+func TestRunNamespace(t *testing.T) {
+	dir := t.TempDir()
+	stderr := new(bytes.Buffer)
+	args := []string{"-o", dir, "-ns", "urn:simple27=schemas/directory", "-decimal", "exact", "../../tests/simple27/simple27.xsd"}
+	assert.NoError(t, run(args, stderr))
+
+	data, err := os.ReadFile(filepath.Join(dir, "schemas", "directory", "simple27.go"))
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(data), "\npackage directory\n"))
+}
+
+func TestRunPackages(t *testing.T) {
+	dir := t.TempDir()
+	stderr := new(bytes.Buffer)
+	args := []string{"-o", dir, "-import", "github.com/realmfoo/caementarii/tests/simple28", "../../tests/simple28/simple28.xsd"}
+	assert.NoError(t, run(args, stderr))
+
+	// Packages of imported namespaces aren't attributed to the schema document which imports them.
+	headers := map[string]string{
+		"simple28.go":      "// Code generated by caementarii from simple28.xsd. DO NOT EDIT.\n\n",
+		"common/common.go": "// Code generated by caementarii. DO NOT EDIT.\n\n",
+		"xml/xml.go":       "// Code generated by caementarii. DO NOT EDIT.\n\n",
+	}
+	for name, header := range headers {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		assert.NoError(t, err)
+		expected, err := os.ReadFile(filepath.Join("../../tests/simple28", filepath.FromSlash(name)))
+		assert.NoError(t, err)
+		assert.Equal(t, header+string(expected), string(data))
+	}
+
+	assert.NoError(t, run(append([]string{"-check"}, args...), stderr))
+	assert.Equal(t, "", stderr.String())
+
+	// Files which are not generated anymore are reported, unless they are generated from other schema documents.
+	leftovers := map[string]string{
+		"old/old.go":     "// Code generated by caementarii. DO NOT EDIT.\n\npackage old\n",
+		"removed.go":     "// Code generated by caementarii from simple28.xsd. DO NOT EDIT.\n\npackage simple28\n",
+		"other.go":       "// Code generated by caementarii from other.xsd. DO NOT EDIT.\n\npackage simple28\n",
+		"handwritten.go": "package simple28\n",
+	}
+	for name, data := range leftovers {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, filepath.FromSlash(name))), 0777))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(data), 0666))
+	}
+	assert.Equal(t, errStale, run(append([]string{"-check"}, args...), stderr))
+	assert.Equal(t, filepath.Join(dir, "old", "old.go")+" is not generated anymore\n"+
+		filepath.Join(dir, "removed.go")+" is not generated anymore\n", stderr.String())
+}
+
+func TestRunSharedPackages(t *testing.T) {
+	dir := t.TempDir()
+	stderr := new(bytes.Buffer)
+	xsd := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:%s" xmlns:c="urn:simple28:common">
+	<xs:import namespace="urn:simple28:common" schemaLocation="%s"/>
+	<xs:element name="address" type="c:Address"/>
+</xs:schema>`
+	common, err := filepath.Abs("../../tests/simple28/simple28-common.xsd")
+	assert.NoError(t, err)
+	for _, name := range []string{"first", "second"} {
+		data := fmt.Sprintf(xsd, name, filepath.ToSlash(common))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name+".xsd"), []byte(data), 0666))
+	}
+
+	// The package of the namespace which both documents import is generated once.
+	args := []string{"-o", dir, "-import", "example.com/shared", "-ns", "urn:first=first", "-ns", "urn:second=second",
+		"-ns", "urn:simple28:common=common", "-ns", "http://www.w3.org/XML/1998/namespace=xml",
+		filepath.Join(dir, "first.xsd"), filepath.Join(dir, "second.xsd")}
+	assert.NoError(t, run(args, stderr))
+	data, err := os.ReadFile(filepath.Join(dir, "common", "common.go"))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "// Code generated by caementarii. DO NOT EDIT.\n\npackage common\n"))
+
+	assert.NoError(t, run(append([]string{"-check"}, args...), stderr))
+	assert.Equal(t, "", stderr.String())
+}
+
+func TestRunInvalidFlags(t *testing.T) {
+	stderr := new(bytes.Buffer)
+	assert.Error(t, run([]string{"-decimal", "float32", "a.xsd"}, stderr))
+	assert.Error(t, run([]string{"-ns", "urn:a", "a.xsd"}, stderr))
+	assert.Error(t, run([]string{}, stderr))
+}
@@ -77,7 +77,7 @@ type elementDeclaration struct {
 	// A set of Identity-Constraint Definition components.
 	identityConstraintDefinitions []identityConstraint
 	// A set of Element Declaration components.
-	substitutionGroupAffiliations []elementDeclaration
+	substitutionGroupAffiliations []*elementDeclaration
 	// A subset of {extension, restriction}.
 	substitutionGroupExclusions []string
 	// A subset of {substitution, extension, restriction}.
@@ -85,6 +85,14 @@ type elementDeclaration struct {
 	// An xs:boolean value. Required.
 	abstract bool
 
+	// A Go type name for a generated global element
+	goType string
+	// Element declarations which declare this one in their {substitution group affiliations}.
+	substitutionGroupMembers []*elementDeclaration
+	// The {target namespace} of a local element. The name of a local element is left unqualified, so its field
+	// matches the element in any namespace.
+	namespace string
+
 	annotatedComponent
 }
 
@@ -153,7 +161,7 @@ type complexTypeDefinition struct {
 	// A set of Attribute Use components.
 	attributeUses []*attributeUse
 	// A Wildcard component. Optional.
-	attributeWildcard wildcard
+	attributeWildcard *wildcard
 	// A Content Type property record. Required.
 	contentType complexTypeContentType
 	// A subset of {extension, restriction}.
@@ -161,6 +169,15 @@ type complexTypeDefinition struct {
 	// A sequence of Assertion components.
 	assertions []assertion
 
+	// A Go type name for a named type definition
+	goType string
+	// Named type definitions derived from this one, directly or indirectly, sorted by name.
+	derivedTypes []*complexTypeDefinition
+	// A Go type name of items of mixed content which is kept in document order, see Generator.Mixed.
+	itemType string
+	// A repeated choice whose occurrences are kept in document order, see choiceItem.
+	choice *choiceItem
+
 	typeDefinition
 	annotatedComponent
 }
@@ -210,7 +227,7 @@ type simpleTypeDefinition struct {
 	// A sequence of primitive or ordinary Simple Type Definition components.
 	// Must be present (but may be empty) if {variety} is union, otherwise must be ·absent·.
 	// The sequence may contain any primitive or ordinary simple type definition, but must not contain any special type definitions.
-	numberTypeDefinitions []TypeDefinition
+	memberTypeDefinitions []TypeDefinition
 
 	// A Go type for representing a content
 	goType string
@@ -248,9 +265,9 @@ type attributeGroupDefinition struct {
 	// A name with optional target namespace.
 	name xml.Name
 	// A set of Attribute Use components.
-	attributeUses []attributeUse
+	attributeUses []*attributeUse
 	// A Wildcard component. Optional.
-	attributeWildcard wildcard
+	attributeWildcard *wildcard
 
 	annotatedComponent
 }
@@ -274,13 +291,26 @@ type wildcardNamespaceConstraint struct {
 	disallowedNames []string
 }
 
+// allows reports whether a namespace name is allowed by the constraint, see 3.10.4.3 Wildcard allows Namespace Name.
+// An ·absent· namespace is an empty string.
+func (c wildcardNamespaceConstraint) allows(namespace string) bool {
+	switch c.variety {
+	case "any":
+		return true
+	case "enumeration":
+		return containsString(c.namespaces, namespace)
+	default:
+		return !containsString(c.namespaces, namespace)
+	}
+}
+
 // A model group definition associates a name and optional annotations with a Model Group. By reference to the name, the entire model group can be incorporated by reference into a {term}.
 // Model group definitions are provided primarily for reference from the XML Representation of Complex Type Definition Schema Components (§3.4.2) (see <complexType> and <group>). Thus, model group definitions provide a replacement for some uses of XML's parameter entity facility.
 type modelGroupDefinition struct {
 	// A name with optional target namespace.
 	name xml.Name
 	// A Model Group component. Required.
-	modelGroup modelGroup
+	modelGroup *modelGroup
 
 	annotatedComponent
 }
@@ -325,25 +355,60 @@ type schema struct {
 	// A set of Type Definition components.
 	typeDefinitions map[xml.Name]TypeDefinition
 	// A set of Attribute Declaration components.
-	attributeDeclarations []attributeDeclaration
+	attributeDeclarations map[xml.Name]*attributeDeclaration
 	// A set of Element Declaration components.
 	elementDeclarations map[xml.Name]*elementDeclaration
 	// A set of Attribute Group Definition components.
-	attributeGroupDefinitions []attributeGroupDefinition
+	attributeGroupDefinitions map[xml.Name]*attributeGroupDefinition
 	// A set of Model Group Definition components.
-	modelGroupDefinitions []modelGroupDefinition
+	modelGroupDefinitions map[xml.Name]*modelGroupDefinition
 	// A set of Notation Declaration components.
 	notationDeclarations []notationDeclaration
 	// A set of Identity-Constraint Definition components.
 	identityConstraintDefinitions []identityConstraint
 
+	// Type definitions without a name in the order they were parsed.
+	anonymousTypeDefinitions []TypeDefinition
+
 	xsdSchema *xsd.Schema
+	// Schema documents which make up the schema: the schema document itself, and the documents it includes,
+	// redefines or overrides. Each of them is a schema which shares the components with this one, see
+	// processIncludes.
+	documents []*schema
+	// Top-level components of the schema document. Components replaced by <redefine> or <override> are dropped, and
+	// the replacing components are added to the document which redefines or overrides them.
+	schemaTop []interface{}
+	// Original components of the redefined documents by the names of the components which redefine them.
+	redefinitions map[componentKey]*redefinition
+	// The resolved location of the schema document, and the document which includes, redefines or overrides it.
+	location   string
+	includedBy *schema
+	// An included schema document without a target namespace takes the target namespace of the including one, and
+	// unqualified names it refers to are in that namespace too.
+	chameleon bool
 	// A map of known namespaces
 	prefixMap            map[string]string
 	targetNamespace      string
 	blockDefault         string
 	finalDefault         string
 	attributeFormDefault string
+	elementFormDefault   string
+}
+
+// componentKey is a name of a top-level component in its symbol space. Simple and complex types share the symbol
+// space of types.
+type componentKey struct {
+	space string
+	name  xml.Name
+}
+
+// redefinition is an original component of a redefined schema document. It's parsed when the component which
+// redefines it refers to it.
+type redefinition struct {
+	document *schema
+	node     interface{}
+	// The parsed component, or nil if it's not parsed yet.
+	component interface{}
 }
 
 func checkPrefixNamespaceConstraint(prefix string, ns string) bool {
@@ -373,13 +438,39 @@ func newSchema(s *xsd.Schema) *schema {
 			prefixMap[attr.Name.Local] = attr.Value
 		}
 	}
-	return &schema{
-		xsdSchema:           s,
-		targetNamespace:     s.TargetNamespace,
-		prefixMap:           prefixMap,
-		typeDefinitions:     make(map[xml.Name]TypeDefinition, 0),
-		elementDeclarations: make(map[xml.Name]*elementDeclaration, 0),
+	r := &schema{
+		xsdSchema:                 s,
+		location:                  s.Location,
+		schemaTop:                 s.SchemaTop,
+		redefinitions:             make(map[componentKey]*redefinition),
+		targetNamespace:           s.TargetNamespace,
+		elementFormDefault:        s.ElementFormDefault,
+		prefixMap:                 prefixMap,
+		typeDefinitions:           make(map[xml.Name]TypeDefinition, 0),
+		elementDeclarations:       make(map[xml.Name]*elementDeclaration, 0),
+		modelGroupDefinitions:     make(map[xml.Name]*modelGroupDefinition, 0),
+		attributeGroupDefinitions: make(map[xml.Name]*attributeGroupDefinition, 0),
+		attributeDeclarations:     make(map[xml.Name]*attributeDeclaration, 0),
 	}
+	r.documents = []*schema{r}
+	return r
+}
+
+// newIncludedSchema creates a schema of a schema document included into the schema. The document shares components
+// of the schema, but it has its own namespace prefixes.
+func (s *schema) newIncludedSchema(xs *xsd.Schema) *schema {
+	r := newSchema(xs)
+	if r.targetNamespace == "" {
+		r.targetNamespace = s.targetNamespace
+		r.chameleon = s.targetNamespace != ""
+	}
+	r.typeDefinitions = s.typeDefinitions
+	r.elementDeclarations = s.elementDeclarations
+	r.modelGroupDefinitions = s.modelGroupDefinitions
+	r.attributeGroupDefinitions = s.attributeGroupDefinitions
+	r.attributeDeclarations = s.attributeDeclarations
+	r.documents = nil
+	return r
 }
 
 // resolveQName resolves a QName value into xml.Name struct
@@ -395,6 +486,9 @@ func (s *schema) resolveQName(qname xsd.QName) (name xml.Name) {
 		}
 		name.Local = p[1]
 	}
+	if s.chameleon && name.Space == "" {
+		name.Space = s.targetNamespace
+	}
 	return
 }
 
@@ -428,6 +522,7 @@ type (
 		constrainingFacet
 	}
 
+	// numFacet is a facet which value is a non-negative integer.
 	numFacet struct {
 		// A sequence of Annotation components.
 		annotations []annotation
@@ -439,21 +534,42 @@ type (
 		constrainingFacet
 	}
 
-	fractionDigitsFacet struct {
+	lengthFacet         struct{ numFacet }
+	minLengthFacet      struct{ numFacet }
+	maxLengthFacet      struct{ numFacet }
+	totalDigitsFacet    struct{ numFacet }
+	fractionDigitsFacet struct{ numFacet }
+
+	// boundFacet is a facet which value is a bound of the value space.
+	boundFacet struct {
 		// A sequence of Annotation components.
 		annotations []annotation
-		// An xs:nonNegativeInteger value. Required.
-		value int
+		// A value from the value space. Required.
+		value string
 		// An xs:boolean value. Required.
 		fixed bool
 
-		numFacet
+		constrainingFacet
 	}
 
-	minInclusiveFacet struct {
+	minInclusiveFacet struct{ boundFacet }
+	minExclusiveFacet struct{ boundFacet }
+	maxInclusiveFacet struct{ boundFacet }
+	maxExclusiveFacet struct{ boundFacet }
+
+	enumerationFacet struct {
 		// A sequence of Annotation components.
 		annotations []annotation
-		// A value from the value space. Required.
+		// A set of values from the value space of the {base type definition}. Required.
+		value []string
+
+		constrainingFacet
+	}
+
+	explicitTimezoneFacet struct {
+		// A sequence of Annotation components.
+		annotations []annotation
+		// One of {required, prohibited, optional}. Required.
 		value string
 		// An xs:boolean value. Required.
 		fixed bool
@@ -464,10 +580,9 @@ type (
 	patternFacet struct {
 		// A sequence of Annotation components.
 		annotations []annotation
-		// One of {preserve, replace, collapse}. Required.
-		value string
-		// An xs:boolean value. Required.
-		fixed bool
+		// A non-empty set of regular expressions. A value must match all of them, each one comes from a derivation
+		// step with alternatives of the <pattern> elements of that step.
+		value []string
 
 		constrainingFacet
 	}
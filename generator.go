@@ -3,17 +3,96 @@ package goxsd
 import (
 	"bytes"
 	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
 	"github.com/realmfoo/caementarii/xsd"
 	"go/format"
+	"hash/fnv"
 	"io"
+	"math"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
 type Generator struct {
-	PkgName        string
+	PkgName string
+	// ImportResolver returns a schema document which is imported, included, redefined or overridden. A relative
+	// schemaLocation is resolved against the Location of the referring document if it's known, see package resolver
+	// for a ready-made one.
 	ImportResolver func(namespace string, schemaLocation string) (*xsd.Schema, error)
-	schemas        map[string]*schema
+	// Decimal selects a Go type of xs:decimal and the types derived from it which are not integers.
+	Decimal DecimalType
+	// Integer selects a Go type of xs:integer and the types derived from it which have no lower bound.
+	Integer IntegerType
+	// Mixed selects a representation of complex types with mixed content.
+	Mixed MixedContent
+	// ImportPath is the import path of the package which the schema is generated into by GeneratePackages.
+	ImportPath string
+	// Packages maps target namespaces to import paths of the packages which GeneratePackages generates their
+	// components into. A namespace which is not mapped gets a package under ImportPath named after the namespace.
+	Packages map[string]string
+	// Names overrides Go names of components, and of fields of elements and attributes, by their XML names. An
+	// override which is not an exported identifier is sanitized like an XML name, and a number is appended to one
+	// which is taken.
+	Names   map[xml.Name]string
+	schemas map[string]*schema
+	// Original components which are referred to by components being redefined, see Generator.resolveRedefined.
+	redefining map[componentKey]*redefinition
+}
+
+// DecimalType is a Go type which represents xs:decimal.
+type DecimalType int
+
+const (
+	// DecimalFloat64 maps xs:decimal to float64, which can't represent most of decimal fractions exactly.
+	DecimalFloat64 DecimalType = iota
+	// DecimalExact maps xs:decimal to xs.Decimal, which keeps a value and its number of fraction digits exactly.
+	DecimalExact
+	// DecimalBigRat maps xs:decimal to xs.Rat, which embeds a *big.Rat. A *big.Rat itself isn't used, since its
+	// MarshalText encodes a fraction like 21/2, which is not a valid xs:decimal, and its UnmarshalText accepts
+	// fractions and exponents. xs.Rat encodes the lexical form of xs:decimal instead.
+	DecimalBigRat
+)
+
+// IntegerType is a Go type which represents xs:integer. Bounded integer types are always represented by Go integer
+// types of their size, e.g. xs:int by int32.
+type IntegerType int
+
+const (
+	// IntegerInt maps xs:integer to int, which can't represent integers beyond 64 bits.
+	IntegerInt IntegerType = iota
+	// IntegerBigInt maps xs:integer to xs.Int, which is a *big.Int encoded as an integer.
+	IntegerBigInt
+)
+
+// MixedContent is a representation of complex types with mixed content.
+type MixedContent int
+
+const (
+	// MixedElements represents mixed content as element-only content, so the text between elements is dropped.
+	MixedElements MixedContent = iota
+	// MixedOrdered represents mixed content as a slice of items in document order, each of which is either text or
+	// one of the child elements. It applies to named complex types and anonymous types of top-level elements, other
+	// types are represented as element-only content.
+	MixedOrdered
+)
+
+// goTypes returns Go types of built-in types which are replaced according to the options of the generator.
+func (g *Generator) goTypes() map[string]string {
+	goTypes := map[string]string{}
+	switch g.Decimal {
+	case DecimalFloat64:
+		goTypes["xs.Decimal"] = "float64"
+	case DecimalBigRat:
+		goTypes["xs.Decimal"] = "xs.Rat"
+	}
+	if g.Integer == IntegerBigInt {
+		goTypes["int"] = "xs.Int"
+	}
+	return goTypes
 }
 
 func (g *Generator) Generate(s *xsd.Schema, o io.Writer) error {
@@ -22,166 +101,3228 @@ func (g *Generator) Generate(s *xsd.Schema, o io.Writer) error {
 		return err
 	}
 
-	file := toGoFile(g.PkgName, schema)
+	file, err := toGoFile(g.PkgName, schema, g.goTypes(), g.Mixed, g.Names, nil)
+	if err != nil {
+		return err
+	}
+	formatted, err := formatFile(file)
+	o.Write(formatted)
+	return err
+}
+
+// formatFile returns a formatted source of a file, or the source as is if it could not be formatted.
+func formatFile(file *File) ([]byte, error) {
 	w := new(bytes.Buffer)
 	file.Write(w)
 
 	formatted, err := format.Source(w.Bytes())
 	if err != nil {
-		o.Write(w.Bytes())
-		return err
+		return w.Bytes(), err
 	}
-
-	o.Write(formatted)
-	return nil
+	return formatted, nil
 }
 
 type xmlNames []xml.Name
 
-func (a xmlNames) Len() int           { return len(a) }
-func (a xmlNames) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
-func (a xmlNames) Less(i, j int) bool { return a[i].Local < a[j].Local }
+func (a xmlNames) Len() int      { return len(a) }
+func (a xmlNames) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a xmlNames) Less(i, j int) bool {
+	if a[i].Local != a[j].Local {
+		return a[i].Local < a[j].Local
+	}
+	return a[i].Space < a[j].Space
+}
 
-func toGoFile(pkgName string, schema *schema) *File {
-	f := &File{PkgName: pkgName}
+func toGoFile(pkgName string, schema *schema, goTypes map[string]string, mixed MixedContent, overrides map[xml.Name]string, packages map[string]*goPackage) (*File, error) {
+	f := &File{PkgName: pkgName, goTypes: goTypes, packages: packages, names: newNaming(overrides)}
 
-	// Sort elements by local name
+	// Sort elements by local name, and then by namespace
 	keys := make([]xml.Name, 0, len(schema.elementDeclarations))
 	for k := range schema.elementDeclarations {
 		keys = append(keys, k)
 	}
 	sort.Sort(xmlNames(keys))
 
-	// Generate types in alphabetical order
+	// Sort named complex types by local name, and then by namespace
+	typeKeys := make([]xml.Name, 0, len(schema.typeDefinitions))
+	for k, typeDef := range schema.typeDefinitions {
+		if _, ok := typeDef.(*complexTypeDefinition); ok {
+			typeKeys = append(typeKeys, k)
+		}
+	}
+	sort.Sort(xmlNames(typeKeys))
+
+	// Name elements and complex types. Elements own their names, so a type that has the same name as an element gets a
+	// suffix. A number is appended to a name which is still taken, like a name of the same local name in another
+	// namespace.
+	elementNames := make(map[string]bool, len(keys))
 	for _, key := range keys {
 		elm := schema.elementDeclarations[key]
-		typeName := makeTypeName(elm.name)
-		decl := &TypeDecl{
-			Name: &Name{Value: typeName},
-			Type: createElementDeclType(f, elm, typeName),
+		elm.goType = f.names.declare(makeTypeName(f, key))
+		elementNames[elm.goType] = true
+	}
+	for _, key := range typeKeys {
+		typeName := makeTypeName(f, key)
+		if elementNames[typeName] {
+			typeName += "Type"
+		}
+		schema.typeDefinitions[key].(*complexTypeDefinition).goType = f.names.declare(typeName)
+	}
+
+	// Collect types derived from every named complex type, since they could replace the base type by xsi:type. Types
+	// of other packages can't be replaced by the types of this one.
+	for _, key := range typeKeys {
+		typeDef := schema.typeDefinitions[key].(*complexTypeDefinition)
+		base, ok := typeDef.baseTypeDefinition.(*complexTypeDefinition)
+		for ok && base != anyType {
+			if base.goType != "" && !isQualifiedGoType(base.goType) {
+				base.derivedTypes = append(base.derivedTypes, typeDef)
+			}
+			base, ok = base.baseTypeDefinition.(*complexTypeDefinition)
+		}
+	}
+
+	// Name restricted and union simple types. An anonymous type is named after its context, and a number is appended
+	// if the name is already taken.
+	simpleTypes := declaredSimpleTypes(schema)
+	for _, typeDef := range simpleTypes {
+		typeName := makeTypeName(f, simpleTypeName(typeDef))
+		if elementNames[typeName] {
+			typeName += "Type"
+		}
+		typeDef.goType = f.names.declare(typeName)
+	}
+
+	// Name items of mixed content which is kept in document order.
+	if mixed == MixedOrdered {
+		mixedTypes := make([]*complexTypeDefinition, 0)
+		itemNames := make([]string, 0)
+		for _, key := range typeKeys {
+			typeDef := schema.typeDefinitions[key].(*complexTypeDefinition)
+			mixedTypes, itemNames = append(mixedTypes, typeDef), append(itemNames, typeDef.goType+"Item")
+		}
+		for _, key := range keys {
+			elm := schema.elementDeclarations[key]
+			if typeDef, ok := elm.typeDefinition.(*complexTypeDefinition); ok && typeDef.goType == "" && typeDef != anyType {
+				mixedTypes, itemNames = append(mixedTypes, typeDef), append(itemNames, elm.goType+"Item")
+			}
+		}
+		for i, typeDef := range mixedTypes {
+			if typeDef.contentType.variety != "mixed" {
+				continue
+			}
+			typeDef.itemType = f.names.declare(itemNames[i])
+		}
+	}
+
+	// Name items of repeated choices which are kept in document order. An item is named after the named type or the
+	// top-level element it's found in.
+	choiceTypes := make([]*complexTypeDefinition, 0)
+	for _, key := range typeKeys {
+		typeDef := schema.typeDefinitions[key].(*complexTypeDefinition)
+		if p := itemChoice(typeDef); p != nil {
+			typeDef.choice = &choiceItem{particle: p, goType: f.names.declare(typeDef.goType + "Choice")}
+			choiceTypes = append(choiceTypes, typeDef)
+		}
+	}
+	for _, key := range keys {
+		elm := schema.elementDeclarations[key]
+		if typeDef, ok := elm.typeDefinition.(*complexTypeDefinition); ok && typeDef.goType == "" && typeDef != anyType {
+			if p := itemChoice(typeDef); p != nil {
+				typeDef.choice = &choiceItem{particle: p, goType: f.names.declare(elm.goType + "Choice")}
+				choiceTypes = append(choiceTypes, typeDef)
+			}
+		}
+	}
+
+	// Name element wildcards which are generated as types of their own. A wildcard is named after the named type or
+	// the top-level element it's found in, and the wildcard of xs:anyType is named Any.
+	wildcards := make([]ownedWildcard, 0)
+	visited := make(map[interface{}]bool)
+	for _, key := range typeKeys {
+		typeDef := schema.typeDefinitions[key].(*complexTypeDefinition)
+		wildcards = append(wildcards, collectWildcards(typeDef.contentType.particle, typeDef.goType, visited)...)
+	}
+	for _, key := range keys {
+		elm := schema.elementDeclarations[key]
+		wildcards = append(wildcards, elementWildcards(elm, elm.goType, visited)...)
+	}
+	f.wildcards = make(map[*wildcard]string, len(wildcards))
+	for _, w := range wildcards {
+		f.wildcards[w.wildcard] = f.names.declare(w.owner + "Any")
+	}
+
+	decls := make(map[string][]Decl, len(keys)+len(typeKeys))
+	for _, w := range wildcards {
+		decls[f.wildcards[w.wildcard]] = createWildcardDecls(f, schema, keys, w.wildcard)
+	}
+	for _, key := range keys {
+		elm := schema.elementDeclarations[key]
+		if typeDef, ok := elm.typeDefinition.(*complexTypeDefinition); ok && typeDef.goType == "" && typeDef.itemType != "" {
+			decls["xmlTokens"] = createXMLTokensDecls(f)
+		}
+		decls[elm.goType] = createElementDecls(f, elm, elm.goType)
+		decls[elm.goType] = append(decls[elm.goType], createSubstitutionGroupDecls(f, elm)...)
+	}
+	for _, key := range typeKeys {
+		typeDef := schema.typeDefinitions[key].(*complexTypeDefinition)
+		if typeDef.itemType != "" {
+			decls[typeDef.goType] = createMixedDecls(f, typeDef.goType, typeDef, nil)
+			decls[typeDef.goType] = append(decls[typeDef.goType], createPolymorphicDecls(f, typeDef)...)
+			if len(typeDef.derivedTypes) > 0 {
+				decls["xsiType"] = createXsiTypeDecls(f)
+			}
+			decls["xmlTokens"] = createXMLTokensDecls(f)
+			continue
+		}
+		decls[typeDef.goType] = append([]Decl{
+			&TypeDecl{
+				Name: &Name{Value: typeDef.goType},
+				Type: createComplexTypeDeclType(f, nil, typeDef),
+			},
+		}, createChoiceDecls(f, typeDef.goType, typeDef, false)...)
+		decls[typeDef.goType] = append(decls[typeDef.goType], createAllDecls(f, typeDef.goType, typeDef, false)...)
+		decls[typeDef.goType] = append(decls[typeDef.goType], createSubstitutionDecls(f, typeDef.goType, typeDef, false)...)
+		decls[typeDef.goType] = append(decls[typeDef.goType], createAnyAttrDecls(f, typeDef.goType, typeDef)...)
+		decls[typeDef.goType] = append(decls[typeDef.goType], createPolymorphicDecls(f, typeDef)...)
+		if len(typeDef.derivedTypes) > 0 {
+			decls["xsiType"] = createXsiTypeDecls(f)
+		}
+	}
+	for _, typeDef := range choiceTypes {
+		decls[typeDef.choice.goType] = createChoiceItemDecls(f, typeDef)
+	}
+	for _, typeDef := range simpleTypes {
+		switch typeDef.variety {
+		case "union":
+			decls[typeDef.goType] = createUnionTypeDecls(f, typeDef)
+		case "list":
+			decls[typeDef.goType] = createListTypeDecls(f, typeDef)
+		default:
+			restricted, err := createRestrictedTypeDecls(f, typeDef)
+			if err != nil {
+				return nil, err
+			}
+			decls[typeDef.goType] = restricted
+		}
+	}
+
+	// Generate types in alphabetical order
+	names := make([]string, 0, len(decls))
+	for name := range decls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f.DeclList = append(f.DeclList, decls[name]...)
+	}
+
+	return f, nil
+}
+
+// makeTypeName returns a Go name of a component, which is not yet declared in the file, see naming.
+func makeTypeName(f *File, name xml.Name) string {
+	return f.names.name(name)
+}
+
+// createElementDecls creates declarations for a top-level element.
+func createElementDecls(f *File, elm *elementDeclaration, typeName string) []Decl {
+	if typeDef, ok := elm.typeDefinition.(*complexTypeDefinition); ok && typeDef.goType == "" {
+		if typeDef.itemType != "" {
+			return createMixedDecls(f, typeName, typeDef, elm)
 		}
+		// An anonymous complex type is declared in place with the XMLName field.
+		decls := append([]Decl{
+			&TypeDecl{
+				Name: &Name{Value: typeName},
+				Type: createComplexTypeDeclType(f, elm, typeDef),
+			},
+		}, createChoiceDecls(f, typeName, typeDef, true)...)
+		decls = append(decls, createAllDecls(f, typeName, typeDef, true)...)
+		decls = append(decls, createSubstitutionDecls(f, typeName, typeDef, true)...)
+		return append(decls, createAnyAttrDecls(f, typeName, typeDef)...)
+	}
+
+	// An element of a named or a simple type is declared as a new type based on its type definition and carries its
+	// name in marshaling methods.
+	underlying := createElementDeclType(f, elm)
+	nsName := "ns" + typeName + "QName"
+	// A type other than a plain name, like a slice of a list type, is parenthesized in conversions.
+	typeExpr := exprString(underlying)
+	conversion := typeExpr
+	if _, ok := underlying.(*Name); !ok {
+		conversion = "(" + typeExpr + ")"
+	}
 
-		f.DeclList = append(f.DeclList, decl)
+	f.Require("encoding/xml")
+	return []Decl{
+		&VarDecl{
+			NameList: []*Name{{Value: nsName}},
+			Values:   &BasicLit{Value: `xml.Name{Space: "` + elm.name.Space + `", Local: "` + elm.name.Local + `"}`},
+		},
+		&TypeDecl{
+			Name: &Name{Value: typeName},
+			Type: underlying,
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: []Stmt{
+				&ReturnStmt{Results: &BasicLit{Value: "d.DecodeElement((*" + typeExpr + ")(t), &start)"}},
+			}},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "MarshalXML"},
+			Type: marshalXMLFuncType(),
+			Body: &BlockStmt{List: []Stmt{
+				&AssignStmt{Lhs: &Name{Value: "start.Name"}, Rhs: &Name{Value: nsName}},
+				&ReturnStmt{Results: &BasicLit{Value: "e.EncodeElement(" + conversion + "(t), start)"}},
+			}},
+		},
 	}
+}
 
-	return f
+// unmarshalXMLFuncType returns a signature of the xml.Unmarshaler's method.
+func unmarshalXMLFuncType() *FuncType {
+	return &FuncType{
+		ParamList: []*Field{
+			{Name: &Name{Value: "d"}, Type: &PointerType{Elem: &BasicLit{Value: "xml.Decoder"}}},
+			{Name: &Name{Value: "start"}, Type: &BasicLit{Value: "xml.StartElement"}},
+		},
+		ResultList: []*Field{{Type: &Name{Value: "error"}}},
+	}
 }
 
-func makeTypeName(name xml.Name) string {
-	return strings.Title(name.Local)
+// marshalXMLFuncType returns a signature of the xml.Marshaler's method.
+func marshalXMLFuncType() *FuncType {
+	return &FuncType{
+		ParamList: []*Field{
+			{Name: &Name{Value: "e"}, Type: &PointerType{Elem: &BasicLit{Value: "xml.Encoder"}}},
+			{Name: &Name{Value: "start"}, Type: &BasicLit{Value: "xml.StartElement"}},
+		},
+		ResultList: []*Field{{Type: &Name{Value: "error"}}},
+	}
 }
 
-func createElementDeclType(f *File, elm *elementDeclaration, typeName string) Expr {
+// createElementDeclType returns a Go type of an element's content.
+func createElementDeclType(f *File, elm *elementDeclaration) Expr {
 	var elmType Expr
 
 	switch typeDef := elm.typeDefinition.(type) {
 	case *simpleTypeDefinition:
-		var goType string
-		if typeDef.primitiveTypeDefinition != nil {
-			goType = typeDef.primitiveTypeDefinition.goType
+		elmType = &Name{Value: requireGoType(f, simpleGoType(typeDef))}
+	case *complexTypeDefinition:
+		if len(typeDef.derivedTypes) > 0 {
+			// Any of derived types could be used in place of the element's type.
+			elmType = &Name{Value: requireGoType(f, polymorphicTypeName(typeDef))}
+		} else if typeDef.goType != "" {
+			elmType = &Name{Value: requireGoType(f, typeDef.goType)}
 		} else {
-			goType = "string"
+			elmType = createComplexTypeDeclType(f, nil, typeDef)
 		}
+	}
+
+	return elmType
+}
 
-		elmType = &Name{Value: goType}
+// createElementRefType returns a Go type of an element used as a particle. A global element which is generated as a
+// Go type is referred by its type name.
+func createElementRefType(f *File, elm *elementDeclaration) Expr {
+	if elm.goType != "" {
+		return &Name{Value: requireGoType(f, elm.goType)}
+	}
+	return createElementDeclType(f, elm)
+}
 
-		// If it's a root type then add a namespace variable?
-		if typeName != "" {
-			f.Require("encoding/xml")
-			f.DeclList = append(f.DeclList, &VarDecl{
-				NameList: []*Name{{Value: "ns" + typeName}},
-				Values:   &BasicLit{Value: `xml.Name{Space: "` + elm.name.Space + `", Local: "` + elm.name.Local + `"}`},
-			})
+// simpleGoType returns a Go type of a simple type definition, which is the Go type of the nearest ancestor type
+// definition which has one. A list type is a slice of its item type.
+func simpleGoType(typeDef *simpleTypeDefinition) string {
+	for t := typeDef; t != nil; {
+		if t.goType != "" {
+			return t.goType
 		}
-	case *complexTypeDefinition:
-		elmType = createComplexTypeDeclType(f, elm, typeName, typeDef)
+		if t.itemTypeDefinition != nil {
+			return "[]" + simpleGoType(t.itemTypeDefinition)
+		}
+		base, ok := t.baseTypeDefinition.(*simpleTypeDefinition)
+		if !ok {
+			break
+		}
+		t = base
 	}
+	return "string"
+}
 
-	return elmType
+// runtimePackage is the import path of the package with Go types of the built-in types which have no suitable
+// counterpart in the standard library.
+const runtimePackage = "github.com/realmfoo/caementarii/xs"
+
+// isRuntimeGoType reports whether a Go type comes from the runtime package.
+func isRuntimeGoType(goType string) bool {
+	return strings.HasPrefix(goType, "xs.")
 }
 
-func createComplexTypeDeclType(f *File, elm *elementDeclaration, typeName string, typeDef *complexTypeDefinition) *StructType {
-	s := &StructType{
-		FieldList: []*Field{},
+// requireGoType replaces a Go type of a built-in type according to the options of the file, adds an import of the
+// package of the type to the file and returns the type.
+func requireGoType(f *File, goType string) string {
+	if t, ok := f.goTypes[goType]; ok {
+		goType = t
+	}
+	if isRuntimeGoType(goType) {
+		f.Require(runtimePackage)
+	}
+	if i := strings.LastIndex(goType, "."); i >= 0 {
+		if pkg, ok := f.packages[strings.TrimLeft(goType[:i], "[]*")]; ok {
+			if pkg.qualifier == pkg.name {
+				f.Require(pkg.importPath)
+			} else {
+				f.RequireNamed(pkg.importPath, pkg.qualifier)
+			}
+		}
 	}
+	return goType
+}
 
-	// If it's a root type definition then add an XMLName field.
-	if typeName != "" {
-		f.Require("encoding/xml")
-		s.FieldList = append(s.FieldList,
-			&Field{
-				Name: &Name{Value: "XMLName"},
-				Type: &BasicLit{Value: `xml.Name`},
-				Tags: map[string]string{
-					"xml": xmlNameTag(elm.name),
-				},
-			},
-		)
+// dateTimeTypes parse lexical forms of the date/time runtime types into their canonical forms and time instants.
+var dateTimeTypes = map[string]func(string) (string, time.Time, error){
+	"xs.DateTime": func(s string) (string, time.Time, error) {
+		v, err := xs.ParseDateTime(s)
+		return v.String(), v.Time, err
+	},
+	"xs.DateTimeStamp": func(s string) (string, time.Time, error) {
+		v, err := xs.ParseDateTimeStamp(s)
+		return v.String(), v.Time, err
+	},
+	"xs.Date": func(s string) (string, time.Time, error) {
+		v, err := xs.ParseDate(s)
+		return v.String(), v.Time, err
+	},
+	"xs.Time": func(s string) (string, time.Time, error) {
+		v, err := xs.ParseTime(s)
+		return v.String(), v.Time, err
+	},
+	"xs.GYearMonth": func(s string) (string, time.Time, error) {
+		v, err := xs.ParseGYearMonth(s)
+		return v.String(), v.Time, err
+	},
+	"xs.GYear": func(s string) (string, time.Time, error) {
+		v, err := xs.ParseGYear(s)
+		return v.String(), v.Time, err
+	},
+	"xs.GMonthDay": func(s string) (string, time.Time, error) {
+		v, err := xs.ParseGMonthDay(s)
+		return v.String(), v.Time, err
+	},
+	"xs.GDay": func(s string) (string, time.Time, error) {
+		v, err := xs.ParseGDay(s)
+		return v.String(), v.Time, err
+	},
+	"xs.GMonth": func(s string) (string, time.Time, error) {
+		v, err := xs.ParseGMonth(s)
+		return v.String(), v.Time, err
+	},
+}
+
+// runtimeCanonical returns the canonical form of a value of a runtime type. It reports false if the value is not
+// valid for the type.
+func runtimeCanonical(goType string, value string) (string, bool) {
+	if parse, ok := dateTimeTypes[goType]; ok {
+		canonical, _, err := parse(value)
+		return canonical, err == nil
+	}
+	switch goType {
+	case "xs.Duration":
+		d, err := xs.ParseDuration(value)
+		return d.String(), err == nil
+	case "xs.Decimal", "xs.Rat":
+		d, err := xs.ParseDecimal(value)
+		return d.Canonical(), err == nil
+	case "xs.Int":
+		i, err := xs.ParseInt(value)
+		return i.String(), err == nil
+	case "xs.Base64Binary":
+		var b xs.Base64Binary
+		err := b.UnmarshalText([]byte(value))
+		return b.String(), err == nil
+	case "xs.HexBinary":
+		var b xs.HexBinary
+		err := b.UnmarshalText([]byte(value))
+		return b.String(), err == nil
 	}
+	return "", false
+}
 
-	for _, attr := range typeDef.attributeUses {
-		f.Require("encoding/xml")
-		var attrType Expr
-		tags := xmlNameTag(attr.attributeDeclaration.name) + ",attr"
-		attrType = &BasicLit{Value: attr.attributeDeclaration.typeDefinition.goType}
-		if !attr.required {
-			tags += ",omitempty"
-			attrType = &PointerType{Elem: attrType}
+// goTimeLiteral returns a Go expression which makes a time instant.
+func goTimeLiteral(t time.Time) string {
+	loc := "time.UTC"
+	if _, offset := t.Zone(); offset != 0 {
+		loc = "time.FixedZone(\"\", " + strconv.Itoa(offset) + ")"
+	}
+	return fmt.Sprintf("time.Date(%d, %d, %d, %d, %d, %d, %d, %s)",
+		t.Year(), int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+}
+
+// builtinGoType returns a Go type of the nearest built-in ancestor of a simple type definition.
+func builtinGoType(typeDef *simpleTypeDefinition) string {
+	if t := builtinAncestor(typeDef); t != nil {
+		return simpleGoType(t)
+	}
+	return "string"
+}
+
+// builtinAncestor returns the nearest built-in simple type definition among the type definition and its ancestors.
+func builtinAncestor(typeDef *simpleTypeDefinition) *simpleTypeDefinition {
+	for t := typeDef; t != nil; {
+		if xmlTypes[t.name] == TypeDefinition(t) {
+			return t
+		}
+		base, ok := t.baseTypeDefinition.(*simpleTypeDefinition)
+		if !ok {
+			break
+		}
+		t = base
+	}
+	return nil
+}
+
+// declaredSimpleTypes returns simple type definitions of the schema which get Go types of their own, see
+// hasOwnGoType. Named types come first in alphabetical order, then anonymous types in the order they were parsed.
+func declaredSimpleTypes(schema *schema) []*simpleTypeDefinition {
+	keys := make([]xml.Name, 0, len(schema.typeDefinitions))
+	for k, typeDef := range schema.typeDefinitions {
+		if t, ok := typeDef.(*simpleTypeDefinition); ok && hasOwnGoType(t) {
+			keys = append(keys, k)
 		}
-		s.FieldList = append(s.FieldList,
-			&Field{
-				Name: &Name{Value: makeTypeName(attr.attributeDeclaration.name)},
-				Type: attrType,
-				Tags: map[string]string{
-					"xml": tags,
-				},
-			},
-		)
 	}
+	sort.Sort(xmlNames(keys))
 
-	p := typeDef.contentType.particle
-	if p != nil {
-		switch term := p.term.(type) {
-		case *modelGroup:
-			// sequence
-			if term.compositor == "sequence" {
-				s.FieldList = append(s.FieldList, createSequenceDecls(term, f)...)
+	types := make([]*simpleTypeDefinition, 0, len(keys))
+	for _, k := range keys {
+		types = append(types, schema.typeDefinitions[k].(*simpleTypeDefinition))
+	}
+	for _, doc := range schema.documents {
+		for _, typeDef := range doc.anonymousTypeDefinitions {
+			if t, ok := typeDef.(*simpleTypeDefinition); ok && hasOwnGoType(t) {
+				types = append(types, t)
 			}
 		}
 	}
-	return s
+	return types
 }
 
-func createSequenceDecls(term *modelGroup, f *File) []*Field {
-	fields := make([]*Field, 0)
-	for _, particle := range term.particles {
-		switch tt := particle.term.(type) {
-		case *elementDeclaration:
-			dt := createElementDeclType(f, tt, "")
-			if particle.maxOccurs > 1 {
-				dt = &SliceType{Elem: dt}
-			} else if particle.minOccurs == 0 {
-				dt = &PointerType{Elem: dt}
-			}
-			fields = append(fields,
-				&Field{
-					Name: &Name{Value: makeTypeName(tt.name)},
-					Type: dt,
-					Tags: map[string]string{
-						"xml": xmlNameTag(tt.name),
-					},
-				},
-			)
+// hasOwnGoType reports whether a simple type definition gets a Go type of its own: it declares constraining facets,
+// or it's a union, or it's a list which is not derived by restriction. An anonymous base type of a restriction which
+// gets a Go type of its own doesn't, since nothing else refers to it.
+func hasOwnGoType(typeDef *simpleTypeDefinition) bool {
+	if c, ok := typeDef.context.(*simpleTypeDefinition); ok && typeDef.name.Local == "" && c.baseTypeDefinition == typeDef && hasOwnGoType(c) {
+		return false
+	}
+	switch typeDef.variety {
+	case "union":
+		return true
+	case "list":
+		return typeDef.itemTypeDefinition != nil && (typeDef.baseTypeDefinition == anySimpleType || declaresFacets(typeDef))
+	}
+	return declaresFacets(typeDef)
+}
+
+// declaresFacets reports whether the type definition has a constraining facet which is not inherited from its base
+// type definition. The whiteSpace facet doesn't count, as it doesn't constrain a value.
+func declaresFacets(typeDef *simpleTypeDefinition) bool {
+	if typeDef.variety == "union" {
+		return false
+	}
+	base, _ := typeDef.baseTypeDefinition.(*simpleTypeDefinition)
+	for _, f := range typeDef.facets {
+		if _, ok := f.(*whiteSpaceFacet); ok {
+			continue
+		}
+		if base == nil || !hasFacet(base.facets, f) {
+			return true
 		}
 	}
-	return fields
+	return false
 }
 
-func xmlNameTag(name xml.Name) string {
-	xn := ""
-	if name.Space != "" {
-		xn += name.Space + " "
+func hasFacet(facets []ConstrainingFacet, facet ConstrainingFacet) bool {
+	for _, f := range facets {
+		if f == facet {
+			return true
+		}
+	}
+	return false
+}
+
+func enumerationOf(facets []ConstrainingFacet) *enumerationFacet {
+	for _, f := range facets {
+		if e, ok := f.(*enumerationFacet); ok {
+			return e
+		}
+	}
+	return nil
+}
+
+func whiteSpaceOf(typeDef *simpleTypeDefinition) string {
+	for _, f := range typeDef.facets {
+		if w, ok := f.(*whiteSpaceFacet); ok {
+			return w.value
+		}
+	}
+	return "preserve"
+}
+
+// simpleTypeName returns a name of a simple type definition, or the name of its context if the type is anonymous.
+func simpleTypeName(typeDef *simpleTypeDefinition) xml.Name {
+	if typeDef.name.Local != "" {
+		return typeDef.name
+	}
+	switch c := typeDef.context.(type) {
+	case *elementDeclaration:
+		return c.name
+	case *attributeDeclaration:
+		return c.name
+	case *simpleTypeDefinition:
+		return simpleTypeName(c)
+	case *complexTypeDefinition:
+		// The content of a complex type with simple content.
+		if c.name.Local != "" {
+			return c.name
+		}
+		if elm, ok := c.context.(*elementDeclaration); ok {
+			return elm.name
+		}
+	}
+	return xml.Name{Local: "value"}
+}
+
+// createRestrictedTypeDecls creates a type for a simple type which declares constraining facets. An enumerated type gets
+// a constant for each of its values and an IsValid method. Other facets are checked by a Validate method, which
+// is also called on decoding.
+func createRestrictedTypeDecls(f *File, typeDef *simpleTypeDefinition) ([]Decl, error) {
+	typeName := typeDef.goType
+	underlying := requireGoType(f, builtinGoType(typeDef))
+
+	// Facets of a built-in type are represented by its Go type.
+	facets := make([]ConstrainingFacet, 0, len(typeDef.facets))
+	builtin := builtinAncestor(typeDef)
+	for _, facet := range typeDef.facets {
+		if builtin == nil || !hasFacet(builtin.facets, facet) {
+			facets = append(facets, facet)
+		}
+	}
+
+	// A verb and a value to format the value in errors.
+	verb, value := "%v", "t"
+	if underlying == "string" {
+		verb, value = "%q", "string(t)"
+	} else if isRuntimeGoType(underlying) {
+		verb, value = "%q", underlying+"(t).String()"
+	}
+	fail := func(subject string, format string, args ...string) string {
+		return "return fmt.Errorf(" + strconv.Quote(typeName+": "+format) + ", " + strings.Join(append([]string{subject}, args...), ", ") + ")"
+	}
+
+	var decls []Decl
+	var validate []Stmt
+	var parse []Stmt
+
+	// Patterns are compiled once.
+	var patterns []string
+	for _, facet := range facets {
+		if p, ok := facet.(*patternFacet); ok {
+			for _, v := range p.value {
+				re, err := translatePattern(v)
+				if err != nil {
+					return nil, fmt.Errorf("simple type %s: %w", xmlNameAsString(simpleTypeName(typeDef)), err)
+				}
+				f.Require("regexp")
+				name := "pattern" + typeName
+				if len(patterns) > 0 {
+					name += strconv.Itoa(len(patterns) + 1)
+				}
+				patterns = append(patterns, name)
+				decls = append(decls, &VarDecl{
+					NameList: []*Name{{Value: name}},
+					Values:   &BasicLit{Value: "regexp.MustCompile(" + goStringLiteral(re) + ")"},
+				})
+
+				msg := " does not match the pattern " + strings.ReplaceAll(v, "%", "%%")
+				if underlying == "string" {
+					validate = append(validate, &ExprStmt{X: &BasicLit{
+						Value: "if !" + name + ".MatchString(string(t)) {\n" + fail(value, verb+msg) + "\n}",
+					}})
+				} else {
+					// A pattern constrains the lexical space, so it's checked on decoding only. Whitespace of
+					// other types than strings is collapsed, so the pattern is matched without leading and
+					// trailing whitespace.
+					parse = append(parse, &ExprStmt{X: &BasicLit{
+						Value: "if !" + name + ".MatchString(s) {\n" + fail("text", "%q"+msg) + "\n}",
+					}})
+				}
+			}
+		}
+	}
+
+	decls = append(decls, &TypeDecl{Name: &Name{Value: typeName}, Type: &Name{Value: underlying}})
+
+	if underlying == "xs.Rat" {
+		// Arithmetic on big.Rat could make a number which is not a decimal.
+		validate = append(validate, &ExprStmt{X: &BasicLit{
+			Value: "if !xs.Rat(t).IsDecimal() {\n" + fail(value, verb+" has no finite decimal representation") + "\n}",
+		}})
+	}
+
+	if e := enumerationOf(facets); e != nil {
+		if isRuntimeGoType(underlying) {
+			decls = append(decls, createRuntimeEnumerationDecls(typeName, underlying, e))
+		} else {
+			decls = append(decls, createEnumerationDecls(f, typeName, underlying, e)...)
+		}
+		validate = append(validate, &ExprStmt{X: &BasicLit{
+			Value: "if !t.IsValid() {\n" + fail(value, verb+" is not a valid value") + "\n}",
+		}})
+	}
+
+	for _, facet := range facets {
+		switch facet := facet.(type) {
+		case *lengthFacet, *minLengthFacet, *maxLengthFacet:
+			// The length of a string is measured in characters, and the length of binary data in octets.
+			length := "utf8.RuneCountInString(string(t))"
+			switch underlying {
+			case "string":
+				f.Require("unicode/utf8")
+			case "xs.Base64Binary", "xs.HexBinary":
+				length = "len(t)"
+			default:
+				continue
+			}
+			var cond, msg string
+			switch facet := facet.(type) {
+			case *lengthFacet:
+				cond, msg = "n != "+strconv.Itoa(facet.value), "length of "+verb+" is %d, but it must be "+strconv.Itoa(facet.value)
+			case *minLengthFacet:
+				cond, msg = "n < "+strconv.Itoa(facet.value), "length of "+verb+" is %d, but it must be at least "+strconv.Itoa(facet.value)
+			case *maxLengthFacet:
+				cond, msg = "n > "+strconv.Itoa(facet.value), "length of "+verb+" is %d, but it must be at most "+strconv.Itoa(facet.value)
+			}
+			validate = append(validate, &ExprStmt{X: &BasicLit{
+				Value: "if n := " + length + "; " + cond + " {\n" + fail(value, msg, "n") + "\n}",
+			}})
+
+		case *minInclusiveFacet, *minExclusiveFacet, *maxInclusiveFacet, *maxExclusiveFacet:
+			if parse, ok := dateTimeTypes[underlying]; ok {
+				if cond, msg, ok := dateTimeBound(parse, facet); ok {
+					f.Require("time")
+					validate = append(validate, &ExprStmt{X: &BasicLit{
+						Value: "if " + cond + " {\n" + fail(value, verb+" "+msg) + "\n}",
+					}})
+				}
+				continue
+			}
+			if d := decimalExpr(underlying); d != "" {
+				if cond, msg, ok := decimalBound(d, facet); ok {
+					validate = append(validate, &ExprStmt{X: &BasicLit{
+						Value: "if " + cond + " {\n" + fail(value, verb+" "+msg) + "\n}",
+					}})
+				}
+				continue
+			}
+			if underlying == "xs.Duration" {
+				if cond, msg, ok := durationBound(facet); ok {
+					validate = append(validate, &ExprStmt{X: &BasicLit{
+						Value: "if " + cond + " {\n" + fail(value, verb+" "+msg) + "\n}",
+					}})
+				}
+				continue
+			}
+			if !isNumericGoType(underlying) {
+				continue
+			}
+			var op, msg string
+			var bound string
+			switch facet := facet.(type) {
+			case *minInclusiveFacet:
+				op, msg, bound = "<", "must be at least", facet.value
+			case *minExclusiveFacet:
+				op, msg, bound = "<=", "must be greater than", facet.value
+			case *maxInclusiveFacet:
+				op, msg, bound = ">", "must be at most", facet.value
+			case *maxExclusiveFacet:
+				op, msg, bound = ">=", "must be less than", facet.value
+			}
+			literal, ok := goValueLiteral(underlying, bound)
+			if !ok {
+				continue
+			}
+			validate = append(validate, &ExprStmt{X: &BasicLit{
+				Value: "if t " + op + " " + literal + " {\n" + fail(value, verb+" "+msg+" "+literal) + "\n}",
+			}})
+
+		case *totalDigitsFacet:
+			if d := decimalExpr(underlying); d != "" {
+				validate = append(validate, &ExprStmt{X: &BasicLit{
+					Value: "if n := " + d + ".TotalDigits(); n > " + strconv.Itoa(facet.value) + " {\n" +
+						fail(value, verb+" has %d digits, but at most "+strconv.Itoa(facet.value)+" allowed", "n") + "\n}",
+				}})
+				continue
+			}
+			var digits string
+			switch underlying {
+			case "int", "int8", "int16", "int32", "int64":
+				digits = `strings.TrimPrefix(strconv.FormatInt(int64(t), 10), "-")`
+			case "uint", "uint8", "uint16", "uint32", "uint64":
+				digits = `strconv.FormatUint(uint64(t), 10)`
+			case "float64":
+				f.Require("math")
+				digits = `strings.TrimLeft(strings.Replace(strconv.FormatFloat(math.Abs(float64(t)), 'f', -1, 64), ".", "", 1), "0")`
+			default:
+				continue
+			}
+			f.Require("strconv")
+			f.Require("strings")
+			validate = append(validate, &ExprStmt{X: &BasicLit{
+				Value: "if n := len(" + digits + "); n > " + strconv.Itoa(facet.value) + " {\n" +
+					fail(value, verb+" has %d digits, but at most "+strconv.Itoa(facet.value)+" allowed", "n") + "\n}",
+			}})
+
+		case *explicitTimezoneFacet:
+			// A timezone of xs.DateTimeStamp is always required.
+			if _, ok := dateTimeTypes[underlying]; !ok || underlying == "xs.DateTimeStamp" {
+				continue
+			}
+			switch facet.value {
+			case "required":
+				validate = append(validate, &ExprStmt{X: &BasicLit{
+					Value: "if !t.Timezone {\n" + fail(value, verb+" must have a timezone") + "\n}",
+				}})
+			case "prohibited":
+				validate = append(validate, &ExprStmt{X: &BasicLit{
+					Value: "if t.Timezone {\n" + fail(value, verb+" must not have a timezone") + "\n}",
+				}})
+			}
+
+		case *fractionDigitsFacet:
+			if d := decimalExpr(underlying); d != "" {
+				validate = append(validate, &ExprStmt{X: &BasicLit{
+					Value: "if " + d + ".FractionDigits() > " + strconv.Itoa(facet.value) + " {\n" +
+						fail(value, verb+" has more than "+strconv.Itoa(facet.value)+" fraction digits") + "\n}",
+				}})
+				continue
+			}
+			if underlying != "float64" {
+				continue
+			}
+			f.Require("strconv")
+			f.Require("strings")
+			validate = append(validate, &ExprStmt{X: &BasicLit{
+				Value: "if s := strconv.FormatFloat(float64(t), 'f', -1, 64); strings.Contains(s, \".\") && len(s)-strings.Index(s, \".\")-1 > " + strconv.Itoa(facet.value) + " {\n" +
+					fail(value, verb+" has more than "+strconv.Itoa(facet.value)+" fraction digits") + "\n}",
+			}})
+		}
+	}
+
+	f.Require("fmt")
+	validate = append(validate, &ReturnStmt{Results: &Name{Value: "nil"}})
+	decls = append(decls, &FuncDecl{
+		Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+		Name: &Name{Value: "Validate"},
+		Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "error"}}}},
+		Body: &BlockStmt{List: validate},
+	})
+
+	if underlying == "string" {
+		text := "text"
+		if ws := whiteSpaceOf(typeDef); ws != "preserve" {
+			text = normalizeExpr(f, ws, "text")
+		}
+		parse = append(parse,
+			&AssignStmt{Define: true, Lhs: &Name{Value: "v"}, Rhs: &BasicLit{Value: typeName + "(" + text + ")"}},
+		)
+	} else if isRuntimeGoType(underlying) {
+		if len(patterns) > 0 {
+			f.Require(runtimePackage)
+			parse = append([]Stmt{&AssignStmt{Define: true, Lhs: &Name{Value: "s"}, Rhs: &BasicLit{Value: "xs.TrimSpace(string(text))"}}}, parse...)
+		}
+		// A defined type doesn't inherit methods of a runtime type, so they are delegated.
+		parse = append(parse,
+			&ExprStmt{X: &BasicLit{Value: "var x " + underlying}},
+			&ExprStmt{X: &BasicLit{
+				Value: "if err := x.UnmarshalText(text); err != nil {\n" +
+					"return fmt.Errorf(" + strconv.Quote(typeName+": %q is not a valid value: %w") + ", text, err)\n" +
+					"}",
+			}},
+			&AssignStmt{Define: true, Lhs: &Name{Value: "v"}, Rhs: &BasicLit{Value: typeName + "(x)"}},
+		)
+		decls = append(decls, &FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "MarshalText"},
+			Type: marshalTextFuncType(),
+			Body: &BlockStmt{List: []Stmt{
+				&ReturnStmt{Results: &BasicLit{Value: underlying + "(t).MarshalText()"}},
+			}},
+		})
+	} else {
+		// The lexical space of the built-in type is checked on the text with leading and trailing whitespace
+		// removed, and the parsed value is converted to the restricted type.
+		f.Require(runtimePackage)
+		parse = append([]Stmt{&AssignStmt{Define: true, Lhs: &Name{Value: "s"}, Rhs: &BasicLit{Value: "xs.TrimSpace(string(text))"}}}, parse...)
+		init, cond, x := textType{goType: typeName, underlying: underlying}.parse(f, "text", "s", "x")
+		if init != "" {
+			parse = append(parse, &ExprStmt{X: &BasicLit{Value: init}})
+		}
+		if cond == "err == nil" {
+			parse = append(parse, &ExprStmt{X: &BasicLit{
+				Value: "if err != nil {\n" + fail("text", "%q is not a valid value: %w", "err") + "\n}",
+			}})
+		} else {
+			parse = append(parse, &ExprStmt{X: &BasicLit{
+				Value: "if !(" + cond + ") {\n" + fail("text", "%q is not a valid value") + "\n}",
+			}})
+		}
+		if !strings.HasPrefix(x, typeName+"(") {
+			x = typeName + "(" + x + ")"
+		}
+		parse = append(parse, &AssignStmt{Define: true, Lhs: &Name{Value: "v"}, Rhs: &BasicLit{Value: x}})
+	}
+	decls = append(decls, &FuncDecl{
+		Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+		Name: &Name{Value: "UnmarshalText"},
+		Type: unmarshalTextFuncType(),
+		Body: &BlockStmt{List: append(parse,
+			&ExprStmt{X: &BasicLit{Value: "if err := v.Validate(); err != nil {\nreturn err\n}"}},
+			&AssignStmt{Lhs: &Name{Value: "*t"}, Rhs: &Name{Value: "v"}},
+			&ReturnStmt{Results: &Name{Value: "nil"}},
+		)},
+	})
+
+	return decls, nil
+}
+
+// textType describes how a value of a simple type is converted from and to text.
+type textType struct {
+	goType string
+	// underlying is the Go type of the nearest built-in ancestor of the type.
+	underlying string
+	whiteSpace string
+	// unmarshaler and marshaler report whether the Go type has UnmarshalText and MarshalText methods.
+	unmarshaler bool
+	marshaler   bool
+	// min and max are bounds of an integer type which are narrower than its Go type, e.g. 1 for xs:positiveInteger.
+	min, max string
+}
+
+func newTextType(f *File, typeDef *simpleTypeDefinition) textType {
+	goType := requireGoType(f, simpleGoType(typeDef))
+	underlying := requireGoType(f, builtinGoType(typeDef))
+	t := textType{
+		goType:      goType,
+		underlying:  underlying,
+		whiteSpace:  whiteSpaceOf(typeDef),
+		unmarshaler: goType != underlying || isRuntimeGoType(goType),
+		marshaler:   isRuntimeGoType(underlying) || typeDef.variety != "atomic",
+	}
+
+	bits, ok := intBitSizes[underlying]
+	if !ok {
+		return t
+	}
+	min, max := "0", strconv.FormatUint(1<<bits-1, 10)
+	if !strings.HasPrefix(underlying, "uint") {
+		min, max = strconv.FormatInt(-1<<(bits-1), 10), strconv.FormatInt(1<<(bits-1)-1, 10)
+	}
+	for _, facet := range typeDef.facets {
+		switch facet := facet.(type) {
+		case *minInclusiveFacet:
+			if facet.value != min {
+				t.min = facet.value
+			}
+		case *maxInclusiveFacet:
+			if facet.value != max {
+				t.max = facet.value
+			}
+		}
+	}
+	return t
+}
+
+// formatExpr returns an expression of the text of a value x of a type which has no MarshalText method.
+func (t textType) formatExpr(f *File, x string) string {
+	switch {
+	case t.underlying == "bool":
+		f.Require("strconv")
+		return "strconv.FormatBool(bool(" + x + "))"
+	case t.underlying == "float64":
+		f.Require("strconv")
+		return "strconv.FormatFloat(float64(" + x + "), 'f', -1, 64)"
+	case strings.HasPrefix(t.underlying, "uint"):
+		f.Require("strconv")
+		return "strconv.FormatUint(uint64(" + x + "), 10)"
+	case strings.HasPrefix(t.underlying, "int"):
+		f.Require("strconv")
+		return "strconv.FormatInt(int64(" + x + "), 10)"
+	}
+	return "string(" + x + ")"
+}
+
+// parse returns a statement which parses a text, a condition which reports whether it's parsed, and an expression of
+// the parsed value. A type with an UnmarshalText method is parsed into a variable x which must be declared before,
+// other types are parsed from a string s which has no leading and trailing whitespace. Any text is a valid string, so
+// a string type has neither a statement nor a condition.
+func (t textType) parse(f *File, text string, s string, x string) (string, string, string) {
+	switch {
+	case t.unmarshaler:
+		return "err := " + x + ".UnmarshalText(" + text + ")", "err == nil", x
+	case t.underlying == "bool":
+		return "", s + ` == "true" || ` + s + ` == "1" || ` + s + ` == "false" || ` + s + ` == "0"`, s + ` == "true" || ` + s + ` == "1"`
+	case t.underlying == "float64":
+		// strconv.ParseFloat accepts hexadecimal numbers and underscores, which are not in the lexical space.
+		f.Require(runtimePackage)
+		return x + ", err := xs.ParseDouble(" + s + ")", "err == nil", x
+	case intBitSizes[t.underlying] > 0:
+		f.Require("strconv")
+		parse := "strconv.ParseInt"
+		if strings.HasPrefix(t.underlying, "uint") {
+			parse = "strconv.ParseUint"
+		}
+		cond := "err == nil"
+		if t.min != "" {
+			cond += " && " + x + " >= " + t.min
+		}
+		if t.max != "" {
+			cond += " && " + x + " <= " + t.max
+		}
+		return x + ", err := " + parse + "(" + s + ", 10, " + strconv.Itoa(intBitSizes[t.underlying]) + ")", cond, t.goType + "(" + x + ")"
+	}
+	return "", "", normalizeExpr(f, t.whiteSpace, text)
+}
+
+// normalizeExpr returns a Go expression of a string value normalized by the whiteSpace facet. Only XML whitespace is
+// replaced or collapsed, other Unicode spaces are part of the value.
+func normalizeExpr(f *File, whiteSpace string, text string) string {
+	switch whiteSpace {
+	case "replace":
+		f.Require(runtimePackage)
+		return "xs.Replace(string(" + text + "))"
+	case "collapse":
+		f.Require(runtimePackage)
+		return "xs.Collapse(string(" + text + "))"
+	}
+	return "string(" + text + ")"
+}
+
+// unionMember is a member type of a union, which is kept in a field of the union's Go type.
+type unionMember struct {
+	field string
+	// name is the local name of the member type, which is reported by Which.
+	name string
+	textType
+}
+
+// unionMembers returns the member types of a union in order. A field is named after a named member type, or after
+// the Go type of an anonymous one, and a number is appended if the name is already taken. An anonymous member type is
+// reported by Which by the local name of its nearest named base type, also with a number if it's taken.
+func unionMembers(f *File, typeDef *simpleTypeDefinition) []unionMember {
+	members := make([]unionMember, 0, len(typeDef.memberTypeDefinitions))
+	usedNames := map[string]bool{}
+	usedXMLNames := map[string]bool{}
+	for _, m := range typeDef.memberTypeDefinitions {
+		member := m.(*simpleTypeDefinition)
+		t := newTextType(f, member)
+
+		field := makeTypeName(f, member.name)
+		if member.name.Local == "" {
+			if t.goType != t.underlying {
+				// A type of another package is referred to by a qualified name.
+				field = t.goType[strings.LastIndex(t.goType, ".")+1:]
+			} else if builtin := builtinAncestor(member); builtin != nil {
+				field = makeTypeName(f, builtin.name)
+			}
+		}
+		for i, base := 2, field; usedNames[field]; i++ {
+			field = base + strconv.Itoa(i)
+		}
+		usedNames[field] = true
+
+		name := ""
+		for t := member; t != nil && name == ""; {
+			name = t.name.Local
+			t, _ = t.baseTypeDefinition.(*simpleTypeDefinition)
+		}
+		for i, base := 2, name; usedXMLNames[name]; i++ {
+			name = base + strconv.Itoa(i)
+		}
+		usedXMLNames[name] = true
+
+		members = append(members, unionMember{field: field, name: name, textType: t})
+	}
+	return members
+}
+
+// createUnionTypeDecls creates a type for a union, which has a pointer field for each member type. UnmarshalText tries
+// the member types in order and sets the field of the first one which accepts the text, a Which method returns the
+// local name of the member type which is set, like Which of a choice returns the name of an element.
+func createUnionTypeDecls(f *File, typeDef *simpleTypeDefinition) []Decl {
+	typeName := typeDef.goType
+	members := unionMembers(f, typeDef)
+
+	f.Require("fmt")
+
+	s := &StructType{FieldList: make([]*Field, 0, len(members))}
+	which := "switch {\n"
+	marshal := "switch {\n"
+	var unmarshal []Stmt
+	trimmed := false
+	matched := false
+	for i, m := range members {
+		s.FieldList = append(s.FieldList, &Field{Name: &Name{Value: m.field}, Type: &PointerType{Elem: &Name{Value: m.goType}}})
+
+		value := "t." + m.field
+		which += "case " + value + " != nil:\nreturn " + strconv.Quote(m.name) + "\n"
+
+		marshal += "case " + value + " != nil:\n"
+		if m.marshaler {
+			marshal += "return " + value + ".MarshalText()\n"
+		} else if m.underlying == "string" {
+			marshal += "return []byte(*" + value + "), nil\n"
+		} else {
+			marshal += "return []byte(" + m.formatExpr(f, "*"+value) + "), nil\n"
+		}
+
+		// Any text is a valid string, so the following member types are never tried.
+		if matched {
+			continue
+		}
+
+		x := "x"
+		if m.unmarshaler {
+			x = "m" + strconv.Itoa(i+1)
+			unmarshal = append(unmarshal, &ExprStmt{X: &BasicLit{Value: "var " + x + " " + m.goType}})
+		} else if m.underlying != "string" && !trimmed {
+			// The lexical space of a built-in type is checked on the text with leading and trailing whitespace removed.
+			f.Require(runtimePackage)
+			unmarshal = append(unmarshal, &AssignStmt{Define: true, Lhs: &Name{Value: "s"}, Rhs: &BasicLit{Value: "xs.TrimSpace(string(text))"}})
+			trimmed = true
+		}
+		init, cond, v := m.parse(f, "text", "s", x)
+
+		set := "*t = " + typeName + "{" + m.field + ": &" + x + "}\nreturn nil"
+		if !m.unmarshaler {
+			set = "v := " + v + "\n*t = " + typeName + "{" + m.field + ": &v}\nreturn nil"
+		}
+		if cond == "" {
+			unmarshal = append(unmarshal, &ExprStmt{X: &BasicLit{Value: set}})
+			matched = true
+			continue
+		}
+		if init != "" {
+			init += "; "
+		}
+		unmarshal = append(unmarshal, &ExprStmt{X: &BasicLit{Value: "if " + init + cond + " {\n" + set + "\n}"}})
+	}
+	which += "}"
+	marshal += "}"
+
+	if !matched {
+		unmarshal = append(unmarshal, &ReturnStmt{Results: &BasicLit{
+			Value: "fmt.Errorf(" + strconv.Quote(typeName+": %q is not a valid value of any member type") + ", text)",
+		}})
+	}
+
+	return []Decl{
+		&TypeDecl{Name: &Name{Value: typeName}, Type: s},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "Which"},
+			Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "string"}}}},
+			Body: &BlockStmt{List: []Stmt{
+				&ExprStmt{X: &BasicLit{Value: which}},
+				&ReturnStmt{Results: &BasicLit{Value: `""`}},
+			}},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "MarshalText"},
+			Type: marshalTextFuncType(),
+			Body: &BlockStmt{List: []Stmt{
+				&ExprStmt{X: &BasicLit{Value: marshal}},
+				&ReturnStmt{Results: &BasicLit{Value: "nil, fmt.Errorf(" + strconv.Quote(typeName+": no member value is set") + ")"}},
+			}},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+			Name: &Name{Value: "UnmarshalText"},
+			Type: unmarshalTextFuncType(),
+			Body: &BlockStmt{List: unmarshal},
+		},
+	}
+}
+
+// createListTypeDecls creates a slice type for a list. A list is encoded as its items separated by spaces, and the
+// length facets count the items.
+func createListTypeDecls(f *File, typeDef *simpleTypeDefinition) []Decl {
+	typeName := typeDef.goType
+	item := newTextType(f, typeDef.itemTypeDefinition)
+
+	f.Require("fmt")
+	f.Require("strings")
+
+	decls := []Decl{&TypeDecl{Name: &Name{Value: typeName}, Type: &SliceType{Elem: &Name{Value: item.goType}}}}
+
+	var validate []Stmt
+	fail := func(format string, args ...string) string {
+		return "return fmt.Errorf(" + strconv.Quote(typeName+": "+format) + ", " + strings.Join(args, ", ") + ")"
+	}
+	for _, facet := range typeDef.facets {
+		var cond, msg string
+		switch facet := facet.(type) {
+		case *lengthFacet:
+			cond, msg = "n != "+strconv.Itoa(facet.value), "%d items, but it must have "+strconv.Itoa(facet.value)
+		case *minLengthFacet:
+			cond, msg = "n < "+strconv.Itoa(facet.value), "%d items, but it must have at least "+strconv.Itoa(facet.value)
+		case *maxLengthFacet:
+			cond, msg = "n > "+strconv.Itoa(facet.value), "%d items, but it must have at most "+strconv.Itoa(facet.value)
+		default:
+			continue
+		}
+		validate = append(validate, &ExprStmt{X: &BasicLit{
+			Value: "if n := len(t); " + cond + " {\n" + fail("list has "+msg, "n") + "\n}",
+		}})
+	}
+	if len(validate) > 0 {
+		decls = append(decls, &FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "Validate"},
+			Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "error"}}}},
+			Body: &BlockStmt{List: append(validate, &ReturnStmt{Results: &Name{Value: "nil"}})},
+		})
+	}
+
+	// Every item is marshaled by its type.
+	marshal := []Stmt{&ReturnStmt{Results: &BasicLit{Value: `[]byte(strings.Join(t, " ")), nil`}}}
+	if item.goType != "string" {
+		loop := "for _, x := range t {\n"
+		if item.marshaler {
+			loop += "text, err := x.MarshalText()\nif err != nil {\nreturn nil, err\n}\n" +
+				"items = append(items, string(text))\n"
+		} else {
+			loop += "items = append(items, " + item.formatExpr(f, "x") + ")\n"
+		}
+		marshal = []Stmt{
+			&AssignStmt{Define: true, Lhs: &Name{Value: "items"}, Rhs: &BasicLit{Value: "make([]string, 0, len(t))"}},
+			&ExprStmt{X: &BasicLit{Value: loop + "}"}},
+			&ReturnStmt{Results: &BasicLit{Value: `[]byte(strings.Join(items, " ")), nil`}},
+		}
+	}
+	decls = append(decls, &FuncDecl{
+		Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+		Name: &Name{Value: "MarshalText"},
+		Type: marshalTextFuncType(),
+		Body: &BlockStmt{List: marshal},
+	})
+
+	// Items are separated by XML whitespace, and every item is parsed by its type.
+	f.Require(runtimePackage)
+	parse := []Stmt{&AssignStmt{Define: true, Lhs: &Name{Value: "fields"}, Rhs: &BasicLit{Value: "xs.Fields(string(text))"}}}
+	if !item.unmarshaler && item.underlying == "string" {
+		parse = append(parse, &AssignStmt{Define: true, Lhs: &Name{Value: "v"}, Rhs: &BasicLit{Value: typeName + "(fields)"}})
+	} else {
+		unmarshal := "for _, s := range fields {\n"
+		init, cond, value := item.parse(f, "[]byte(s)", "s", "x")
+		if item.unmarshaler {
+			unmarshal += "var x " + item.goType + "\n"
+			init += "; "
+		} else if init != "" {
+			// A parsed value is used after the check, so it's declared outside of it.
+			unmarshal += init + "\n"
+			init = ""
+		}
+		if cond == "err == nil" {
+			unmarshal += "if " + init + "err != nil {\n" + fail("%q is not a valid item: %w", "s", "err") + "\n}\n"
+		} else {
+			unmarshal += "if " + init + "!(" + cond + ") {\n" + fail("%q is not a valid item", "s") + "\n}\n"
+		}
+		unmarshal += "v = append(v, " + value + ")\n}"
+		parse = append(parse,
+			&AssignStmt{Define: true, Lhs: &Name{Value: "v"}, Rhs: &BasicLit{Value: "make(" + typeName + ", 0, len(fields))"}},
+			&ExprStmt{X: &BasicLit{Value: unmarshal}},
+		)
+	}
+	if len(validate) > 0 {
+		parse = append(parse, &ExprStmt{X: &BasicLit{Value: "if err := v.Validate(); err != nil {\nreturn err\n}"}})
+	}
+	decls = append(decls, &FuncDecl{
+		Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+		Name: &Name{Value: "UnmarshalText"},
+		Type: unmarshalTextFuncType(),
+		Body: &BlockStmt{List: append(parse,
+			&AssignStmt{Lhs: &Name{Value: "*t"}, Rhs: &Name{Value: "v"}},
+			&ReturnStmt{Results: &Name{Value: "nil"}},
+		)},
+	})
+
+	return decls
+}
+
+// marshalTextFuncType returns a signature of the encoding.TextMarshaler's method.
+func marshalTextFuncType() *FuncType {
+	return &FuncType{ResultList: []*Field{
+		{Type: &SliceType{Elem: &Name{Value: "byte"}}},
+		{Type: &Name{Value: "error"}},
+	}}
+}
+
+// unmarshalTextFuncType returns a signature of the encoding.TextUnmarshaler's method.
+func unmarshalTextFuncType() *FuncType {
+	return &FuncType{
+		ParamList:  []*Field{{Name: &Name{Value: "text"}, Type: &SliceType{Elem: &Name{Value: "byte"}}}},
+		ResultList: []*Field{{Type: &Name{Value: "error"}}},
+	}
+}
+
+// createEnumerationDecls creates a constant for each value of an enumeration facet, and an IsValid method which checks
+// that a value is one of them.
+func createEnumerationDecls(f *File, typeName string, underlying string, e *enumerationFacet) []Decl {
+	var decls []Decl
+	group := &Group{}
+	constNames := make([]string, 0, len(e.value))
+	seenValues := map[string]bool{}
+	for _, v := range e.value {
+		value, ok := goValueLiteral(underlying, v)
+		if !ok || seenValues[value] {
+			continue
+		}
+		seenValues[value] = true
+
+		// A constant could take the name of a type or of a constant of another type, which is declared first.
+		name := f.names.declare(typeName + enumConstName(v))
+		constNames = append(constNames, name)
+
+		decls = append(decls, &ConstDecl{
+			NameList: []*Name{{Value: name}},
+			Type:     &Name{Value: typeName},
+			Values:   &BasicLit{Value: value},
+			Group:    group,
+		})
+	}
+
+	return append(decls, &FuncDecl{
+		Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+		Name: &Name{Value: "IsValid"},
+		Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "bool"}}}},
+		Body: &BlockStmt{List: []Stmt{
+			&ExprStmt{X: &BasicLit{Value: "switch t {\ncase " + strings.Join(constNames, ", ") + ":\nreturn true\n}"}},
+			&ReturnStmt{Results: &Name{Value: "false"}},
+		}},
+	})
+}
+
+// createRuntimeEnumerationDecls creates an IsValid method which checks that a value of a runtime type is one of values
+// of an enumeration facet. Values are compared by their canonical forms, since there are no constants of struct types.
+func createRuntimeEnumerationDecls(typeName string, underlying string, e *enumerationFacet) Decl {
+	values := make([]string, 0, len(e.value))
+	seen := map[string]bool{}
+	for _, v := range e.value {
+		canonical, ok := runtimeCanonical(underlying, v)
+		if !ok || seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+		values = append(values, strconv.Quote(canonical))
+	}
+
+	return &FuncDecl{
+		Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+		Name: &Name{Value: "IsValid"},
+		Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "bool"}}}},
+		Body: &BlockStmt{List: []Stmt{
+			&ExprStmt{X: &BasicLit{Value: "switch " + runtimeCanonicalExpr(underlying) + " {\ncase " + strings.Join(values, ", ") + ":\nreturn true\n}"}},
+			&ReturnStmt{Results: &Name{Value: "false"}},
+		}},
+	}
+}
+
+// runtimeCanonicalExpr returns an expression of the canonical form of a value t of a runtime type.
+func runtimeCanonicalExpr(underlying string) string {
+	if expr := decimalExpr(underlying); expr != "" {
+		return expr + ".Canonical()"
+	}
+	return underlying + "(t).String()"
+}
+
+// decimalExpr returns an expression of a value t of an arbitrary-precision runtime type as xs.Decimal, or an empty
+// string for other types.
+func decimalExpr(underlying string) string {
+	switch underlying {
+	case "xs.Decimal":
+		return "xs.Decimal(t)"
+	case "xs.Rat":
+		return "xs.Rat(t).Decimal()"
+	case "xs.Int":
+		return "xs.Int(t).Decimal()"
+	}
+	return ""
+}
+
+// dateTimeBound returns a condition under which a date/time value violates a bound facet, and a message about it.
+// Values are compared as time instants, a value without a timezone is taken in UTC. It reports false if the bound is
+// not a valid value.
+func dateTimeBound(parse func(string) (string, time.Time, error), facet ConstrainingFacet) (string, string, bool) {
+	var cond, msg, bound string
+	switch facet := facet.(type) {
+	case *minInclusiveFacet:
+		cond, msg, bound = "t.Time.Before(%s)", "must be at least", facet.value
+	case *minExclusiveFacet:
+		cond, msg, bound = "!t.Time.After(%s)", "must be later than", facet.value
+	case *maxInclusiveFacet:
+		cond, msg, bound = "t.Time.After(%s)", "must be at most", facet.value
+	case *maxExclusiveFacet:
+		cond, msg, bound = "!t.Time.Before(%s)", "must be earlier than", facet.value
+	}
+	canonical, instant, err := parse(bound)
+	if err != nil {
+		return "", "", false
+	}
+	return fmt.Sprintf(cond, goTimeLiteral(instant)), msg + " " + canonical, true
+}
+
+// decimalBound returns a condition under which a decimal violates a bound facet, and a message about it.
+func decimalBound(decimal string, facet ConstrainingFacet) (string, string, bool) {
+	var op, msg, bound string
+	switch facet := facet.(type) {
+	case *minInclusiveFacet:
+		op, msg, bound = "<", "must be at least", facet.value
+	case *minExclusiveFacet:
+		op, msg, bound = "<=", "must be greater than", facet.value
+	case *maxInclusiveFacet:
+		op, msg, bound = ">", "must be at most", facet.value
+	case *maxExclusiveFacet:
+		op, msg, bound = ">=", "must be less than", facet.value
+	}
+	d, err := xs.ParseDecimal(bound)
+	if err != nil {
+		return "", "", false
+	}
+	return decimal + ".Cmp(xs.MustParseDecimal(" + strconv.Quote(d.Canonical()) + ")) " + op + " 0", msg + " " + d.Canonical(), true
+}
+
+// durationBound returns a condition under which a duration violates a bound facet, and a message about it. Durations
+// are partially ordered, so a duration which is incomparable with the bound violates it.
+func durationBound(facet ConstrainingFacet) (string, string, bool) {
+	var cond, msg, bound string
+	switch facet := facet.(type) {
+	case *minInclusiveFacet:
+		cond, msg, bound = "c < 0", "must be at least", facet.value
+	case *minExclusiveFacet:
+		cond, msg, bound = "c <= 0", "must be longer than", facet.value
+	case *maxInclusiveFacet:
+		cond, msg, bound = "c > 0", "must be at most", facet.value
+	case *maxExclusiveFacet:
+		cond, msg, bound = "c >= 0", "must be shorter than", facet.value
+	}
+	d, err := xs.ParseDuration(bound)
+	if err != nil {
+		return "", "", false
+	}
+	literal := fmt.Sprintf("xs.Duration{Months: %d, Seconds: %d}", d.Months, int64(d.Seconds))
+	return "c, ok := xs.Duration(t).Compare(" + literal + "); !ok || " + cond, msg + " " + d.String(), true
+}
+
+// enumConstName makes an identifier suffix out of an enumeration value.
+func enumConstName(value string) string {
+	name := joinWords(value)
+	if strings.HasPrefix(value, "-") {
+		name = "Minus" + name
+	}
+	if name == "" {
+		return "Empty"
+	}
+	return name
+}
+
+func isNumericGoType(goType string) bool {
+	_, ok := intBitSizes[goType]
+	return ok || goType == "float64"
+}
+
+// intBitSizes are sizes of Go integer types which represent built-in integer types. The size of int and uint is
+// assumed to be 64.
+var intBitSizes = map[string]int{
+	"int": 64, "int8": 8, "int16": 16, "int32": 32, "int64": 64,
+	"uint": 64, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64,
+}
+
+// goValueLiteral returns a Go literal of a value for the underlying Go type. It reports false if the value is not
+// valid for the type.
+func goValueLiteral(underlying string, value string) (string, bool) {
+	trimmed := xs.TrimSpace(value)
+	switch underlying {
+	case "int", "int8", "int16", "int32", "int64":
+		v, err := strconv.ParseInt(strings.TrimPrefix(trimmed, "+"), 10, intBitSizes[underlying])
+		return strconv.FormatInt(v, 10), err == nil
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		v, err := strconv.ParseUint(strings.TrimPrefix(trimmed, "+"), 10, intBitSizes[underlying])
+		return strconv.FormatUint(v, 10), err == nil
+	case "float64":
+		v, err := strconv.ParseFloat(trimmed, 64)
+		if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+			return "", false
+		}
+		if s := strconv.FormatFloat(v, 'f', -1, 64); len(s) <= 21 {
+			return s, true
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case "bool":
+		v, err := strconv.ParseBool(trimmed)
+		return strconv.FormatBool(v), err == nil
+	}
+	return strconv.Quote(value), true
+}
+
+// goStringLiteral returns a raw string literal if possible, otherwise an interpreted one.
+func goStringLiteral(s string) string {
+	if !strings.Contains(s, "`") && !strings.Contains(s, "\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// createComplexTypeDeclType creates a struct for a complex type definition. If the element declaration is passed, then
+// the struct gets an XMLName field with the element's name.
+func createComplexTypeDeclType(f *File, elm *elementDeclaration, typeDef *complexTypeDefinition) *StructType {
+	s := &StructType{
+		FieldList: []*Field{},
+	}
+
+	// If it's a root type definition then add an XMLName field.
+	if elm != nil {
+		f.Require("encoding/xml")
+		s.FieldList = append(s.FieldList,
+			&Field{
+				Name: &Name{Value: "XMLName"},
+				Type: &BasicLit{Value: `xml.Name`},
+				Tags: map[string]string{
+					"xml": xmlNameTag(elm.name),
+				},
+			},
+		)
+	}
+
+	// A type derived by extension embeds its base type, so only its own attributes and particles are added.
+	attributeUses := typeDef.attributeUses
+	base, _, embedded := embeddedBase(typeDef)
+	if embedded {
+		s.FieldList = append(s.FieldList, &Field{Type: &Name{Value: requireGoType(f, base.goType)}})
+		attributeUses = make([]*attributeUse, 0, len(typeDef.attributeUses))
+		for _, attr := range typeDef.attributeUses {
+			if !hasAttributeUse(base, attr) {
+				attributeUses = append(attributeUses, attr)
+			}
+		}
+	}
+
+	// Fields of elements are created ahead of attributes, so an attribute could be named apart from them.
+	var particleFields []*Field
+	if p := structParticle(typeDef); p != nil && typeDef.itemType == "" {
+		particleFields = createParticleFields(f, f.names.structFields(typeDef), typeDef.choice, p, false, false)
+	}
+
+	for _, attr := range attributeUses {
+		f.Require("encoding/xml")
+		var attrType Expr
+		tags := xmlNameTag(attr.attributeDeclaration.name) + ",attr"
+		attrType = &BasicLit{Value: requireGoType(f, simpleGoType(attr.attributeDeclaration.typeDefinition))}
+		if !attr.required {
+			tags += ",omitempty"
+			attrType = &PointerType{Elem: attrType}
+		}
+		s.FieldList = append(s.FieldList,
+			&Field{
+				Name: &Name{Value: f.names.attributeField(s, attr.attributeDeclaration.name, particleFields)},
+				Type: attrType,
+				Tags: map[string]string{
+					"xml": tags,
+				},
+			},
+		)
+	}
+
+	// Simple content is kept as the text of the element. An extension of a type with simple content has the same
+	// content, which is promoted from the embedded base type.
+	if typeDef.contentType.variety == "simple" && !embedded {
+		name := "Value"
+		if hasField(s, name) {
+			// An attribute is named value.
+			name = "Content"
+		}
+		for i, base := 2, name; hasField(s, name); i++ {
+			name = base + strconv.Itoa(i)
+		}
+		s.FieldList = append(s.FieldList,
+			&Field{
+				Name: &Name{Value: name},
+				Type: &Name{Value: requireGoType(f, simpleGoType(typeDef.contentType.simpleTypeDefinition))},
+				Tags: map[string]string{
+					"xml": ",chardata",
+				},
+			},
+		)
+	}
+
+	// Attributes matched by an attribute wildcard are kept as is.
+	if typeDef.attributeWildcard != nil {
+		f.Require("encoding/xml")
+		s.FieldList = append(s.FieldList,
+			&Field{
+				Name: &Name{Value: "AnyAttrs"},
+				Type: &BasicLit{Value: `[]xml.Attr`},
+				Tags: map[string]string{
+					"xml": ",any,attr",
+				},
+			},
+		)
+	}
+
+	// Mixed content which is kept in document order is a slice of items, which are marshaled one by one.
+	if typeDef.itemType != "" {
+		s.FieldList = append(s.FieldList,
+			&Field{
+				Name: &Name{Value: "Items"},
+				Type: &SliceType{Elem: &Name{Value: typeDef.itemType}},
+				Tags: map[string]string{
+					"xml": ",any",
+				},
+			},
+		)
+		return s
+	}
+
+	for _, field := range particleFields {
+		if !hasField(s, field.Name.Value) {
+			s.FieldList = append(s.FieldList, field)
+		}
+	}
+	return s
+}
+
+// embeddedBase returns the base type definition which could be embedded into a struct of a type derived by
+// extension, and the particle of the type's own content. The base type is flattened into the derived struct if it
+// has no Go type of its own or it has XML methods, since embedded methods would be promoted and take over the
+// encoding of the derived type.
+func embeddedBase(typeDef *complexTypeDefinition) (*complexTypeDefinition, *particle, bool) {
+	if typeDef.derivationMethod != "extension" {
+		return nil, nil, false
+	}
+	base, ok := typeDef.baseTypeDefinition.(*complexTypeDefinition)
+	if !ok || base.goType == "" || hasXMLMethods(base) {
+		return nil, nil, false
+	}
+
+	p, baseParticle := typeDef.contentType.particle, base.contentType.particle
+	switch {
+	case baseParticle == nil || p == baseParticle:
+		// The base type has no particle, or the derived type adds no particle of its own.
+		if p == baseParticle {
+			p = nil
+		}
+		return base, p, true
+	case p != nil:
+		// The base particle is followed by the own particle in a sequence. The decoder fills only the first field
+		// which catches any element, so a wildcard can't follow a repeated choice of the base type.
+		if m, ok := p.term.(*modelGroup); ok && m.compositor == "sequence" && len(m.particles) == 2 && m.particles[0] == baseParticle {
+			if itemChoice(base) != nil && particleWildcard(m.particles[1]) != nil {
+				return nil, nil, false
+			}
+			return base, m.particles[1], true
+		}
+	}
+	return nil, nil, false
+}
+
+// structParticle returns the particle whose elements are generated as fields of a complex type's struct.
+func structParticle(typeDef *complexTypeDefinition) *particle {
+	if _, own, ok := embeddedBase(typeDef); ok {
+		return own
+	}
+	return typeDef.contentType.particle
+}
+
+// hasXMLMethods reports whether UnmarshalXML or MarshalXML is generated for a complex type definition.
+func hasXMLMethods(typeDef *complexTypeDefinition) bool {
+	return typeDef.itemType != "" || typeDef.attributeWildcard != nil || hasAllGroup(typeDef) ||
+		len(substitutionFields(typeDef)) > 0 || hasChoices(typeDef)
+}
+
+// hasChoices reports whether a complex type definition has choices which are checked by a Validate method, see
+// createChoiceDecls.
+func hasChoices(typeDef *complexTypeDefinition) bool {
+	p := typeDef.contentType.particle
+	return p != nil && len(collectChoices(p, false)) > 0
+}
+
+func hasAttributeUse(typeDef *complexTypeDefinition, a *attributeUse) bool {
+	for _, attr := range typeDef.attributeUses {
+		if attr == a {
+			return true
+		}
+	}
+	return false
+}
+
+// createParticleFields creates struct fields for elements of a particle. An element becomes an optional field if it's
+// optional by itself or it's a member of an optional model group or of a choice. It becomes a slice if it's repeated by
+// itself or by any of its model groups. Fields are named by names, see naming.structFields. A repeated choice which is
+// kept in document order becomes a slice of its items, which catches any element the other fields don't.
+func createParticleFields(f *File, names map[xml.Name]string, choice *choiceItem, p *particle, optional bool, repeated bool) []*Field {
+	if choice != nil && p == choice.particle {
+		return []*Field{{
+			Name: &Name{Value: choice.field},
+			Type: &SliceType{Elem: &Name{Value: choice.goType}},
+			Tags: map[string]string{
+				"xml": ",any",
+			},
+		}}
+	}
+	optional = optional || p.minOccurs == 0
+	repeated = repeated || p.maxOccurs > 1
+
+	fields := make([]*Field, 0)
+	switch term := p.term.(type) {
+	case *elementDeclaration:
+		dt := createElementRefType(f, term)
+		if substitutionGroup(term) != nil {
+			// Any element of the substitution group could appear in place of the head, and an absent one is nil.
+			dt = &Name{Value: substitutionGroupInterface(term)}
+			optional = false
+		}
+		if repeated {
+			dt = &SliceType{Elem: dt}
+		} else if optional {
+			dt = &PointerType{Elem: dt}
+		}
+		fields = append(fields,
+			&Field{
+				Name: &Name{Value: names[term.name]},
+				Type: dt,
+				Tags: map[string]string{
+					"xml": xmlNameTag(term.name),
+				},
+			},
+		)
+	case *wildcard:
+		// Elements matched by a wildcard are kept in the Any field. The decoder fills the first of such fields only, so
+		// the following wildcards share it.
+		dt := wildcardGoType(f, term)
+		if repeated {
+			dt = &SliceType{Elem: dt}
+		} else if optional {
+			dt = &PointerType{Elem: dt}
+		}
+		fields = append(fields,
+			&Field{
+				Name: &Name{Value: "Any"},
+				Type: dt,
+				Tags: map[string]string{
+					"xml": ",any",
+				},
+			},
+		)
+	case *modelGroup:
+		alternative := term.compositor == "choice" && len(term.particles) > 1
+		for _, particle := range term.particles {
+			fields = append(fields, createParticleFields(f, names, choice, particle, optional || alternative, repeated)...)
+		}
+	}
+	return fields
+}
+
+func hasField(s *StructType, name string) bool {
+	for _, field := range s.FieldList {
+		if field.Name != nil && field.Name.Value == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFieldTag reports whether a struct has a field with the xml tag.
+func hasFieldTag(s *StructType, tag string) bool {
+	for _, field := range s.FieldList {
+		if field.Tags["xml"] == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// createChoiceDecls creates methods which keep "exactly one of" semantics of choice model groups that are not repeated.
+// Each such choice gets a Which method (Which2, Which3 and so on for the following ones), which reports the present
+// branch by the name of its first element. A Validate method checks that a single branch of every choice is present,
+// or at most one if the choice is optional, and that a present branch has its required elements. It's called by
+// MarshalXML and after decoding.
+func createChoiceDecls(f *File, typeName string, typeDef *complexTypeDefinition, hasXMLName bool) []Decl {
+	if !hasChoices(typeDef) {
+		return nil
+	}
+	choices := collectChoices(typeDef.contentType.particle, false)
+
+	f.Require("encoding/xml")
+	f.Require("fmt")
+
+	fields, choice := promotedFields(f, typeDef)
+	decls := make([]Decl, 0, len(choices)+3)
+	checks := make([]Stmt, 0, len(choices)+2)
+	checks = append(checks, &AssignStmt{Define: true, Lhs: &Name{Value: "n"}, Rhs: &BasicLit{Value: "0"}})
+	required := make([]Stmt, 0)
+	for i, c := range choices {
+		methodName := "Which"
+		if i > 0 {
+			methodName += fmt.Sprint(i + 1)
+		}
+
+		// A branch is present if any of its elements is, and it's counted only once.
+		if i > 0 {
+			checks = append(checks, &AssignStmt{Lhs: &Name{Value: "n"}, Rhs: &BasicLit{Value: "0"}})
+		}
+		which := "switch {\n"
+		names := make([]string, 0)
+		for _, branch := range c.particle.term.(*modelGroup).particles {
+			elements := choiceElements(branch, false, choice)
+			if len(elements) == 0 {
+				continue
+			}
+			conds := make([]string, 0, len(elements))
+			for _, elm := range elements {
+				conds = append(conds, fieldPresence(fields, elm))
+			}
+			cond := strings.Join(conds, " || ")
+			which += "case " + cond + ":\n"
+			which += "return " + strconv.Quote(elements[0].name.Local) + "\n"
+			names = append(names, elements[0].name.Local)
+			checks = append(checks, &ExprStmt{X: &BasicLit{Value: "if " + cond + " {\nn++\n}"}})
+
+			for _, check := range requiredChecks(fields, choice, typeName, branch, false) {
+				required = append(required, &ExprStmt{X: &BasicLit{Value: check}})
+			}
+		}
+		which += "}"
+		decls = append(decls, &FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: methodName},
+			Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "string"}}}},
+			Body: &BlockStmt{List: []Stmt{
+				&ExprStmt{X: &BasicLit{Value: which}},
+				&ReturnStmt{Results: &BasicLit{Value: `""`}},
+			}},
+		})
+
+		cond, quantity := "n != 1", "exactly one"
+		if c.optional {
+			cond, quantity = "n > 1", "at most one"
+		}
+		checks = append(checks, &ExprStmt{X: &BasicLit{
+			Value: "if " + cond + " {\n" +
+				"return fmt.Errorf(" + strconv.Quote(quantity+" of "+strings.Join(names, ", ")+" must be present in "+typeName+", got %d") + ", n)\n" +
+				"}",
+		}})
+	}
+	checks = append(checks, required...)
+	checks = append(checks, &ReturnStmt{Results: &Name{Value: "nil"}})
+	decls = append(decls, &FuncDecl{
+		Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+		Name: &Name{Value: "Validate"},
+		Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "error"}}}},
+		Body: &BlockStmt{List: checks},
+	})
+
+	// An alias type has no methods, so it's marshaled by the encoder as usual.
+	marshal := []Stmt{
+		&ExprStmt{X: &BasicLit{Value: "if err := t.Validate(); err != nil {\nreturn err\n}"}},
+		&ExprStmt{X: &BasicLit{Value: "type alias " + typeName}},
+	}
+	if hasXMLName {
+		marshal = append(marshal, &ReturnStmt{Results: &BasicLit{Value: "e.Encode(alias(t))"}})
+	} else {
+		marshal = append(marshal, &ReturnStmt{Results: &BasicLit{Value: "e.EncodeElement(alias(t), start)"}})
+	}
+	decls = append(decls, &FuncDecl{
+		Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+		Name: &Name{Value: "MarshalXML"},
+		Type: marshalXMLFuncType(),
+		Body: &BlockStmt{List: marshal},
+	})
+
+	// Types with an attribute wildcard or substitution groups are validated by their own UnmarshalXML.
+	if typeDef.attributeWildcard == nil && len(substitutionFields(typeDef)) == 0 {
+		decls = append(decls, &FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: []Stmt{
+				&ExprStmt{X: &BasicLit{Value: "type alias " + typeName}},
+				&ExprStmt{X: &BasicLit{Value: "if err := d.DecodeElement((*alias)(t), &start); err != nil {\nreturn err\n}"}},
+				&ReturnStmt{Results: &BasicLit{Value: "t.Validate()"}},
+			}},
+		})
+	}
+
+	return decls
+}
+
+// promotedFields returns names of the fields of elements in a complex type's struct, including the fields promoted
+// from embedded base types, and the repeated choice which is kept in document order by any of them.
+func promotedFields(f *File, typeDef *complexTypeDefinition) (map[xml.Name]string, *choiceItem) {
+	fields := make(map[xml.Name]string)
+	var choice *choiceItem
+	for {
+		for name, field := range f.names.structFields(typeDef) {
+			if _, ok := fields[name]; !ok {
+				fields[name] = field
+			}
+		}
+		if choice == nil {
+			choice = typeDef.choice
+		}
+		base, _, ok := embeddedBase(typeDef)
+		if !ok {
+			return fields, choice
+		}
+		typeDef = base
+	}
+}
+
+// createAllDecls creates an UnmarshalXML method for an <all> model group. Elements of the group may come in any order,
+// which is what the decoder does with a struct anyway, but it silently keeps the last one of duplicated elements and
+// ignores missing elements. So every element is decoded into a shadowing slice field first, and its occurrences are
+// checked against the particle's minOccurs and maxOccurs.
+func createAllDecls(f *File, typeName string, typeDef *complexTypeDefinition, hasXMLName bool) []Decl {
+	if !hasAllGroup(typeDef) {
+		return nil
+	}
+	p := typeDef.contentType.particle
+	m := p.term.(*modelGroup)
+	names := f.names.structFields(typeDef)
+
+	f.Require("encoding/xml")
+	f.Require("fmt")
+
+	// The alias is embedded, so it must be exported to let the decoder set its XMLName field.
+	shadow := &StructType{FieldList: []*Field{{Type: &PointerType{Elem: &Name{Value: "XMLAlias"}}}}}
+	init := &CompositeLit{
+		Type:     shadow,
+		ElemList: []Expr{&KeyValueExpr{Key: &Name{Value: "XMLAlias"}, Value: &BasicLit{Value: "(*XMLAlias)(t)"}}},
+		NKeys:    1,
+	}
+	vars := make([]Stmt, 0)
+	counts := make([]string, 0, len(m.particles))
+	required := make([]string, 0, len(m.particles))
+	checks := make([]Stmt, 0, len(m.particles))
+	for _, child := range m.particles {
+		elm, ok := child.term.(*elementDeclaration)
+		if !ok {
+			continue
+		}
+		fieldName := names[elm.name]
+		field := "v." + fieldName
+		head := substitutionGroup(elm) != nil
+		if head {
+			// Elements of a substitution group are collected into a variable.
+			field = "elm" + fieldName
+			vars = append(vars, &ExprStmt{X: &BasicLit{Value: "var " + field + " " + substitutionGroupCollector(elm)}})
+			addSubstitutionShadowFields(f, names, shadow, init, elm, field)
+		} else {
+			shadow.FieldList = append(shadow.FieldList, &Field{
+				Name: &Name{Value: fieldName},
+				Type: &SliceType{Elem: createElementRefType(f, elm)},
+				Tags: map[string]string{
+					"xml": xmlNameTag(elm.name),
+				},
+			})
+		}
+		counts = append(counts, "len("+field+")")
+
+		if child.minOccurs > 0 {
+			required = append(required, "if len("+field+") < "+strconv.Itoa(child.minOccurs)+" {\n"+
+				"return fmt.Errorf("+strconv.Quote("element "+elm.name.Local+" is missing in "+typeName)+")\n"+
+				"}")
+		}
+
+		if child.maxOccurs > 1 {
+			if child.maxOccurs != unbounded {
+				checks = append(checks, &ExprStmt{X: &BasicLit{
+					Value: "if len(" + field + ") > " + strconv.Itoa(child.maxOccurs) + " {\n" +
+						"return fmt.Errorf(" + strconv.Quote("element "+elm.name.Local+" occurs %d times in "+typeName+", but at most "+strconv.Itoa(child.maxOccurs)+" allowed") + ", len(" + field + "))\n" +
+						"}",
+				}})
+			}
+			checks = append(checks, &AssignStmt{Lhs: &Name{Value: "t." + fieldName}, Rhs: &Name{Value: field}})
+			continue
+		}
+
+		// The same rule as in createParticleFields makes a field optional.
+		assign := field + "[0]"
+		if !head && (child.minOccurs == 0 || p.minOccurs == 0) {
+			assign = "&" + assign
+		}
+		checks = append(checks,
+			&ExprStmt{X: &BasicLit{
+				Value: "if len(" + field + ") > 1 {\n" +
+					"return fmt.Errorf(" + strconv.Quote("element "+elm.name.Local+" occurs %d times in "+typeName) + ", len(" + field + "))\n" +
+					"}",
+			}},
+			&ExprStmt{X: &BasicLit{
+				Value: "if len(" + field + ") == 1 {\n" +
+					"t." + fieldName + " = " + assign + "\n" +
+					"}",
+			}},
+		)
+	}
+
+	body := []Stmt{}
+	if typeDef.attributeWildcard != nil {
+		body = append(body, stripNamespaceDeclsStmt())
+	}
+	body = append(body, &ExprStmt{X: &BasicLit{Value: "type XMLAlias " + typeName}})
+	body = append(body, vars...)
+	body = append(body,
+		&AssignStmt{Define: true, Lhs: &Name{Value: "v"}, Rhs: init},
+		&ExprStmt{X: &BasicLit{Value: "if err := d.DecodeElement(&v, &start); err != nil {\nreturn err\n}"}},
+	)
+	if hasXMLName {
+		// The decoder doesn't set XMLName of an embedded struct.
+		body = append(body, &AssignStmt{Lhs: &Name{Value: "t.XMLName"}, Rhs: &Name{Value: "start.Name"}})
+	}
+	if len(required) > 0 {
+		if p.minOccurs == 0 {
+			// The whole group is optional, so its required elements are required only if any of them is present.
+			body = append(body, &ExprStmt{X: &BasicLit{
+				Value: "if " + strings.Join(counts, "+") + " > 0 {\n" + strings.Join(required, "\n") + "\n}",
+			}})
+		} else {
+			for _, check := range required {
+				body = append(body, &ExprStmt{X: &BasicLit{Value: check}})
+			}
+		}
+	}
+	body = append(body, checks...)
+	body = append(body, &ReturnStmt{Results: &Name{Value: "nil"}})
+
+	return []Decl{
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: body},
+		},
+	}
+}
+
+// hasAllGroup reports whether the content of the type definition is an <all> model group.
+func hasAllGroup(typeDef *complexTypeDefinition) bool {
+	p := typeDef.contentType.particle
+	if p == nil {
+		return false
+	}
+	m, ok := p.term.(*modelGroup)
+	return ok && m.compositor == "all"
+}
+
+// createAnyAttrDecls creates an UnmarshalXML method for a type with an attribute wildcard. The decoder passes
+// namespace declarations as attributes, and they would be caught by the wildcard field and written back as ordinary
+// attributes by the encoder. Types with an <all> group do the same in their own UnmarshalXML.
+func createAnyAttrDecls(f *File, typeName string, typeDef *complexTypeDefinition) []Decl {
+	if typeDef.attributeWildcard == nil || hasAllGroup(typeDef) || len(substitutionFields(typeDef)) > 0 {
+		return nil
+	}
+
+	f.Require("encoding/xml")
+
+	body := []Stmt{
+		stripNamespaceDeclsStmt(),
+		&ExprStmt{X: &BasicLit{Value: "type alias " + typeName}},
+	}
+	if hasChoices(typeDef) {
+		// Choices are checked after decoding, see createChoiceDecls.
+		body = append(body,
+			&ExprStmt{X: &BasicLit{Value: "if err := d.DecodeElement((*alias)(t), &start); err != nil {\nreturn err\n}"}},
+			&ReturnStmt{Results: &BasicLit{Value: "t.Validate()"}},
+		)
+	} else {
+		body = append(body, &ReturnStmt{Results: &BasicLit{Value: "d.DecodeElement((*alias)(t), &start)"}})
+	}
+
+	return []Decl{
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: body},
+		},
+	}
+}
+
+// createMixedDecls creates a complex type whose mixed content is kept as a slice of items in document order, and the
+// type of its items. An item is either text or one of the child elements, and a Which method returns the local name
+// of the element, or an empty string for text.
+func createMixedDecls(f *File, typeName string, typeDef *complexTypeDefinition, elm *elementDeclaration) []Decl {
+	f.Require("encoding/xml")
+
+	// Attributes are decoded from the start element alone, then the content is read token by token.
+	unmarshal := make([]Stmt, 0, 4)
+	if typeDef.attributeWildcard != nil {
+		unmarshal = append(unmarshal, stripNamespaceDeclsStmt())
+	}
+	unmarshal = append(unmarshal,
+		&ExprStmt{X: &BasicLit{Value: "type alias " + typeName}},
+		&ExprStmt{X: &BasicLit{Value: "if err := xml.NewTokenDecoder(&xmlTokens{start, start.End()}).Decode((*alias)(t)); err != nil {\nreturn err\n}"}},
+		&AssignStmt{Lhs: &Name{Value: "t.Items"}, Rhs: &Name{Value: "nil"}},
+		&ExprStmt{X: &BasicLit{
+			Value: "for {\n" +
+				"tok, err := d.Token()\n" +
+				"if err != nil {\nreturn err\n}\n" +
+				"switch tok := tok.(type) {\n" +
+				"case xml.CharData:\n" +
+				"if n := len(t.Items); n > 0 && t.Items[n-1].Which() == \"\" {\n" +
+				"t.Items[n-1].Text += string(tok)\n" +
+				"} else {\n" +
+				"t.Items = append(t.Items, " + typeDef.itemType + "{Text: string(tok)})\n" +
+				"}\n" +
+				"case xml.StartElement:\n" +
+				"var item " + typeDef.itemType + "\n" +
+				"if err := item.UnmarshalXML(d, tok); err != nil {\nreturn err\n}\n" +
+				"t.Items = append(t.Items, item)\n" +
+				"case xml.EndElement:\n" +
+				"return nil\n" +
+				"}\n" +
+				"}",
+		}},
+	)
+
+	decls := []Decl{
+		&TypeDecl{
+			Name: &Name{Value: typeName},
+			Type: createComplexTypeDeclType(f, elm, typeDef),
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: unmarshal},
+		},
+	}
+	return append(decls, createMixedItemDecls(f, typeDef)...)
+}
+
+// createMixedItemDecls creates the type of items of mixed content, which has a text and a pointer field for each
+// child element. Elements which are not allowed in the content are skipped on decoding.
+func createMixedItemDecls(f *File, typeDef *complexTypeDefinition) []Decl {
+	typeName := typeDef.itemType
+	s := &StructType{FieldList: []*Field{{Name: &Name{Value: "Text"}, Type: &Name{Value: "string"}}}}
+	which := "switch {\n"
+	unmarshal := "switch {\n"
+	marshal := "switch {\n"
+	elements := mixedElements(typeDef.contentType.particle)
+	names := make([]xml.Name, 0, len(elements))
+	for _, elm := range elements {
+		names = append(names, elm.name)
+	}
+	// Text is the text of an item.
+	fields := f.names.fieldNames(names, map[string]bool{"Text": true})
+	for _, elm := range elements {
+		field := fields[elm.name]
+		if hasField(s, field) {
+			continue
+		}
+		s.FieldList = append(s.FieldList, &Field{Name: &Name{Value: field}, Type: &PointerType{Elem: createElementRefType(f, elm)}})
+
+		value := "t." + field
+		which += "case " + value + " != nil:\nreturn " + strconv.Quote(elm.name.Local) + "\n"
+
+		// An unqualified name matches an element in any namespace, as encoding/xml does.
+		cond := "start.Name.Local == " + strconv.Quote(elm.name.Local)
+		if elm.name.Space != "" {
+			cond += " && start.Name.Space == " + strconv.Quote(elm.name.Space)
+		}
+		unmarshal += "case " + cond + ":\n" +
+			"return d.DecodeElement(&" + value + ", &start)\n"
+
+		marshal += "case " + value + " != nil:\n" +
+			"return e.EncodeElement(" + value + ", xml.StartElement{Name: xml.Name{Space: " + strconv.Quote(elm.name.Space) + ", Local: " + strconv.Quote(elm.name.Local) + "}})\n"
+	}
+
+	// Elements matched by a wildcard are kept in the Any field, other elements are skipped.
+	skip := "d.Skip()"
+	if w := particleWildcard(typeDef.contentType.particle); w != nil && !hasField(s, "Any") {
+		s.FieldList = append(s.FieldList, &Field{Name: &Name{Value: "Any"}, Type: &PointerType{Elem: wildcardGoType(f, w)}})
+		which += "case t.Any != nil:\nreturn \"any\"\n"
+		marshal += "case t.Any != nil:\nreturn e.Encode(t.Any)\n"
+		skip = "d.DecodeElement(&t.Any, &start)"
+	}
+	which += "}"
+	unmarshal += "}"
+	marshal += "}"
+
+	// Text is the only item of mixed content without elements.
+	switchStmt := func(body string, result string) []Stmt {
+		if body == "switch {\n}" {
+			return []Stmt{&ReturnStmt{Results: &BasicLit{Value: result}}}
+		}
+		return []Stmt{&ExprStmt{X: &BasicLit{Value: body}}, &ReturnStmt{Results: &BasicLit{Value: result}}}
+	}
+
+	return []Decl{
+		&TypeDecl{Name: &Name{Value: typeName}, Type: s},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "Which"},
+			Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "string"}}}},
+			Body: &BlockStmt{List: switchStmt(which, `""`)},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: switchStmt(unmarshal, skip)},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "MarshalXML"},
+			Type: marshalXMLFuncType(),
+			Body: &BlockStmt{List: switchStmt(marshal, "e.EncodeToken(xml.CharData(t.Text))")},
+		},
+	}
+}
+
+// particleWildcard returns the first element wildcard of a particle, or nil if it has none.
+func particleWildcard(p *particle) *wildcard {
+	if p == nil {
+		return nil
+	}
+	switch term := p.term.(type) {
+	case *wildcard:
+		return term
+	case *modelGroup:
+		for _, child := range term.particles {
+			if w := particleWildcard(child); w != nil {
+				return w
+			}
+		}
+	}
+	return nil
+}
+
+// mixedElements returns all element declarations of a particle in document order.
+func mixedElements(p *particle) []*elementDeclaration {
+	if p == nil {
+		return nil
+	}
+	elements := make([]*elementDeclaration, 0)
+	switch term := p.term.(type) {
+	case *elementDeclaration:
+		elements = append(elements, term)
+	case *modelGroup:
+		for _, child := range term.particles {
+			elements = append(elements, mixedElements(child)...)
+		}
+	}
+	return elements
+}
+
+// createXMLTokensDecls creates a token reader of a list of tokens, which lets a decoder decode a start element alone.
+func createXMLTokensDecls(f *File) []Decl {
+	f.Require("io")
+
+	return []Decl{
+		&TypeDecl{Name: &Name{Value: "xmlTokens"}, Type: &SliceType{Elem: &BasicLit{Value: "xml.Token"}}},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: "xmlTokens"}}},
+			Name: &Name{Value: "Token"},
+			Type: &FuncType{ResultList: []*Field{
+				{Type: &BasicLit{Value: "xml.Token"}},
+				{Type: &Name{Value: "error"}},
+			}},
+			Body: &BlockStmt{List: []Stmt{
+				&ExprStmt{X: &BasicLit{Value: "if len(*t) == 0 {\nreturn nil, io.EOF\n}"}},
+				&AssignStmt{Define: true, Lhs: &Name{Value: "tok"}, Rhs: &BasicLit{Value: "(*t)[0]"}},
+				&AssignStmt{Lhs: &Name{Value: "*t"}, Rhs: &BasicLit{Value: "(*t)[1:]"}},
+				&ReturnStmt{Results: &BasicLit{Value: "tok, nil"}},
+			}},
+		},
+	}
+}
+
+type ownedWildcard struct {
+	wildcard *wildcard
+	owner    string
+}
+
+// collectWildcards returns element wildcards of a particle which are generated as types of their own, along with the
+// names of their owners. Anonymous types of local elements are declared in place, so their wildcards have the same
+// owner.
+func collectWildcards(p *particle, owner string, visited map[interface{}]bool) []ownedWildcard {
+	if p == nil {
+		return nil
+	}
+	wildcards := make([]ownedWildcard, 0)
+	switch term := p.term.(type) {
+	case *wildcard:
+		if !visited[term] && hasWildcardType(term) {
+			visited[term] = true
+			wildcards = append(wildcards, ownedWildcard{wildcard: term, owner: owner})
+		}
+	case *elementDeclaration:
+		if term.scope.variety == "local" {
+			wildcards = append(wildcards, elementWildcards(term, owner, visited)...)
+		}
+	case *modelGroup:
+		if visited[term] {
+			return nil
+		}
+		visited[term] = true
+		for _, child := range term.particles {
+			wildcards = append(wildcards, collectWildcards(child, owner, visited)...)
+		}
+	}
+	return wildcards
+}
+
+// elementWildcards returns element wildcards of an element's type if it's anonymous or xs:anyType.
+func elementWildcards(elm *elementDeclaration, owner string, visited map[interface{}]bool) []ownedWildcard {
+	typeDef, ok := elm.typeDefinition.(*complexTypeDefinition)
+	switch {
+	case !ok:
+		return nil
+	case typeDef == anyType:
+		return collectWildcards(typeDef.contentType.particle, "", visited)
+	case typeDef.goType == "":
+		return collectWildcards(typeDef.contentType.particle, owner, visited)
+	}
+	return nil
+}
+
+// hasWildcardType reports whether an element wildcard is generated as a type of its own. Elements matched by a
+// wildcard of any namespace which skips their contents are kept as xs.AnyElement.
+func hasWildcardType(w *wildcard) bool {
+	return w.processContents != "skip" || w.namespaceConstraint.variety != "any"
+}
+
+// wildcardGoType returns a Go type of elements matched by an element wildcard.
+func wildcardGoType(f *File, w *wildcard) Expr {
+	if typeName, ok := f.wildcards[w]; ok {
+		return &Name{Value: typeName}
+	}
+	return &Name{Value: requireGoType(f, "xs.AnyElement")}
+}
+
+// createWildcardDecls creates a type of elements matched by an element wildcard, which checks the namespace constraint
+// of the wildcard on decoding. Unless their contents are skipped, top-level elements of the schema are decoded into
+// their own Go types. Other elements are kept as xs.AnyElement if their contents are processed laxly, and they are
+// rejected if their contents are processed strictly.
+func createWildcardDecls(f *File, schema *schema, keys []xml.Name, w *wildcard) []Decl {
+	typeName := f.wildcards[w]
+	f.Require("encoding/xml")
+
+	unmarshal := make([]Stmt, 0, 4)
+	if c := w.namespaceConstraint; c.variety != "any" {
+		f.Require("fmt")
+		spaces := make([]string, 0, len(c.namespaces))
+		for _, ns := range c.namespaces {
+			spaces = append(spaces, strconv.Quote(ns))
+		}
+		reject := "return fmt.Errorf(" + strconv.Quote("element %s of namespace %q is not allowed in "+typeName) + ", start.Name.Local, start.Name.Space)"
+		if c.variety == "enumeration" {
+			reject = "default:\n" + reject
+		}
+		unmarshal = append(unmarshal, &ExprStmt{X: &BasicLit{
+			Value: "switch start.Name.Space {\ncase " + strings.Join(spaces, ", ") + ":\n" + reject + "\n}",
+		}})
+	}
+
+	if w.processContents != "skip" {
+		cases := ""
+		for _, key := range keys {
+			elm := schema.elementDeclarations[key]
+			if elm.abstract || !w.namespaceConstraint.allows(key.Space) {
+				continue
+			}
+			cases += "case xml.Name{Space: " + strconv.Quote(key.Space) + ", Local: " + strconv.Quote(key.Local) + "}:\n" +
+				"v := new(" + elm.goType + ")\n" +
+				"t.Value = v\n" +
+				"return d.DecodeElement(v, &start)\n"
+		}
+		if cases != "" {
+			unmarshal = append(unmarshal, &ExprStmt{X: &BasicLit{Value: "switch start.Name {\n" + cases + "}"}})
+		}
+	}
+
+	if w.processContents == "strict" {
+		f.Require("fmt")
+		unmarshal = append(unmarshal, &ReturnStmt{Results: &BasicLit{
+			Value: "fmt.Errorf(" + strconv.Quote("element %s of namespace %q is not declared") + ", start.Name.Local, start.Name.Space)",
+		}})
+	} else {
+		unmarshal = append(unmarshal,
+			&AssignStmt{Define: true, Lhs: &Name{Value: "v"}, Rhs: &BasicLit{Value: "new(" + requireGoType(f, "xs.AnyElement") + ")"}},
+			&AssignStmt{Lhs: &Name{Value: "t.Value"}, Rhs: &Name{Value: "v"}},
+			&ReturnStmt{Results: &BasicLit{Value: "d.DecodeElement(v, &start)"}},
+		)
+	}
+
+	return []Decl{
+		&TypeDecl{
+			Name: &Name{Value: typeName},
+			Type: &StructType{FieldList: []*Field{{Name: &Name{Value: "Value"}, Type: &Name{Value: "interface{}"}}}},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: unmarshal},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "MarshalXML"},
+			Type: marshalXMLFuncType(),
+			Body: &BlockStmt{List: []Stmt{
+				&ExprStmt{X: &BasicLit{Value: "if t.Value == nil {\nreturn nil\n}"}},
+				&ReturnStmt{Results: &BasicLit{Value: "e.Encode(t.Value)"}},
+			}},
+		},
+	}
+}
+
+// stripNamespaceDeclsStmt returns a statement which removes namespace declarations from attributes of the start
+// element.
+func stripNamespaceDeclsStmt() Stmt {
+	return &ExprStmt{X: &BasicLit{
+		Value: "attrs := make([]xml.Attr, 0, len(start.Attr))\n" +
+			"for _, a := range start.Attr {\n" +
+			"if a.Name.Space != \"xmlns\" && !(a.Name.Space == \"\" && a.Name.Local == \"xmlns\") {\n" +
+			"attrs = append(attrs, a)\n" +
+			"}\n" +
+			"}\n" +
+			"start.Attr = attrs",
+	}}
+}
+
+// choiceGroup is a choice particle which is checked by a Validate method, see createChoiceDecls.
+type choiceGroup struct {
+	particle *particle
+	// optional is true if the choice or any particle around it may be missing, so none of its branches is required.
+	optional bool
+}
+
+// collectChoices returns the choice particles which are not repeated, including choices in branches of other choices,
+// in document order. A choice is optional if it may match no elements by itself, it's in a model group which may, or
+// it's in a branch of another choice.
+func collectChoices(p *particle, optional bool) []choiceGroup {
+	if p.maxOccurs > 1 {
+		return nil
+	}
+	m, ok := p.term.(*modelGroup)
+	if !ok {
+		return nil
+	}
+	optional = optional || emptiable(p)
+	choices := make([]choiceGroup, 0)
+	if m.compositor == "choice" && len(m.particles) > 1 {
+		choices = append(choices, choiceGroup{particle: p, optional: optional})
+		optional = true
+	}
+	for _, child := range m.particles {
+		choices = append(choices, collectChoices(child, optional)...)
+	}
+	return choices
+}
+
+// emptiable reports whether a particle may match no elements at all.
+func emptiable(p *particle) bool {
+	if p.minOccurs == 0 {
+		return true
+	}
+	m, ok := p.term.(*modelGroup)
+	if !ok {
+		return false
+	}
+	if m.compositor == "choice" {
+		for _, child := range m.particles {
+			if emptiable(child) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, child := range m.particles {
+		if !emptiable(child) {
+			return false
+		}
+	}
+	return true
+}
+
+type choiceElement struct {
+	name     xml.Name
+	repeated bool
+	// field is the name of the field which keeps the element if it isn't named after the element.
+	field string
+}
+
+// choiceElements returns all elements of a particle in document order, and whether they are repeated. A repeated
+// choice which is kept in document order is a single element, named after its first element.
+func choiceElements(p *particle, repeated bool, choice *choiceItem) []choiceElement {
+	if choice != nil && p == choice.particle {
+		first := choiceItemElements(p)[0]
+		return []choiceElement{{name: first.name, repeated: true, field: choice.field}}
+	}
+	repeated = repeated || p.maxOccurs > 1
+
+	elements := make([]choiceElement, 0)
+	switch term := p.term.(type) {
+	case *elementDeclaration:
+		elements = append(elements, choiceElement{name: term.name, repeated: repeated})
+	case *wildcard:
+		// Elements matched by a wildcard are kept in the Any field.
+		elements = append(elements, choiceElement{name: xml.Name{Local: "any"}, repeated: repeated, field: "Any"})
+	case *modelGroup:
+		for _, child := range term.particles {
+			elements = append(elements, choiceElements(child, repeated, choice)...)
+		}
+	}
+	return elements
+}
+
+// fieldPresence returns a Go expression which is true if an element is present in the receiver t. All elements of a
+// choice are optional fields, so they are either pointers or slices.
+func fieldPresence(names map[xml.Name]string, elm choiceElement) string {
+	if elm.repeated {
+		return "len(" + choiceField(names, elm) + ") > 0"
+	}
+	return choiceField(names, elm) + " != nil"
+}
+
+// fieldAbsence returns a Go expression which is true if an element is missing in the receiver t.
+func fieldAbsence(names map[xml.Name]string, elm choiceElement) string {
+	if elm.repeated {
+		return "len(" + choiceField(names, elm) + ") == 0"
+	}
+	return choiceField(names, elm) + " == nil"
+}
+
+func choiceField(names map[xml.Name]string, elm choiceElement) string {
+	if elm.field != "" {
+		return "t." + elm.field
+	}
+	return "t." + names[elm.name]
+}
+
+// requiredChecks returns checks of the required members of a sequence which is present if any of its elements is. A
+// member is required unless it may match no elements, and a member which is a model group is required to have any of
+// its elements. Its own members are checked the same way.
+func requiredChecks(names map[xml.Name]string, choice *choiceItem, typeName string, p *particle, repeated bool) []string {
+	m, ok := p.term.(*modelGroup)
+	if !ok || m.compositor != "sequence" || choice != nil && p == choice.particle {
+		return nil
+	}
+	repeated = repeated || p.maxOccurs > 1
+
+	checks := make([]string, 0)
+	for i, child := range m.particles {
+		if !emptiable(child) {
+			others := make([]string, 0)
+			for j, other := range m.particles {
+				if j == i {
+					continue
+				}
+				for _, elm := range choiceElements(other, repeated, choice) {
+					others = append(others, fieldPresence(names, elm))
+				}
+			}
+			absent := make([]string, 0)
+			members := make([]string, 0)
+			for _, elm := range choiceElements(child, repeated, choice) {
+				absent = append(absent, fieldAbsence(names, elm))
+				members = append(members, elm.name.Local)
+			}
+			if len(others) > 0 && len(members) > 0 {
+				cond := strings.Join(others, " || ")
+				if len(others) > 1 {
+					cond = "(" + cond + ")"
+				}
+				message := "element " + members[0] + " is missing in " + typeName
+				if len(members) > 1 {
+					message = "elements " + strings.Join(members, ", ") + " are missing in " + typeName
+				}
+				checks = append(checks, "if "+cond+" && "+strings.Join(absent, " && ")+" {\n"+
+					"return fmt.Errorf("+strconv.Quote(message)+")\n"+
+					"}")
+			}
+		}
+		checks = append(checks, requiredChecks(names, choice, typeName, child, repeated)...)
+	}
+	return checks
+}
+
+// choiceItem is a repeated choice of a complex type's struct whose occurrences are kept in document order. The struct
+// has a slice of items of the choice in place of the fields of its elements, see itemChoice.
+type choiceItem struct {
+	particle *particle
+	// goType is the name of the item type.
+	goType string
+	// field is the name of the slice field in the struct, see naming.structFields.
+	field string
+}
+
+// itemChoice returns the repeated choice of a complex type's struct which is kept in document order, or nil if there
+// is none. Only a choice of elements which are found nowhere else in the content qualifies, so every element could be
+// told apart by its name. A struct has at most one such choice, since the decoder fills only the first field which
+// catches any element, and none if the content has an element wildcard or the type's items are mixed content.
+func itemChoice(typeDef *complexTypeDefinition) *particle {
+	content := typeDef.contentType.particle
+	if typeDef.itemType != "" || content == nil || particleWildcard(content) != nil {
+		return nil
+	}
+	if base, _, ok := embeddedBase(typeDef); ok && itemChoice(base) != nil {
+		return nil
+	}
+	p := structParticle(typeDef)
+	if p == nil {
+		return nil
+	}
+	choices := repeatedChoices(p)
+	if len(choices) != 1 {
+		return nil
+	}
+	elements := choiceItemElements(choices[0])
+	if len(elements) == 0 {
+		return nil
+	}
+	counts := map[xml.Name]int{}
+	for _, elm := range mixedElements(content) {
+		counts[elm.name]++
+	}
+	for _, elm := range elements {
+		if counts[elm.name] != 1 || substitutionGroup(elm) != nil {
+			return nil
+		}
+	}
+	return choices[0]
+}
+
+// repeatedChoices returns the outermost repeated choice particles of a particle which are not in a repeated model
+// group.
+func repeatedChoices(p *particle) []*particle {
+	m, ok := p.term.(*modelGroup)
+	if !ok {
+		return nil
+	}
+	if m.compositor == "choice" && len(m.particles) > 1 && p.maxOccurs > 1 {
+		return []*particle{p}
+	}
+	if p.maxOccurs > 1 {
+		return nil
+	}
+	choices := make([]*particle, 0)
+	for _, child := range m.particles {
+		choices = append(choices, repeatedChoices(child)...)
+	}
+	return choices
+}
+
+// choiceItemElements returns the elements of a choice in document order, including elements of nested choices. It
+// returns nil if any member of the choice is neither an element nor a choice.
+func choiceItemElements(p *particle) []*elementDeclaration {
+	elements := make([]*elementDeclaration, 0)
+	for _, child := range p.term.(*modelGroup).particles {
+		switch term := child.term.(type) {
+		case *elementDeclaration:
+			elements = append(elements, term)
+		case *modelGroup:
+			if term.compositor != "choice" {
+				return nil
+			}
+			nested := choiceItemElements(child)
+			if nested == nil {
+				return nil
+			}
+			elements = append(elements, nested...)
+		default:
+			return nil
+		}
+	}
+	return elements
+}
+
+// instanceName returns the name of an element in instance documents. Unlike the name of a local element, it's
+// qualified by the element's target namespace.
+func instanceName(elm *elementDeclaration) xml.Name {
+	if elm.scope.variety == "local" {
+		return xml.Name{Space: elm.namespace, Local: elm.name.Local}
+	}
+	return elm.name
+}
+
+// createChoiceItemDecls creates the type of items of a repeated choice, which has a pointer field for each element of
+// the choice. Exactly one of the fields is set in an item, and elements which are not allowed in the choice are
+// rejected on decoding. Elements are told apart by their names in instance documents, including the namespace.
+func createChoiceItemDecls(f *File, typeDef *complexTypeDefinition) []Decl {
+	f.Require("encoding/xml")
+	f.Require("fmt")
+
+	typeName := typeDef.choice.goType
+	elements := choiceItemElements(typeDef.choice.particle)
+	names := make([]xml.Name, 0, len(elements))
+	locals := make([]string, 0, len(elements))
+	for _, elm := range elements {
+		names = append(names, elm.name)
+		locals = append(locals, elm.name.Local)
+	}
+	fields := f.names.fieldNames(names, map[string]bool{})
+
+	s := &StructType{}
+	which := "switch {\n"
+	unmarshal := "switch start.Name {\n"
+	marshal := "switch {\n"
+	validate := []Stmt{&AssignStmt{Define: true, Lhs: &Name{Value: "n"}, Rhs: &BasicLit{Value: "0"}}}
+	for _, elm := range elements {
+		field := fields[elm.name]
+		s.FieldList = append(s.FieldList, &Field{Name: &Name{Value: field}, Type: &PointerType{Elem: createElementRefType(f, elm)}})
+
+		value := "t." + field
+		which += "case " + value + " != nil:\nreturn " + strconv.Quote(elm.name.Local) + "\n"
+		validate = append(validate, &ExprStmt{X: &BasicLit{Value: "if " + value + " != nil {\nn++\n}"}})
+
+		name := "xml.Name{Space: " + strconv.Quote(instanceName(elm).Space) + ", Local: " + strconv.Quote(elm.name.Local) + "}"
+		unmarshal += "case " + name + ":\n" +
+			"return d.DecodeElement(&" + value + ", &start)\n"
+
+		marshal += "case " + value + " != nil:\n" +
+			"return e.EncodeElement(" + value + ", xml.StartElement{Name: " + name + "})\n"
+	}
+	which += "}"
+	unmarshal += "}"
+	marshal += "}"
+	validate = append(validate,
+		&ExprStmt{X: &BasicLit{
+			Value: "if n != 1 {\n" +
+				"return fmt.Errorf(" + strconv.Quote("exactly one of "+strings.Join(locals, ", ")+" must be present in "+typeName+", got %d") + ", n)\n" +
+				"}",
+		}},
+		&ReturnStmt{Results: &Name{Value: "nil"}},
+	)
+
+	return []Decl{
+		&TypeDecl{Name: &Name{Value: typeName}, Type: s},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "Which"},
+			Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "string"}}}},
+			Body: &BlockStmt{List: []Stmt{
+				&ExprStmt{X: &BasicLit{Value: which}},
+				&ReturnStmt{Results: &BasicLit{Value: `""`}},
+			}},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "Validate"},
+			Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "error"}}}},
+			Body: &BlockStmt{List: validate},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: []Stmt{
+				&ExprStmt{X: &BasicLit{Value: unmarshal}},
+				&ReturnStmt{Results: &BasicLit{
+					Value: "fmt.Errorf(" + strconv.Quote("element %s of namespace %q is not allowed in "+typeName) + ", start.Name.Local, start.Name.Space)",
+				}},
+			}},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: typeName}},
+			Name: &Name{Value: "MarshalXML"},
+			Type: marshalXMLFuncType(),
+			Body: &BlockStmt{List: []Stmt{
+				&ExprStmt{X: &BasicLit{Value: "if err := t.Validate(); err != nil {\nreturn err\n}"}},
+				&ExprStmt{X: &BasicLit{Value: marshal}},
+				&ReturnStmt{Results: &Name{Value: "nil"}},
+			}},
+		},
+	}
+}
+
+func xmlNameTag(name xml.Name) string {
+	xn := ""
+	if name.Space != "" {
+		xn += name.Space + " "
+	}
+	xn += name.Local
+	return xn
+}
+
+// polymorphicTypeName returns a name of the Go type holding a value of a complex type or of any type derived from it.
+func polymorphicTypeName(typeDef *complexTypeDefinition) string {
+	// The type is declared in the package of the complex type.
+	i := strings.LastIndex(typeDef.goType, ".") + 1
+	return typeDef.goType[:i] + "Any" + typeDef.goType[i:]
+}
+
+// createPolymorphicDecls creates an interface implemented by a complex type and the types derived from it, and a
+// type holding a value of any of them. The concrete type is selected by xsi:type when the value is unmarshalled and
+// written to xsi:type when it's marshalled. Abstract types and types derived by a method which is blocked by the
+// base type are rejected. A prefix of xsi:type which is declared by an ancestor of the element is resolved only if the
+// document is decoded by xs.NewDecoder.
+func createPolymorphicDecls(f *File, typeDef *complexTypeDefinition) []Decl {
+	if len(typeDef.derivedTypes) == 0 {
+		return nil
+	}
+
+	f.Require("encoding/xml")
+	f.Require("fmt")
+
+	interfaceName := typeDef.goType + "Value"
+	methodName := "is" + typeDef.goType
+	holderName := polymorphicTypeName(typeDef)
+
+	types := append([]*complexTypeDefinition{typeDef}, typeDef.derivedTypes...)
+	decls := []Decl{
+		&TypeDecl{
+			Name: &Name{Value: interfaceName},
+			Type: &BasicLit{Value: "interface {\n" + methodName + "()\n}"},
+		},
+	}
+	for _, t := range types {
+		decls = append(decls, &FuncDecl{
+			Recv: &Field{Type: &Name{Value: t.goType}},
+			Name: &Name{Value: methodName},
+			Type: &FuncType{},
+			Body: &BlockStmt{},
+		})
+	}
+	decls = append(decls, &TypeDecl{
+		Name: &Name{Value: holderName},
+		Type: &StructType{FieldList: []*Field{{Name: &Name{Value: "Value"}, Type: &Name{Value: interfaceName}}}},
+	})
+
+	typeName := func(t *complexTypeDefinition) string {
+		return `xml.Name{Space: "` + t.name.Space + `", Local: "` + t.name.Local + `"}`
+	}
+
+	unmarshal := "name, ok, err := xsiType(&start)\n" +
+		"if err != nil {\n" +
+		"return err\n" +
+		"}\n" +
+		"if !ok {\n"
+	if typeDef.abstract {
+		unmarshal += "return fmt.Errorf(\"xsi:type is required for the abstract type " + typeDef.name.Local + "\")\n"
+	} else {
+		unmarshal += "name = " + typeName(typeDef) + "\n"
+	}
+	unmarshal += "}\n" +
+		"switch name {\n"
+	marshal := "switch v := t.Value.(type) {\n" +
+		"case nil:\n" +
+		"return nil\n"
+	for _, t := range types {
+		if t.abstract || isBlockedDerivation(t, typeDef) {
+			continue
+		}
+		unmarshal += "case " + typeName(t) + ":\n" +
+			"var v " + t.goType + "\n" +
+			"if err := d.DecodeElement(&v, &start); err != nil {\n" +
+			"return err\n" +
+			"}\n" +
+			"t.Value = v\n" +
+			"return nil\n"
+		marshal += "case " + t.goType + ", *" + t.goType + ":\n"
+		if t != typeDef {
+			marshal += "start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, " + strconv.Quote(t.name.Space) + ", " +
+				strconv.Quote(t.name.Local) + ", " + strconv.Quote(xsiTypePrefix(t.name.Space)) + ")...)\n"
+		}
+		marshal += "return e.EncodeElement(v, start)\n"
+	}
+	unmarshal += "}\n" +
+		"return fmt.Errorf(\"xsi:type {%s}%s is not allowed for " + typeDef.name.Local + "\", name.Space, name.Local)"
+	marshal += "}\n" +
+		"return fmt.Errorf(\"%T is not allowed for " + typeDef.name.Local + "\", t.Value)"
+
+	return append(decls,
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: holderName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: []Stmt{&ExprStmt{X: &BasicLit{Value: unmarshal}}}},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: holderName}},
+			Name: &Name{Value: "MarshalXML"},
+			Type: marshalXMLFuncType(),
+			Body: &BlockStmt{List: []Stmt{&ExprStmt{X: &BasicLit{Value: marshal}}}},
+		},
+	)
+}
+
+// isBlockedDerivation reports whether a type derived from the base type uses a derivation method which is in the
+// {prohibited substitutions} of the base type.
+func isBlockedDerivation(typeDef *complexTypeDefinition, base *complexTypeDefinition) bool {
+	for t := typeDef; t != base; {
+		for _, method := range base.prohibitedSubstitutions {
+			if method == t.derivationMethod {
+				return true
+			}
+		}
+		next, ok := t.baseTypeDefinition.(*complexTypeDefinition)
+		if !ok {
+			break
+		}
+		t = next
+	}
+	return false
+}
+
+// xsiTypePrefix returns a prefix which is declared for the namespace of a type in the xsi:type attribute. It's named
+// after a hash of the namespace, so it doesn't shadow a prefix which the document declares for another namespace.
+func xsiTypePrefix(space string) string {
+	h := fnv.New32a()
+	h.Write([]byte(space))
+	return "ns" + strconv.FormatUint(uint64(h.Sum32()), 36)
+}
+
+// createXsiTypeDecls creates helpers which read and write the xsi:type attribute.
+func createXsiTypeDecls(f *File) []Decl {
+	f.Require("encoding/xml")
+	f.Require("fmt")
+	f.Require("strings")
+
+	return []Decl{
+		&ConstDecl{
+			NameList: []*Name{{Value: "xsiNamespace"}},
+			Values:   &BasicLit{Value: strconv.Quote(xsiNs)},
+		},
+		&FuncDecl{
+			Doc: "xsiType returns the type name of the xsi:type attribute of an element and removes the attribute.\n" +
+				"\n" +
+				"The decoder of encoding/xml doesn't report namespaces declared by ancestors of the element, so the\n" +
+				"prefix of the name is resolved only by declarations of the element itself. Decode by xs.NewDecoder,\n" +
+				"which copies them from the ancestors: with xml.NewDecoder or xml.Unmarshal a prefix declared by an\n" +
+				"ancestor is an error \"prefix ... is not declared\", and a name without a prefix has no namespace\n" +
+				"unless the element declares a default one.",
+			Name: &Name{Value: "xsiType"},
+			Type: &FuncType{
+				ParamList: []*Field{
+					{Name: &Name{Value: "start"}, Type: &PointerType{Elem: &BasicLit{Value: "xml.StartElement"}}},
+				},
+				ResultList: []*Field{{Type: &BasicLit{Value: "xml.Name"}}, {Type: &Name{Value: "bool"}}, {Type: &Name{Value: "error"}}},
+			},
+			Body: &BlockStmt{List: []Stmt{&ExprStmt{X: &BasicLit{
+				Value: "for i, attr := range start.Attr {\n" +
+					"if attr.Name.Space != xsiNamespace || attr.Name.Local != \"type\" {\n" +
+					"continue\n" +
+					"}\n" +
+					"start.Attr = append(start.Attr[:i:i], start.Attr[i+1:]...)\n" +
+					"prefix, local, found := strings.Cut(strings.TrimSpace(attr.Value), \":\")\n" +
+					"if !found {\n" +
+					"prefix, local = \"\", prefix\n" +
+					"}\n" +
+					"for _, ns := range start.Attr {\n" +
+					"if (prefix != \"\" && ns.Name.Space == \"xmlns\" && ns.Name.Local == prefix) || (prefix == \"\" && ns.Name.Space == \"\" && ns.Name.Local == \"xmlns\") {\n" +
+					"return xml.Name{Space: ns.Value, Local: local}, true, nil\n" +
+					"}\n" +
+					"}\n" +
+					"if prefix != \"\" {\n" +
+					"return xml.Name{}, true, fmt.Errorf(\"xsi:type %q: prefix %s is not declared\", attr.Value, prefix)\n" +
+					"}\n" +
+					"return xml.Name{Local: local}, true, nil\n" +
+					"}\n" +
+					"return xml.Name{}, false, nil",
+			}}}},
+		},
+		&FuncDecl{
+			Name: &Name{Value: "xsiTypeAttrs"},
+			Type: &FuncType{
+				ParamList: []*Field{
+					{Name: &Name{Value: "element"}, Type: &Name{Value: "string"}},
+					{Name: &Name{Value: "space"}, Type: &Name{Value: "string"}},
+					{Name: &Name{Value: "local"}, Type: &Name{Value: "string"}},
+					{Name: &Name{Value: "prefix"}, Type: &Name{Value: "string"}},
+				},
+				ResultList: []*Field{{Type: &BasicLit{Value: "[]xml.Attr"}}},
+			},
+			// The encoder declares a prefix of the xsi namespace, unless an ancestor has it already. The namespace of
+			// the element is its default namespace, so a type of the same namespace needs no prefix.
+			Body: &BlockStmt{List: []Stmt{&ExprStmt{X: &BasicLit{
+				Value: "attr := xml.Attr{Name: xml.Name{Space: xsiNamespace, Local: \"type\"}, Value: local}\n" +
+					"if space == \"\" || space == element {\n" +
+					"return []xml.Attr{attr}\n" +
+					"}\n" +
+					"attr.Value = prefix + \":\" + local\n" +
+					"return []xml.Attr{{Name: xml.Name{Local: \"xmlns:\" + prefix}, Value: space}, attr}",
+			}}}},
+		},
+	}
+}
+
+// substitutionGroup returns the elements which could appear in place of a head element, which is included unless
+// it's abstract, sorted by name. Abstract members and members whose type is derived by a method blocked by the head
+// are left out, so are members which are not generated in the file. It returns nil if no element could substitute
+// the head, or if the head is generated in another package.
+func substitutionGroup(head *elementDeclaration) []*elementDeclaration {
+	if len(head.substitutionGroupMembers) == 0 || head.goType == "" || isQualifiedGoType(head.goType) {
+		return nil
+	}
+	blocked := head.disallowedSubstitutions
+	if typeDef, ok := head.typeDefinition.(*complexTypeDefinition); ok {
+		blocked = append(append([]string{}, blocked...), typeDef.prohibitedSubstitutions...)
+	}
+	if containsString(blocked, "substitution") {
+		return nil
+	}
+
+	members := make([]*elementDeclaration, 0)
+	seen := map[*elementDeclaration]bool{}
+	var collect func(elm *elementDeclaration)
+	collect = func(elm *elementDeclaration) {
+		for _, member := range elm.substitutionGroupMembers {
+			if seen[member] {
+				continue
+			}
+			seen[member] = true
+			// Members of a member substitute the head too.
+			collect(member)
+			if member.abstract || member.goType == "" || isQualifiedGoType(member.goType) {
+				continue
+			}
+			methods, _ := derivationMethods(member.typeDefinition, head.typeDefinition)
+			allowed := true
+			for _, method := range methods {
+				allowed = allowed && !containsString(blocked, method)
+			}
+			if allowed {
+				members = append(members, member)
+			}
+		}
+	}
+	collect(head)
+	if len(members) == 0 {
+		return nil
+	}
+
+	if !head.abstract {
+		members = append(members, head)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].name.Local < members[j].name.Local })
+	return members
+}
+
+// substitutionGroupInterface returns a name of the interface implemented by elements of a substitution group.
+func substitutionGroupInterface(head *elementDeclaration) string {
+	return head.goType + "Element"
+}
+
+// substitutionGroupCollector returns a name of the slice type which collects elements of a substitution group.
+func substitutionGroupCollector(head *elementDeclaration) string {
+	r := []rune(head.goType)
+	return string(unicode.ToLower(r[0])) + string(r[1:]) + "Elements"
+}
+
+// createSubstitutionGroupDecls creates an interface implemented by elements of the head's substitution group and a
+// slice type which collects them by element names.
+func createSubstitutionGroupDecls(f *File, head *elementDeclaration) []Decl {
+	group := substitutionGroup(head)
+	if group == nil {
+		return nil
+	}
+
+	f.Require("encoding/xml")
+	f.Require("fmt")
+
+	interfaceName := substitutionGroupInterface(head)
+	methodName := "is" + interfaceName
+	collectorName := substitutionGroupCollector(head)
+
+	decls := []Decl{
+		&TypeDecl{
+			Name: &Name{Value: interfaceName},
+			Type: &BasicLit{Value: "interface {\n" + methodName + "()\n}"},
+		},
+	}
+	unmarshal := "switch start.Name {\n"
+	for _, elm := range group {
+		decls = append(decls, &FuncDecl{
+			Recv: &Field{Type: &Name{Value: elm.goType}},
+			Name: &Name{Value: methodName},
+			Type: &FuncType{},
+			Body: &BlockStmt{},
+		})
+		unmarshal += "case xml.Name{Space: \"" + elm.name.Space + "\", Local: \"" + elm.name.Local + "\"}:\n" +
+			"var v " + elm.goType + "\n" +
+			"if err := d.DecodeElement(&v, &start); err != nil {\n" +
+			"return err\n" +
+			"}\n" +
+			"*t = append(*t, v)\n" +
+			"return nil\n"
+	}
+	unmarshal += "}\n" +
+		"return fmt.Errorf(\"element {%s}%s is not allowed in place of " + head.name.Local + "\", start.Name.Space, start.Name.Local)"
+
+	return append(decls,
+		&TypeDecl{
+			Name: &Name{Value: collectorName},
+			Type: &SliceType{Elem: &Name{Value: interfaceName}},
+		},
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: collectorName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: []Stmt{&ExprStmt{X: &BasicLit{Value: unmarshal}}}},
+		},
+	)
+}
+
+type substitutionField struct {
+	head     *elementDeclaration
+	repeated bool
+}
+
+// substitutionFields returns fields of a complex type's struct which refer to heads of substitution groups.
+func substitutionFields(typeDef *complexTypeDefinition) []substitutionField {
+	p := structParticle(typeDef)
+	if p == nil {
+		return nil
+	}
+
+	fields := make([]substitutionField, 0)
+	seen := map[xml.Name]bool{}
+	var collect func(p *particle, repeated bool)
+	collect = func(p *particle, repeated bool) {
+		repeated = repeated || p.maxOccurs > 1
+		switch term := p.term.(type) {
+		case *elementDeclaration:
+			// The same rule as in createComplexTypeDeclType skips fields of an element which occurs again.
+			if !seen[term.name] && substitutionGroup(term) != nil {
+				fields = append(fields, substitutionField{head: term, repeated: repeated})
+			}
+			seen[term.name] = true
+		case *modelGroup:
+			for _, child := range term.particles {
+				collect(child, repeated)
+			}
+		}
+	}
+	collect(p, false)
+	return fields
+}
+
+// addSubstitutionShadowFields adds fields which collect elements of the head's substitution group into a variable to
+// a shadow struct and its literal.
+func addSubstitutionShadowFields(f *File, fields map[xml.Name]string, shadow *StructType, lit *CompositeLit, head *elementDeclaration, varName string) {
+	names := []xml.Name{head.name}
+	for _, elm := range substitutionGroup(head) {
+		if elm != head {
+			names = append(names, elm.name)
+		}
+	}
+	// The field of the head hides the field of the struct. Fields of other members are named apart from fields of the
+	// struct, which they would hide otherwise.
+	taken := map[string]bool{"XMLAlias": true}
+	for _, field := range fields {
+		taken[field] = true
+	}
+	for _, name := range names {
+		if hasFieldTag(shadow, xmlNameTag(name)) {
+			continue
+		}
+		fieldName := fields[head.name]
+		if name != head.name {
+			fieldName = f.names.name(name)
+			for i, base := 2, fieldName; taken[fieldName] || hasField(shadow, fieldName); i++ {
+				fieldName = base + strconv.Itoa(i)
+			}
+		}
+		shadow.FieldList = append(shadow.FieldList, &Field{
+			Name: &Name{Value: fieldName},
+			Type: &PointerType{Elem: &Name{Value: substitutionGroupCollector(head)}},
+			Tags: map[string]string{
+				"xml": xmlNameTag(name),
+			},
+		})
+		lit.ElemList = append(lit.ElemList, &KeyValueExpr{Key: &Name{Value: fieldName}, Value: &Name{Value: "&" + varName}})
+		lit.NKeys++
+	}
+}
+
+// createSubstitutionDecls creates an UnmarshalXML method for a type with fields which refer to heads of substitution
+// groups. The decoder matches a field by a single name, so a shadow struct has a field for every element of a
+// substitution group, and all of them collect elements into the same slice.
+func createSubstitutionDecls(f *File, typeName string, typeDef *complexTypeDefinition, hasXMLName bool) []Decl {
+	fields := substitutionFields(typeDef)
+	if len(fields) == 0 || hasAllGroup(typeDef) {
+		return nil
+	}
+	names := f.names.structFields(typeDef)
+
+	f.Require("encoding/xml")
+	f.Require("fmt")
+
+	shadow := &StructType{FieldList: []*Field{{Type: &PointerType{Elem: &Name{Value: "XMLAlias"}}}}}
+	init := &CompositeLit{
+		Type:     shadow,
+		ElemList: []Expr{&KeyValueExpr{Key: &Name{Value: "XMLAlias"}, Value: &BasicLit{Value: "(*XMLAlias)(t)"}}},
+		NKeys:    1,
+	}
+
+	body := []Stmt{}
+	if typeDef.attributeWildcard != nil {
+		body = append(body, stripNamespaceDeclsStmt())
+	}
+	body = append(body, &ExprStmt{X: &BasicLit{Value: "type XMLAlias " + typeName}})
+	checks := make([]Stmt, 0, len(fields))
+	for _, field := range fields {
+		fieldName := names[field.head.name]
+		varName := "elm" + fieldName
+		body = append(body, &ExprStmt{X: &BasicLit{Value: "var " + varName + " " + substitutionGroupCollector(field.head)}})
+		addSubstitutionShadowFields(f, names, shadow, init, field.head, varName)
+
+		if field.repeated {
+			checks = append(checks, &AssignStmt{Lhs: &Name{Value: "t." + fieldName}, Rhs: &Name{Value: varName}})
+			continue
+		}
+		checks = append(checks,
+			&ExprStmt{X: &BasicLit{
+				Value: "if len(" + varName + ") > 1 {\n" +
+					"return fmt.Errorf(" + strconv.Quote("element "+field.head.name.Local+" occurs %d times in "+typeName) + ", len(" + varName + "))\n" +
+					"}",
+			}},
+			&ExprStmt{X: &BasicLit{
+				Value: "if len(" + varName + ") == 1 {\n" +
+					"t." + fieldName + " = " + varName + "[0]\n" +
+					"}",
+			}},
+		)
+	}
+	body = append(body,
+		&AssignStmt{Define: true, Lhs: &Name{Value: "v"}, Rhs: init},
+		&ExprStmt{X: &BasicLit{Value: "if err := d.DecodeElement(&v, &start); err != nil {\nreturn err\n}"}},
+	)
+	if hasXMLName {
+		// The decoder doesn't set XMLName of an embedded struct.
+		body = append(body, &AssignStmt{Lhs: &Name{Value: "t.XMLName"}, Rhs: &Name{Value: "start.Name"}})
+	}
+	body = append(body, checks...)
+	if hasChoices(typeDef) {
+		// Choices are checked after decoding, see createChoiceDecls.
+		body = append(body, &ReturnStmt{Results: &BasicLit{Value: "t.Validate()"}})
+	} else {
+		body = append(body, &ReturnStmt{Results: &Name{Value: "nil"}})
+	}
+
+	return []Decl{
+		&FuncDecl{
+			Recv: &Field{Name: &Name{Value: "t"}, Type: &PointerType{Elem: &Name{Value: typeName}}},
+			Name: &Name{Value: "UnmarshalXML"},
+			Type: unmarshalXMLFuncType(),
+			Body: &BlockStmt{List: body},
+		},
 	}
-	xn += name.Local
-	return xn
 }
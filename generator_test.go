@@ -3,20 +3,21 @@ package goxsd
 import (
 	"bufio"
 	"encoding/xml"
-	"fmt"
-	"github.com/realmfoo/caementarii/xsd"
+	"github.com/realmfoo/caementarii/resolver"
+	"github.com/stretchr/testify/assert"
 	"os"
+	"strings"
 	"testing"
 )
 
 func TestGenerator(t *testing.T) {
-	data, err := os.ReadFile("tests/xmlschema/XMLSchema.xsd")
+	r := resolver.New(nil)
+	err := r.AddCatalog("tests/xmlschema/catalog.xml")
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	s := xsd.Schema{}
-	err = xml.Unmarshal(data, &s)
+	s, err := r.Load("tests/xmlschema/XMLSchema.xsd")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -25,27 +26,27 @@ func TestGenerator(t *testing.T) {
 	defer w.Flush()
 
 	g := Generator{
-		PkgName: "xmlschema",
-		ImportResolver: func(namespace string, schemaLocation string) (*xsd.Schema, error) {
-			if namespace != nsXml {
-				return nil, fmt.Errorf("could not find a location of %s", namespace)
-			}
-
-			data, err := os.ReadFile("tests/xmlschema/xml.xsd")
-			if err != nil {
-				return nil, err
-			}
-
-			s := xsd.Schema{}
-			err = xml.Unmarshal(data, &s)
-			if err != nil {
-				return nil, err
-			}
-			return &s, nil
-		},
+		PkgName:        "xmlschema",
+		ImportResolver: r.Resolve,
 	}
-	err = g.Generate(&s, w)
+	err = g.Generate(s, w)
 	if err != nil {
 		t.Fatal(err)
 	}
 }
+
+func TestInvalidPattern(t *testing.T) {
+	// A component which is not built by the parser may have a pattern which can't be translated.
+	typeDef := &simpleTypeDefinition{
+		name:               xml.Name{Space: "urn:test", Local: "code"},
+		goType:             "Code",
+		variety:            "atomic",
+		baseTypeDefinition: xmlTypes[xml.Name{Space: xmlNs, Local: "string"}],
+		facets:             []ConstrainingFacet{&patternFacet{value: []string{"[a-"}}},
+	}
+	f := &File{PkgName: "test", names: newNaming(nil)}
+	_, err := createRestrictedTypeDecls(f, typeDef)
+	if assert.Error(t, err) {
+		assert.True(t, strings.HasPrefix(err.Error(), "simple type "), err.Error())
+	}
+}
@@ -0,0 +1,246 @@
+package goxsd
+
+import (
+	"encoding/xml"
+	gotoken "go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// naming resolves Go identifiers of a generated file. Declared identifiers are unique in the file, and names of fields
+// are unique in their struct.
+type naming struct {
+	// overrides are Go names of components and fields by their XML names, see Generator.Names.
+	overrides map[xml.Name]string
+	declared  map[string]bool
+	// structs holds names of element fields of complex types' structs, see structFields.
+	structs map[*complexTypeDefinition]map[xml.Name]string
+}
+
+func newNaming(overrides map[xml.Name]string) *naming {
+	return &naming{
+		overrides: overrides,
+		declared:  make(map[string]bool),
+		structs:   make(map[*complexTypeDefinition]map[xml.Name]string),
+	}
+}
+
+// name returns a Go name of a component or a field, which is either an override or the sanitized local name.
+func (n *naming) name(name xml.Name) string {
+	if override, ok := n.overrides[name]; ok && override != "" {
+		if gotoken.IsIdentifier(override) && gotoken.IsExported(override) {
+			return override
+		}
+		return goName(override)
+	}
+	return goName(name.Local)
+}
+
+// declare declares a top-level identifier, and appends a number to it if it's already taken.
+func (n *naming) declare(name string) string {
+	for i, base := 2, name; n.declared[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	n.declared[name] = true
+	return name
+}
+
+// structFields returns names of the fields of elements in a complex type's struct by the elements' names, and names
+// the field of the type's repeated choice, if any, after the elements. Fields promoted from an embedded base type are
+// taken, since an own field of the same name would hide them.
+func (n *naming) structFields(typeDef *complexTypeDefinition) map[xml.Name]string {
+	if fields, ok := n.structs[typeDef]; ok {
+		return fields
+	}
+
+	taken := map[string]bool{}
+	if base, _, ok := embeddedBase(typeDef); ok {
+		taken[base.goType[strings.LastIndex(base.goType, ".")+1:]] = true
+		for _, field := range n.structFields(base) {
+			taken[field] = true
+		}
+		if base.choice != nil {
+			taken[base.choice.field] = true
+		}
+	}
+	var names []xml.Name
+	if p := structParticle(typeDef); p != nil {
+		names = particleElementNames(p, typeDef.choice)
+	}
+	fields := n.fieldNames(names, taken)
+	if typeDef.choice != nil {
+		field := "Choice"
+		for i := 2; taken[field] || isReservedField(field); i++ {
+			field = "Choice" + strconv.Itoa(i)
+		}
+		typeDef.choice.field = field
+	}
+	n.structs[typeDef] = fields
+	return fields
+}
+
+// fieldNames names fields of elements of a struct, apart from the taken names. Elements are named in the order of
+// their local names and then namespaces, so a name taken by several elements is kept by the first of them, and others
+// get a number. A name of a generated field or method gets the Element suffix.
+func (n *naming) fieldNames(names []xml.Name, taken map[string]bool) map[xml.Name]string {
+	sorted := make([]xml.Name, 0, len(names))
+	fields := make(map[xml.Name]string, len(names))
+	for _, name := range names {
+		if _, ok := fields[name]; !ok {
+			fields[name] = ""
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Sort(xmlNames(sorted))
+
+	for _, name := range sorted {
+		field := n.name(name)
+		if isReservedField(field) {
+			field += "Element"
+		}
+		for i, base := 2, field; taken[field] || isReservedField(field); i++ {
+			field = base + strconv.Itoa(i)
+		}
+		fields[name], taken[field] = field, true
+	}
+	return fields
+}
+
+// particleElementNames returns names of all elements of a particle in document order. Elements of a repeated choice
+// which is kept in document order are left out, since they are not fields of the struct.
+func particleElementNames(p *particle, choice *choiceItem) []xml.Name {
+	if choice != nil && p == choice.particle {
+		return nil
+	}
+	switch term := p.term.(type) {
+	case *elementDeclaration:
+		return []xml.Name{term.name}
+	case *modelGroup:
+		names := make([]xml.Name, 0)
+		for _, child := range term.particles {
+			names = append(names, particleElementNames(child, choice)...)
+		}
+		return names
+	}
+	return nil
+}
+
+// attributeField returns a name of the field of an attribute in a struct. An attribute which has the name of another
+// field of the struct gets the Attr suffix.
+func (n *naming) attributeField(s *StructType, name xml.Name, elements []*Field) string {
+	field := n.name(name)
+	taken := func(field string) bool {
+		if isReservedField(field) || hasField(s, field) {
+			return true
+		}
+		for _, elm := range elements {
+			if elm.Name != nil && elm.Name.Value == field {
+				return true
+			}
+		}
+		return false
+	}
+	if taken(field) {
+		field += "Attr"
+	}
+	for i, base := 2, field; taken(field); i++ {
+		field = base + strconv.Itoa(i)
+	}
+	return field
+}
+
+// isReservedField reports whether a name is taken by generated fields and methods of structs.
+func isReservedField(name string) bool {
+	switch name {
+	case "XMLName", "Any", "AnyAttrs", "Items", "Validate", "IsValid", "MarshalXML", "UnmarshalXML", "MarshalText", "UnmarshalText":
+		return true
+	}
+	// Choices are checked by Which, Which2 and so on.
+	if suffix := strings.TrimPrefix(name, "Which"); suffix != name {
+		if _, err := strconv.Atoi(suffix); suffix == "" || err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// commonInitialisms are words which are written in upper case in Go identifiers.
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true, "QPS": true,
+	"RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "URI": true, "URL": true, "UTF8": true,
+	"UUID": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// goName makes an exported Go identifier out of an XML name. Words are separated by characters which are not allowed
+// in identifiers and by changes of case, and each word is capitalized, or written in upper case if it's a common
+// initialism: order-line becomes OrderLine, and userId becomes UserID. A name which doesn't start with an upper case
+// letter then gets the X prefix, like X3dModel.
+func goName(name string) string {
+	s := joinWords(name)
+	if s == "" || !unicode.IsUpper([]rune(s)[0]) {
+		s = "X" + s
+	}
+	return s
+}
+
+// joinWords joins words of a name, which are capitalized or written in upper case if they are common initialisms.
+func joinWords(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
+
+// splitWords splits a name into words of letters and digits. A word ends before an upper case letter which follows a
+// lower case letter or a digit, and before the last upper case letter of an acronym which is followed by a lower case
+// word, so XMLHttpRequest is split into XML, Http and Request. A plural s stays with its acronym, like in IDs.
+func splitWords(name string) []string {
+	r := []rune(name)
+	words := make([]string, 0)
+	start := -1
+	for i, c := range r {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			if start >= 0 {
+				words = append(words, string(r[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		if !unicode.IsUpper(c) {
+			continue
+		}
+		prev := r[i-1]
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && isLowerWord(r[i+1:]) {
+			words = append(words, string(r[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(r[start:]))
+	}
+	return words
+}
+
+// isLowerWord reports whether runes start with a lower case word, which is not a plural s.
+func isLowerWord(r []rune) bool {
+	n := 0
+	for n < len(r) && unicode.IsLower(r[n]) {
+		n++
+	}
+	return n > 1 || n == 1 && r[0] != 's'
+}
@@ -0,0 +1,61 @@
+package goxsd
+
+import (
+	"encoding/xml"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestGoName(t *testing.T) {
+	cases := map[string]string{
+		"order":          "Order",
+		"order-line":     "OrderLine",
+		"order_line":     "OrderLine",
+		"order.line":     "OrderLine",
+		"orderLine":      "OrderLine",
+		"3dModel":        "X3dModel",
+		"type":           "Type",
+		"userId":         "UserID",
+		"xmlUrl":         "XMLURL",
+		"XMLHttpRequest": "XMLHTTPRequest",
+		"orderIDs":       "OrderIDs",
+		"identifier":     "Identifier",
+		"utf8String":     "UTF8String",
+		"ORDER":          "ORDER",
+		"名前":             "X名前",
+		"élan":           "Élan",
+	}
+	for name, expected := range cases {
+		assert.Equal(t, expected, goName(name), name)
+	}
+}
+
+func TestNamingFields(t *testing.T) {
+	n := newNaming(map[xml.Name]string{{Local: "ref"}: "Reference", {Local: "key"}: "lookup-key"})
+	fields := n.fieldNames([]xml.Name{
+		{Space: "urn:a", Local: "item"},
+		{Local: "item"},
+		{Local: "Item"},
+		{Local: "xmlName"},
+		{Local: "which"},
+		{Local: "ref"},
+		{Local: "key"},
+		{Local: "item"},
+	}, map[string]bool{"Text": true})
+	assert.Equal(t, map[xml.Name]string{
+		{Local: "Item"}:                 "Item",
+		{Local: "item"}:                 "Item2",
+		{Space: "urn:a", Local: "item"}: "Item3",
+		{Local: "xmlName"}:              "XMLNameElement",
+		{Local: "which"}:                "WhichElement",
+		{Local: "ref"}:                  "Reference",
+		{Local: "key"}:                  "LookupKey",
+	}, fields)
+
+	// Names are unique in a struct, so another struct has names of its own.
+	fields = n.fieldNames([]xml.Name{{Space: "urn:a", Local: "item"}, {Local: "text"}}, map[string]bool{"Text": true})
+	assert.Equal(t, map[xml.Name]string{{Space: "urn:a", Local: "item"}: "Item", {Local: "text"}: "Text2"}, fields)
+
+	assert.Equal(t, "Order", n.declare("Order"))
+	assert.Equal(t, "Order2", n.declare("Order"))
+}
@@ -0,0 +1,292 @@
+package goxsd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xsd"
+	gotoken "go/token"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// goPackage is a Go package which components of one or more target namespaces are generated into.
+type goPackage struct {
+	importPath string
+	name       string
+	// A name which other packages refer to the package by. It differs from the name of the package if the name is
+	// taken in the generated files, and then the package is imported under this name.
+	qualifier string
+	schemas   []*schema
+	// Packages of the namespaces imported by the schemas.
+	imports []*goPackage
+}
+
+// GeneratePackages generates the schema and the schemas it imports into a Go package per target namespace, see
+// Generator.Packages, and returns formatted sources of the packages by their import paths. Components of an imported
+// schema are generated once in the package of its namespace and referred to by the packages which import it.
+//
+// Types derived in another package can't replace their base types by xsi:type, and elements of another package can't
+// substitute the heads of their substitution groups. Namespaces which import each other must share a package.
+func (g *Generator) GeneratePackages(s *xsd.Schema) (map[string][]byte, error) {
+	root, err := parseSchema(s, g)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, 0, len(g.schemas))
+	for ns := range g.schemas {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	for _, ns := range namespaces {
+		if err := parseTopLevelComponents(g, g.schemas[ns]); err != nil {
+			return nil, err
+		}
+	}
+
+	packages, err := g.goPackages(root, namespaces)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string][]byte, len(packages))
+	qualifiers := make(map[string]*goPackage, len(packages))
+	for _, pkg := range packages {
+		schema := mergeSchemas(pkg.schemas)
+		file, err := toGoFile(pkg.name, schema, g.goTypes(), g.Mixed, g.Names, qualifiers)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pkg.importPath, err)
+		}
+		source, err := formatFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pkg.importPath, err)
+		}
+		sources[pkg.importPath] = source
+
+		// Packages which are generated later refer to the components of this one by qualified names.
+		qualifyGoTypes(schema, pkg.qualifier)
+		qualifiers[pkg.qualifier] = pkg
+	}
+	return sources, nil
+}
+
+// goPackages groups the schemas into packages, and returns them in the order they could be generated, so a package
+// follows the packages it imports.
+func (g *Generator) goPackages(root *schema, namespaces []string) ([]*goPackage, error) {
+	if g.ImportPath == "" && g.Packages[root.targetNamespace] == "" {
+		return nil, fmt.Errorf("The import path of the package of namespace %q is unknown", root.targetNamespace)
+	}
+
+	importPaths := make(map[string]string, len(namespaces))
+	taken := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		if p, ok := g.Packages[ns]; ok {
+			importPaths[ns], taken[p] = p, true
+		}
+	}
+	if _, ok := importPaths[root.targetNamespace]; !ok {
+		importPaths[root.targetNamespace], taken[g.ImportPath] = g.ImportPath, true
+	}
+	for _, ns := range namespaces {
+		if _, ok := importPaths[ns]; ok {
+			continue
+		}
+		p := path.Join(g.ImportPath, namespacePackageName(ns))
+		for i, base := 2, p; taken[p]; i++ {
+			p = base + strconv.Itoa(i)
+		}
+		importPaths[ns], taken[p] = p, true
+	}
+
+	byPath := make(map[string]*goPackage, len(namespaces))
+	paths := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		p := importPaths[ns]
+		pkg, ok := byPath[p]
+		if !ok {
+			pkg = &goPackage{importPath: p, name: packageName(p)}
+			if p == importPaths[root.targetNamespace] && g.PkgName != "" {
+				pkg.name = g.PkgName
+			}
+			byPath[p] = pkg
+			paths = append(paths, p)
+		}
+		pkg.schemas = append(pkg.schemas, g.schemas[ns])
+	}
+	sort.Strings(paths)
+
+	// Name packages in other packages after themselves, unless the name is taken by an import or an identifier of
+	// generated files, or by another package.
+	qualifiers := map[string]bool{"xs": true}
+	for _, p := range paths {
+		pkg := byPath[p]
+		q := pkg.name
+		if qualifiers[q] || reservedQualifiers[q] || gotoken.IsKeyword(q) || len(q) < 3 {
+			q += "ns"
+		}
+		for i, base := 2, q; qualifiers[q]; i++ {
+			q = base + strconv.Itoa(i)
+		}
+		pkg.qualifier, qualifiers[q] = q, true
+	}
+
+	for _, p := range paths {
+		pkg := byPath[p]
+		imported := map[*goPackage]bool{}
+		for _, s := range pkg.schemas {
+			for _, doc := range s.documents {
+				for _, composition := range doc.xsdSchema.Composition {
+					node, ok := composition.(xsd.Import)
+					if !ok {
+						continue
+					}
+					ns := node.Namespace
+					if ns == "" && node.SchemaLocation != "" {
+						ns = importedNamespace(g, doc, node)
+					}
+					dep, ok := byPath[importPaths[ns]]
+					if ok && dep != pkg && !imported[dep] {
+						imported[dep] = true
+						pkg.imports = append(pkg.imports, dep)
+					}
+				}
+			}
+		}
+	}
+
+	// Sort packages topologically, visiting them in the order of their import paths.
+	packages := make([]*goPackage, 0, len(paths))
+	state := make(map[*goPackage]int, len(paths))
+	var visit func(pkg *goPackage) error
+	visit = func(pkg *goPackage) error {
+		switch state[pkg] {
+		case 1:
+			return fmt.Errorf("Package %s imports itself. Namespaces which import each other must be generated into one package", pkg.importPath)
+		case 2:
+			return nil
+		}
+		state[pkg] = 1
+		for _, dep := range pkg.imports {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[pkg] = 2
+		packages = append(packages, pkg)
+		return nil
+	}
+	for _, p := range paths {
+		if err := visit(byPath[p]); err != nil {
+			return nil, err
+		}
+	}
+	return packages, nil
+}
+
+// importedNamespace returns the target namespace of a schema document imported without a namespace attribute.
+func importedNamespace(g *Generator, doc *schema, node xsd.Import) string {
+	is, err := g.resolveSchema(doc.xsdSchema, node.Namespace, node.SchemaLocation)
+	if err != nil {
+		return ""
+	}
+	return is.TargetNamespace
+}
+
+// reservedQualifiers are names of packages and identifiers used by generated files, which other packages can't be
+// referred to by.
+var reservedQualifiers = map[string]bool{
+	"xml": true, "fmt": true, "io": true, "math": true, "regexp": true, "strconv": true, "strings": true,
+	"time": true, "any": true, "bool": true, "byte": true, "error": true, "float32": true, "float64": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true, "rune": true, "string": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "append": true, "len": true,
+	"make": true, "new": true, "nil": true, "true": true, "false": true, "item": true, "text": true, "start": true,
+	"tok": true, "err": true,
+}
+
+// ignoredNamespaceWords are words of namespace names which don't tell one namespace from another.
+var ignoredNamespaceWords = map[string]bool{
+	"namespace": true, "ns": true, "schema": true, "schemas": true, "xsd": true, "xmlschema": true,
+}
+
+// namespacePackageName derives a package name from a namespace name. It's the last word of the namespace which
+// starts with a letter and tells the namespace apart, like xml for http://www.w3.org/XML/1998/namespace.
+func namespacePackageName(ns string) string {
+	words := strings.FieldsFunc(ns, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i := len(words) - 1; i >= 0; i-- {
+		word := strings.ToLower(words[i])
+		if unicode.IsLetter([]rune(word)[0]) && !ignoredNamespaceWords[word] {
+			return word
+		}
+	}
+	return "ns"
+}
+
+// packageName returns a name of the package of an import path, which is its last element without characters
+// which are not allowed in identifiers.
+func packageName(importPath string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, path.Base(importPath))
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) || gotoken.IsKeyword(name) {
+		name = "ns" + name
+	}
+	return name
+}
+
+// mergeSchemas returns a schema with the components of the schemas, which are generated into one package.
+func mergeSchemas(schemas []*schema) *schema {
+	if len(schemas) == 1 {
+		return schemas[0]
+	}
+	m := &schema{
+		typeDefinitions:     make(map[xml.Name]TypeDefinition),
+		elementDeclarations: make(map[xml.Name]*elementDeclaration),
+	}
+	for _, s := range schemas {
+		for k, v := range s.typeDefinitions {
+			m.typeDefinitions[k] = v
+		}
+		for k, v := range s.elementDeclarations {
+			m.elementDeclarations[k] = v
+		}
+		m.documents = append(m.documents, s.documents...)
+	}
+	return m
+}
+
+// qualifyGoTypes qualifies the names of Go types of the schema's components by the package qualifier.
+func qualifyGoTypes(schema *schema, qualifier string) {
+	qualify := func(goType *string) {
+		if *goType != "" {
+			*goType = qualifier + "." + *goType
+		}
+	}
+	for _, typeDef := range schema.typeDefinitions {
+		if t, ok := typeDef.(*complexTypeDefinition); ok {
+			qualify(&t.goType)
+			qualify(&t.itemType)
+		}
+	}
+	for _, t := range declaredSimpleTypes(schema) {
+		qualify(&t.goType)
+	}
+	for _, elm := range schema.elementDeclarations {
+		qualify(&elm.goType)
+		if t, ok := elm.typeDefinition.(*complexTypeDefinition); ok && t.goType == "" {
+			qualify(&t.itemType)
+		}
+	}
+}
+
+// isQualifiedGoType reports whether a Go type of a component is declared in another package.
+func isQualifiedGoType(goType string) bool {
+	return strings.Contains(goType, ".")
+}
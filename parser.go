@@ -4,6 +4,8 @@ import (
 	"encoding/xml"
 	"fmt"
 	"github.com/realmfoo/caementarii/xsd"
+	"net/url"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -13,29 +15,59 @@ func parseSchema(xs *xsd.Schema, g *Generator) (*schema, error) {
 	s := newSchema(xs)
 	g.schemas = make(map[string]*schema, 4)
 	g.schemas[s.targetNamespace] = s
+	g.redefining = make(map[componentKey]*redefinition)
 	err := processImports(xs, g, g.schemas)
 	if err != nil {
 		return nil, err
 	}
-	for _, top := range xs.SchemaTop {
-		if node, ok := top.(xsd.Element); ok {
-			// 3.3.2.1 Common Mapping Rules for Element Declarations
-			elm, err := g.newElement(s, &node)
-			if err != nil {
-				return nil, err
-			}
-
-			// 3.3.2.2 Mapping Rules for Top-Level Element Declarations
-			elm.name.Space = xs.TargetNamespace
-
-			elm.scope.variety = "global"
+	if err := processIncludes(g, s, s, nil); err != nil {
+		return nil, err
+	}
+	if err := parseTopLevelComponents(g, s); err != nil {
+		return nil, err
+	}
 
-			s.elementDeclarations[elm.name] = elm
+	// Members of a substitution group could be declared in any of the schemas, so elements which declare a
+	// substitution group are resolved in the imported schemas too.
+	for _, is := range g.schemas {
+		for _, top := range is.topLevelComponents() {
+			if node, ok := top.(xsd.Element); ok && node.SubstitutionGroup != "" {
+				if _, err := g.resolveElement(xml.Name{Space: is.targetNamespace, Local: node.Name}); err != nil {
+					return nil, err
+				}
+			}
 		}
 	}
 	return s, nil
 }
 
+// parseTopLevelComponents parses top-level elements, model groups, attribute groups and complex types of a schema.
+func parseTopLevelComponents(g *Generator, s *schema) error {
+	for _, top := range s.topLevelComponents() {
+		switch node := top.(type) {
+		case xsd.Element:
+			if _, err := g.resolveElement(xml.Name{Space: s.targetNamespace, Local: node.Name}); err != nil {
+				return err
+			}
+		case xsd.Group:
+			if _, err := g.resolveModelGroup(xml.Name{Space: s.targetNamespace, Local: node.Name}); err != nil {
+				return err
+			}
+		case xsd.AttributeGroup:
+			if _, err := g.resolveAttributeGroup(xml.Name{Space: s.targetNamespace, Local: node.Name}); err != nil {
+				return err
+			}
+		case xsd.ComplexType:
+			// Named complex types are parsed even if no element refers to them, so each of them could be
+			// generated as a distinct Go type.
+			if _, err := g.resolveType(xml.Name{Space: s.targetNamespace, Local: node.Name}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func processImports(xs *xsd.Schema, g *Generator, schemas map[string]*schema) error {
 	for _, composition := range xs.Composition {
 		if node, ok := composition.(xsd.Import); ok {
@@ -47,7 +79,7 @@ func processImports(xs *xsd.Schema, g *Generator, schemas map[string]*schema) er
 					}
 				}
 
-				es, err := g.ImportResolver(node.Namespace, node.SchemaLocation)
+				es, err := g.resolveSchema(xs, node.Namespace, node.SchemaLocation)
 				if err != nil {
 					return err
 				}
@@ -65,6 +97,9 @@ func processImports(xs *xsd.Schema, g *Generator, schemas map[string]*schema) er
 				if err := processImports(es, g, schemas); err != nil {
 					return err
 				}
+				if err := processIncludes(g, schemas[ns], schemas[ns], nil); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -72,6 +107,298 @@ func processImports(xs *xsd.Schema, g *Generator, schemas map[string]*schema) er
 	return nil
 }
 
+// resolveSchema returns a schema document which is referred to by the base schema document.
+func (g *Generator) resolveSchema(base *xsd.Schema, namespace string, location string) (*xsd.Schema, error) {
+	if g.ImportResolver == nil {
+		return nil, fmt.Errorf("Could not resolve a schema document %s %s: ImportResolver is not set", namespace, location)
+	}
+	location, err := resolveLocation(base, location)
+	if err != nil {
+		return nil, err
+	}
+	return g.ImportResolver(namespace, location)
+}
+
+// resolveLocation resolves a location of a schema document against the Location of the base schema document, if
+// it's known.
+func resolveLocation(base *xsd.Schema, location string) (string, error) {
+	if location == "" || base.Location == "" {
+		return location, nil
+	}
+	b, err := url.Parse(base.Location)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return "", err
+	}
+	return b.ResolveReference(ref).String(), nil
+}
+
+// includedDocument returns a document of the schema which is the schema document at the location. A document
+// without a known location is compared by identity.
+func (s *schema) includedDocument(is *xsd.Schema, location string) *schema {
+	for _, doc := range s.documents {
+		if doc.xsdSchema == is || location != "" && doc.location == location {
+			return doc
+		}
+	}
+	return nil
+}
+
+// processIncludes adds schema documents which are included, redefined or overridden by a schema document to the
+// documents of the schema, see 4.2.3 Including modules of a schema, 4.2.4 Including modules of a schema with
+// modifications and 4.2.5 Overriding component definitions. Replaced components are dropped from the top-level
+// components of the included document, and the replacing components are added to the including one. Components
+// replaced by an override are replaced in the documents included by the overridden document too.
+//
+// A document which is already a document of the schema is included once, so documents could include each other. A
+// document is redefined or overridden by different documents as many times as it's referred to, unless it's one of
+// the documents which include the referring one, since its components are already there.
+func processIncludes(g *Generator, s *schema, doc *schema, overridden map[componentKey]bool) error {
+	for _, composition := range doc.xsdSchema.Composition {
+		var location string
+		var components []interface{}
+		redefine := false
+		switch node := composition.(type) {
+		case xsd.Include:
+			location = node.SchemaLocation
+		case xsd.Redefine:
+			location, redefine = node.SchemaLocation, true
+			components = redefinedComponents(node)
+		case xsd.Override:
+			location = node.SchemaLocation
+			components = overriddenComponents(node)
+		default:
+			continue
+		}
+		if location == "" {
+			return fmt.Errorf("The schemaLocation attribute of an included schema document is required.")
+		}
+
+		is, err := g.resolveSchema(doc.xsdSchema, "", location)
+		if err != nil {
+			return err
+		}
+		if is.TargetNamespace != "" && is.TargetNamespace != s.targetNamespace {
+			return fmt.Errorf("Included XMLSchema has different targetNamespace. Expected %s, but found %s", s.targetNamespace, is.TargetNamespace)
+		}
+		resolved := is.Location
+		if resolved == "" {
+			if resolved, err = resolveLocation(doc.xsdSchema, location); err != nil {
+				return err
+			}
+		}
+		if existing := s.includedDocument(is, resolved); existing != nil {
+			if len(components) == 0 || existing.includes(doc) {
+				continue
+			}
+		}
+		included := s.newIncludedSchema(is)
+		included.location, included.includedBy = resolved, doc
+
+		replaced := make(map[componentKey]bool, len(components))
+		for _, c := range components {
+			key, _ := topLevelKey(s.targetNamespace, c)
+			replaced[key] = true
+			doc.schemaTop = append(doc.schemaTop, c)
+		}
+		if !redefine {
+			for key := range overridden {
+				replaced[key] = true
+			}
+		}
+
+		schemaTop := make([]interface{}, 0, len(included.schemaTop))
+		for _, top := range included.schemaTop {
+			if key, ok := topLevelKey(s.targetNamespace, top); ok && replaced[key] {
+				if redefine {
+					doc.redefinitions[key] = &redefinition{document: included, node: top}
+				}
+				continue
+			}
+			schemaTop = append(schemaTop, top)
+		}
+		included.schemaTop = schemaTop
+
+		// A component could be redefined only if the redefined document declares it.
+		for key := range replaced {
+			if _, ok := doc.redefinitions[key]; redefine && !ok {
+				return fmt.Errorf("Redefined component '%s' is not found in %s.", xmlNameAsString(key.name), location)
+			}
+		}
+
+		s.documents = append(s.documents, included)
+		if err := processImports(is, g, g.schemas); err != nil {
+			return err
+		}
+		if redefine {
+			replaced = nil
+		}
+		if err := processIncludes(g, s, included, replaced); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// includes reports whether the document includes, redefines or overrides another one, directly or by the documents
+// it refers to, or it's that document.
+func (s *schema) includes(doc *schema) bool {
+	for d := doc; d != nil; d = d.includedBy {
+		if d == s {
+			return true
+		}
+	}
+	return false
+}
+
+// redefinedComponents returns components which are redefined by <redefine>.
+func redefinedComponents(node xsd.Redefine) []interface{} {
+	components := make([]interface{}, 0)
+	for _, c := range node.SimpleTypes {
+		components = append(components, c)
+	}
+	for _, c := range node.ComplexTypes {
+		components = append(components, c)
+	}
+	for _, c := range node.Groups {
+		components = append(components, c)
+	}
+	for _, c := range node.AttributeGroups {
+		components = append(components, c)
+	}
+	return components
+}
+
+// overriddenComponents returns components which are overridden by <override>.
+func overriddenComponents(node xsd.Override) []interface{} {
+	components := redefinedComponents(xsd.Redefine{
+		SimpleTypes:     node.SimpleTypes,
+		ComplexTypes:    node.ComplexTypes,
+		Groups:          node.Groups,
+		AttributeGroups: node.AttributeGroups,
+	})
+	for _, c := range node.Elements {
+		components = append(components, c)
+	}
+	for _, c := range node.Attributes {
+		components = append(components, c)
+	}
+	return components
+}
+
+// topLevelKey returns a key of the top-level component declared by an element of a schema document.
+func topLevelKey(ns string, top interface{}) (componentKey, bool) {
+	switch t := top.(type) {
+	case xsd.XMLTopLevelSimpleType:
+		return componentKey{space: "type", name: xml.Name{Space: ns, Local: string(t.Name)}}, true
+	case xsd.ComplexType:
+		return componentKey{space: "type", name: xml.Name{Space: ns, Local: t.Name}}, true
+	case xsd.Group:
+		return componentKey{space: "group", name: xml.Name{Space: ns, Local: t.Name}}, true
+	case xsd.AttributeGroup:
+		return componentKey{space: "attributeGroup", name: xml.Name{Space: ns, Local: t.Name}}, true
+	case xsd.Element:
+		return componentKey{space: "element", name: xml.Name{Space: ns, Local: t.Name}}, true
+	case xsd.Attribute:
+		return componentKey{space: "attribute", name: xml.Name{Space: ns, Local: t.Name}}, true
+	}
+	return componentKey{}, false
+}
+
+// topLevelComponents returns the top-level components of all documents of the schema.
+func (s *schema) topLevelComponents() []interface{} {
+	components := make([]interface{}, 0)
+	for _, doc := range s.documents {
+		components = append(components, doc.schemaTop...)
+	}
+	return components
+}
+
+// findTopLevel finds a top-level component of the schema, and returns it along with the schema document which
+// declares it. It returns a nil document if the schema has no such component.
+func (s *schema) findTopLevel(key componentKey) (*schema, interface{}) {
+	for _, doc := range s.documents {
+		for _, top := range doc.schemaTop {
+			if k, ok := topLevelKey(s.targetNamespace, top); ok && k == key {
+				return doc, top
+			}
+		}
+	}
+	return nil, nil
+}
+
+// redefine makes references to a component refer to its original component while the component which redefines it
+// is being parsed, and returns a function which ends it.
+func (g *Generator) redefine(doc *schema, key componentKey) func() {
+	if doc == nil || doc.redefinitions[key] == nil {
+		return func() {}
+	}
+	g.redefining[key] = doc.redefinitions[key]
+	return func() {
+		delete(g.redefining, key)
+	}
+}
+
+// resolveRedefined parses the original component of a component which is being redefined. It reports false if the
+// component is not being redefined. The original component is not a component of the schema, so it's unregistered
+// from the schema after it's parsed, and references to the component from the original component refer to the
+// redefined one.
+func (g *Generator) resolveRedefined(key componentKey) (interface{}, bool, error) {
+	r, ok := g.redefining[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if r.component != nil {
+		return r.component, true, nil
+	}
+
+	delete(g.redefining, key)
+	defer func() {
+		g.redefining[key] = r
+	}()
+
+	doc := r.document
+	switch t := r.node.(type) {
+	case xsd.XMLTopLevelSimpleType:
+		prev := doc.typeDefinitions[key.name]
+		typeDef, err := g.newSimpleType(doc, nil, &t)
+		if err != nil {
+			return nil, true, err
+		}
+		doc.typeDefinitions[key.name] = prev
+		r.component = typeDef
+	case xsd.ComplexType:
+		prev := doc.typeDefinitions[key.name]
+		typeDef, err := g.newComplexType(doc, nil, &t)
+		if err != nil {
+			return nil, true, err
+		}
+		doc.typeDefinitions[key.name] = prev
+		r.component = typeDef
+	case xsd.Group:
+		prev := doc.modelGroupDefinitions[key.name]
+		def, err := g.newModelGroupDefinition(doc, &t)
+		if err != nil {
+			return nil, true, err
+		}
+		doc.modelGroupDefinitions[key.name] = prev
+		r.component = def
+	case xsd.AttributeGroup:
+		prev := doc.attributeGroupDefinitions[key.name]
+		def, err := g.newAttributeGroupDefinition(doc, &t)
+		if err != nil {
+			return nil, true, err
+		}
+		doc.attributeGroupDefinitions[key.name] = prev
+		r.component = def
+	}
+	return r.component, true, nil
+}
+
 func (g *Generator) newSimpleType(s *schema, parent interface{}, node *xsd.XMLTopLevelSimpleType) (*simpleTypeDefinition, error) {
 	var err error
 
@@ -82,15 +409,50 @@ func (g *Generator) newSimpleType(s *schema, parent interface{}, node *xsd.XMLTo
 	// The ·actual value· of the targetNamespace [attribute] of the <schema> ancestor element information item if present, otherwise ·absent·.
 	typeDef.name.Space = s.targetNamespace
 
+	// Only named type definitions are schema components that could be referenced by a QName.
+	if typeDef.name.Local != "" {
+		s.typeDefinitions[typeDef.name] = &typeDef
+	} else {
+		// If the name [attribute] is present, then ·absent·, otherwise the appropriate case among the following:
+		// an Attribute Declaration, an Element Declaration, a Complex Type Definition or a Simple Type Definition
+		// corresponding to the nearest ancestor.
+		typeDef.context = parent
+		s.anonymousTypeDefinitions = append(s.anonymousTypeDefinitions, &typeDef)
+	}
+
 	if node.Restriction != nil {
 		// The type definition ·resolved· to by the ·actual value· of the base [attribute] on the <restriction> or
 		// <extension> element appearing as a child of <simpleContent>, if present, otherwise the type definition
 		// corresponding to the <simpleType> among the [children] of <restriction>.
-		typeDef.baseTypeDefinition, err = g.resolveType(s.resolveQName(node.Restriction.Base))
+		if node.Restriction.Base != "" {
+			typeDef.baseTypeDefinition, err = g.resolveType(s.resolveQName(node.Restriction.Base))
+		} else if node.Restriction.SimpleType != nil {
+			typeDef.baseTypeDefinition, err = g.newSimpleType(s, &typeDef, &xsd.XMLTopLevelSimpleType{SimpleType: node.Restriction.SimpleType.SimpleType})
+		} else {
+			err = fmt.Errorf("Either the base [attribute] or the <simpleType> [child] of <restriction> must be present.")
+		}
+		if err != nil {
+			return nil, err
+		}
+		base, ok := typeDef.baseTypeDefinition.(*simpleTypeDefinition)
+		if !ok {
+			return nil, fmt.Errorf("The base type of a simple type should be a simple type")
+		}
+		typeDef.variety = base.variety
+		// The {item type definition} and {member type definitions} of the {base type definition}.
+		typeDef.itemTypeDefinition = base.itemTypeDefinition
+		typeDef.memberTypeDefinitions = base.memberTypeDefinitions
+
+		// The {facets} of the {base type definition} with the facets specified in <restriction> replacing those of
+		// the same kind.
+		facets, err := newFacets(&node.Restriction.XMLSimpleRestrictionModel)
+		if err != nil {
+			return nil, err
+		}
+		typeDef.facets, err = restrictFacets(base.facets, facets)
 		if err != nil {
 			return nil, err
 		}
-		typeDef.variety = typeDef.baseTypeDefinition.(*simpleTypeDefinition).variety
 	} else if node.List != nil {
 		typeDef.baseTypeDefinition = anySimpleType
 		if node.List.ItemType != "" {
@@ -99,27 +461,224 @@ func (g *Generator) newSimpleType(s *schema, parent interface{}, node *xsd.XMLTo
 			if err != nil {
 				return nil, err
 			}
-			typeDef.itemTypeDefinition = itemTypeDefinition.(*simpleTypeDefinition)
-		} else {
-			var itemTypeDefinition TypeDefinition
-			itemTypeDefinition, err = g.resolveType(s.resolveQName(node.List.SimpleType.Union.MemberTypes[0]))
+			if itemTypeDefinition != nil {
+				item, ok := itemTypeDefinition.(*simpleTypeDefinition)
+				if !ok {
+					return nil, fmt.Errorf("The item type %s of a list should be a simple type", node.List.ItemType)
+				}
+				typeDef.itemTypeDefinition = item
+			}
+		} else if node.List.SimpleType != nil {
+			typeDef.itemTypeDefinition, err = g.newSimpleType(s, &typeDef, &xsd.XMLTopLevelSimpleType{SimpleType: *node.List.SimpleType})
 			if err != nil {
 				return nil, err
 			}
-			typeDef.itemTypeDefinition = itemTypeDefinition.(*simpleTypeDefinition)
+		} else {
+			return nil, fmt.Errorf("Either the itemType [attribute] or the <simpleType> [child] of <list> must be present.")
 		}
-		typeDef.goType = "[]" + typeDef.itemTypeDefinition.goType
 		typeDef.variety = "list"
+		// A whiteSpace facet with {value} = collapse and {fixed} = true.
+		typeDef.facets = []ConstrainingFacet{&whiteSpaceFacet{value: "collapse", fixed: true}}
 	} else if node.Union != nil {
 		typeDef.baseTypeDefinition = anySimpleType
 		typeDef.variety = "union"
-	}
 
-	s.typeDefinitions[typeDef.name] = &typeDef
+		// The sequence of Simple Type Definitions ·resolved· to by the items in the ·actual value· of the memberTypes
+		// [attribute] of <union>, if any, followed by the Simple Type Definitions corresponding to the <simpleType>s
+		// among the [children] of <union>, if any, in order.
+		typeDef.memberTypeDefinitions = make([]TypeDefinition, 0, len(node.Union.MemberTypes)+len(node.Union.SimpleTypes))
+		for _, memberType := range node.Union.MemberTypes {
+			member, err := g.resolveType(s.resolveQName(memberType))
+			if err != nil {
+				return nil, err
+			}
+			if member == nil {
+				// An unknown type is ignored as everywhere else.
+				continue
+			}
+			if _, ok := member.(*simpleTypeDefinition); !ok {
+				return nil, fmt.Errorf("The member type %s of a union should be a simple type", memberType)
+			}
+			typeDef.memberTypeDefinitions = append(typeDef.memberTypeDefinitions, member)
+		}
+		for _, st := range node.Union.SimpleTypes {
+			member, err := g.newSimpleType(s, &typeDef, &xsd.XMLTopLevelSimpleType{SimpleType: *st})
+			if err != nil {
+				return nil, err
+			}
+			typeDef.memberTypeDefinitions = append(typeDef.memberTypeDefinitions, member)
+		}
+		if len(typeDef.memberTypeDefinitions) == 0 {
+			return nil, fmt.Errorf("Either the memberTypes [attribute] or a <simpleType> [child] of <union> must be present.")
+		}
+	}
 
 	return &typeDef, nil
 }
 
+// newFacets creates constraining facets specified in <restriction> of a simple type.
+func newFacets(node *xsd.XMLSimpleRestrictionModel) ([]ConstrainingFacet, error) {
+	facets := []ConstrainingFacet{}
+
+	// 4.3.1.2 - 4.3.3.2 XML Representation of length, minLength and maxLength Schema Components
+	for _, f := range node.Length {
+		facets = append(facets, &lengthFacet{numFacet{value: f.Value, fixed: isTrue(f.Fixed)}})
+	}
+	for _, f := range node.MinLength {
+		facets = append(facets, &minLengthFacet{numFacet{value: f.Value, fixed: isTrue(f.Fixed)}})
+	}
+	for _, f := range node.MaxLength {
+		facets = append(facets, &maxLengthFacet{numFacet{value: f.Value, fixed: isTrue(f.Fixed)}})
+	}
+
+	// 4.3.4.2 XML Representation of pattern Schema Components
+	// The alternatives of the ·actual value·s of the value [attribute]s of all <pattern> element information items
+	// among the [children] of <restriction>.
+	if len(node.Pattern) > 0 {
+		alternatives := make([]string, 0, len(node.Pattern))
+		for _, f := range node.Pattern {
+			if _, err := translatePattern(f.Value); err != nil {
+				return nil, err
+			}
+			alternatives = append(alternatives, "("+f.Value+")")
+		}
+		value := strings.Join(alternatives, "|")
+		if len(alternatives) == 1 {
+			value = node.Pattern[0].Value
+		}
+		facets = append(facets, &patternFacet{value: []string{value}})
+	}
+
+	// 4.3.5.2 XML Representation of enumeration Schema Components
+	// The appropriate set of values from the ·actual value·s of the value [attribute]s of all <enumeration> element
+	// information items among the [children] of <restriction>.
+	if len(node.Enumeration) > 0 {
+		f := &enumerationFacet{value: make([]string, 0, len(node.Enumeration))}
+		for _, e := range node.Enumeration {
+			f.value = append(f.value, e.Value)
+		}
+		facets = append(facets, f)
+	}
+
+	// 4.3.6.2 XML Representation of whiteSpace Schema Components
+	for _, f := range node.WhiteSpace {
+		switch f.Value {
+		case "preserve", "replace", "collapse":
+		default:
+			return nil, fmt.Errorf("Invalid whiteSpace value '%s'.", f.Value)
+		}
+		facets = append(facets, &whiteSpaceFacet{value: f.Value, fixed: isTrue(f.Fixed)})
+	}
+
+	// 4.3.7.2 - 4.3.10.2 XML Representation of minInclusive, minExclusive, maxInclusive and maxExclusive Schema
+	// Components
+	for _, f := range node.MinInclusive {
+		facets = append(facets, &minInclusiveFacet{boundFacet{value: f.Value, fixed: isTrue(f.Fixed)}})
+	}
+	for _, f := range node.MinExclusive {
+		facets = append(facets, &minExclusiveFacet{boundFacet{value: f.Value, fixed: isTrue(f.Fixed)}})
+	}
+	for _, f := range node.MaxInclusive {
+		facets = append(facets, &maxInclusiveFacet{boundFacet{value: f.Value, fixed: isTrue(f.Fixed)}})
+	}
+	for _, f := range node.MaxExclusive {
+		facets = append(facets, &maxExclusiveFacet{boundFacet{value: f.Value, fixed: isTrue(f.Fixed)}})
+	}
+
+	// 4.3.11.2 - 4.3.12.2 XML Representation of totalDigits and fractionDigits Schema Components
+	for _, f := range node.TotalDigits {
+		facets = append(facets, &totalDigitsFacet{numFacet{value: f.Value, fixed: isTrue(f.Fixed)}})
+	}
+	for _, f := range node.FractionDigits {
+		facets = append(facets, &fractionDigitsFacet{numFacet{value: f.Value, fixed: isTrue(f.Fixed)}})
+	}
+
+	// 4.3.14.2 XML Representation of explicitTimezone Schema Components
+	for _, f := range node.ExplicitTimezone {
+		value := strings.TrimSpace(string(f.Value))
+		switch value {
+		case "required", "prohibited", "optional":
+		default:
+			return nil, fmt.Errorf("Invalid explicitTimezone value '%s'.", f.Value)
+		}
+		facets = append(facets, &explicitTimezoneFacet{value: value, fixed: isTrue(f.Fixed)})
+	}
+
+	return facets, nil
+}
+
+// restrictFacets returns facets of a restricted simple type, which are facets of its base type definition, replaced
+// by the facets of the same kind specified in the restriction. Pattern facets are not replaced, a value must match
+// patterns of all derivation steps.
+func restrictFacets(base []ConstrainingFacet, facets []ConstrainingFacet) ([]ConstrainingFacet, error) {
+	r := make([]ConstrainingFacet, 0, len(base)+len(facets))
+	for _, b := range base {
+		var replacement ConstrainingFacet
+		for _, f := range facets {
+			if reflect.TypeOf(b) == reflect.TypeOf(f) {
+				replacement = f
+				break
+			}
+		}
+		if replacement == nil {
+			r = append(r, b)
+			continue
+		}
+
+		// A fixed facet can't be changed by a derived type.
+		if fixed, value := facetValue(b); fixed {
+			if _, v := facetValue(replacement); v != value {
+				return nil, fmt.Errorf("The facet is fixed to '%s' in the base type definition.", value)
+			}
+		}
+		// 4.3.14.4 Only an optional timezone could be restricted.
+		if e, ok := b.(*explicitTimezoneFacet); ok && e.value != "optional" {
+			if own := replacement.(*explicitTimezoneFacet); own.value != e.value {
+				return nil, fmt.Errorf("The explicitTimezone facet is '%s' in the base type definition.", e.value)
+			}
+		}
+		if p, ok := b.(*patternFacet); ok {
+			own := replacement.(*patternFacet)
+			own.value = append(append([]string{}, p.value...), own.value...)
+		}
+	}
+	return append(r, facets...), nil
+}
+
+// facetValue returns the {fixed} and a representation of the {value} of a facet.
+func facetValue(f ConstrainingFacet) (bool, string) {
+	switch f := f.(type) {
+	case *lengthFacet:
+		return f.fixed, strconv.Itoa(f.value)
+	case *minLengthFacet:
+		return f.fixed, strconv.Itoa(f.value)
+	case *maxLengthFacet:
+		return f.fixed, strconv.Itoa(f.value)
+	case *totalDigitsFacet:
+		return f.fixed, strconv.Itoa(f.value)
+	case *fractionDigitsFacet:
+		return f.fixed, strconv.Itoa(f.value)
+	case *minInclusiveFacet:
+		return f.fixed, f.value
+	case *minExclusiveFacet:
+		return f.fixed, f.value
+	case *maxInclusiveFacet:
+		return f.fixed, f.value
+	case *maxExclusiveFacet:
+		return f.fixed, f.value
+	case *whiteSpaceFacet:
+		return f.fixed, f.value
+	case *explicitTimezoneFacet:
+		return f.fixed, f.value
+	}
+	return false, ""
+}
+
+func isTrue(value string) bool {
+	value = strings.TrimSpace(value)
+	return value == "true" || value == "1"
+}
+
 func (g *Generator) newComplexType(s *schema, parent interface{}, node *xsd.ComplexType) (*complexTypeDefinition, error) {
 	var err error
 
@@ -130,7 +689,12 @@ func (g *Generator) newComplexType(s *schema, parent interface{}, node *xsd.Comp
 	// The ·actual value· of the targetNamespace [attribute] of the <schema> ancestor element information item if present, otherwise ·absent·.
 	typeDef.name.Space = s.targetNamespace
 
-	s.typeDefinitions[typeDef.name] = &typeDef
+	// Only named type definitions are schema components that could be referenced by a QName.
+	if typeDef.name.Local != "" {
+		s.typeDefinitions[typeDef.name] = &typeDef
+	} else {
+		s.anonymousTypeDefinitions = append(s.anonymousTypeDefinitions, &typeDef)
+	}
 
 	// The ·actual value· of the abstract [attribute], if present, otherwise false.
 	typeDef.abstract = node.Abstract
@@ -182,7 +746,7 @@ func (g *Generator) newComplexType(s *schema, parent interface{}, node *xsd.Comp
 
 		if baseDef, ok := typeDef.baseTypeDefinition.(*complexTypeDefinition); ok {
 			if baseDef.contentType.variety == "simple" {
-				if baseDef.derivationMethod == "restriction" {
+				if typeDef.derivationMethod == "restriction" {
 					// 1 If the {base type definition} is a complex type definition whose own {content type} has
 					// {variety} simple and the <restriction> alternative is chosen, then let B be:
 					//
@@ -193,6 +757,18 @@ func (g *Generator) newComplexType(s *schema, parent interface{}, node *xsd.Comp
 					//     {base type definition}
 					//
 					// a simple type definition as follows:
+					restriction := node.SimpleContent.Restriction
+					b := baseDef.contentType.simpleTypeDefinition
+					if restriction.SimpleType != nil {
+						b, err = g.newSimpleType(s, &typeDef, &xsd.XMLTopLevelSimpleType{SimpleType: restriction.SimpleType.SimpleType})
+						if err != nil {
+							return nil, err
+						}
+					}
+					typeDef.contentType.simpleTypeDefinition, err = restrictSimpleContent(s, &typeDef, b, &restriction.XMLSimpleRestrictionModel)
+					if err != nil {
+						return nil, err
+					}
 				} else {
 					// 3 If the {base type definition} is a complex type definition whose own {content type} has
 					// {variety} simple and the <extension> alternative is chosen, then the {simple type definition} of
@@ -265,25 +841,38 @@ func (g *Generator) newComplexType(s *schema, parent interface{}, node *xsd.Comp
 		explicitContent = nil
 
 		var particleDefs xsd.TypeDefParticleGroup
+		var groupRef *xsd.Group
 
 		if node.ComplexContent != nil {
 			if node.ComplexContent.Extension != nil {
 				particleDefs = node.ComplexContent.Extension.TypeDefParticleGroup
+				groupRef = node.ComplexContent.Extension.Group
 			} else {
 				particleDefs = node.ComplexContent.Restriction.TypeDefParticleGroup
+				groupRef = node.ComplexContent.Restriction.Group
 			}
 		} else {
 			particleDefs = node.TypeDefParticleGroup
+			groupRef = node.Group
 		}
 
-		if particleDefs.Sequence != nil {
-			explicitContent, err = g.newSequenceParticle(s, node, particleDefs.Sequence)
+		explicitContent, err = g.newTypeDefParticle(s, node, particleDefs)
+		if err != nil {
+			return nil, err
+		}
+		if explicitContent == nil && groupRef != nil {
+			explicitContent, err = g.newGroupRefParticle(s, groupRef)
 			if err != nil {
 				return nil, err
 			}
 		}
 
 		effectiveContent := explicitContent
+		if effectiveContent == nil && effectiveMixed {
+			// 5.1.1 If the effective mixed is true, then a particle whose {min occurs} and {max occurs} are 1 and whose
+			// {term} is a sequence model group with an empty {particles}.
+			effectiveContent = &particle{minOccurs: 1, maxOccurs: 1, term: &modelGroup{compositor: "sequence", particles: []*particle{}}}
+		}
 
 		explicitContentType := getExplicitContentType(typeDef, effectiveContent, effectiveMixed, explicitContent)
 
@@ -297,16 +886,395 @@ func (g *Generator) newComplexType(s *schema, parent interface{}, node *xsd.Comp
 		}
 	}
 
-	// attributes
-	for _, attr := range node.GetAttributes() {
-		a, err := g.newAttributeUse(s, typeDef, attr)
+	// 3.4.2.5 Mapping rules for attribute uses and the complete wildcard.
+	// The <attributeGroup> referenced by the defaultAttributes [attribute] of the <schema> is added to the
+	// [children] unless the defaultAttributesApply [attribute] is false.
+	groups := node.GetAttributeGroups()
+	if s.xsdSchema.DefaultAttributes != "" && (node.DefaultAttributesApply == nil || *node.DefaultAttributesApply) {
+		groups = append(groups, xsd.AttributeGroup{Ref: s.xsdSchema.DefaultAttributes})
+	}
+	typeDef.attributeUses, typeDef.attributeWildcard, err = g.newAttributeUses(s, &typeDef, node.GetAttributes(), groups, node.GetAnyAttribute())
+	if err != nil {
+		return nil, err
+	}
+
+	if baseDef, ok := typeDef.baseTypeDefinition.(*complexTypeDefinition); ok {
+		inheritAttributeUses(s, &typeDef, baseDef, node.GetAttributes())
+	}
+
+	return &typeDef, nil
+}
+
+// restrictSimpleContent creates the simple type definition of the content of a complex type which restricts a simple
+// type definition B with the facets among the [children] of <restriction>, see rule 1 of 3.4.2.2. The {context} of
+// the created type is the complex type. If <restriction> specifies no facets, B is used as is.
+func restrictSimpleContent(s *schema, typeDef *complexTypeDefinition, b *simpleTypeDefinition, node *xsd.XMLSimpleRestrictionModel) (*simpleTypeDefinition, error) {
+	facets, err := newFacets(node)
+	if err != nil {
+		return nil, err
+	}
+	if len(facets) == 0 {
+		return b, nil
+	}
+
+	content := &simpleTypeDefinition{
+		context:               typeDef,
+		baseTypeDefinition:    b,
+		variety:               b.variety,
+		itemTypeDefinition:    b.itemTypeDefinition,
+		memberTypeDefinitions: b.memberTypeDefinitions,
+	}
+	content.facets, err = restrictFacets(b.facets, facets)
+	if err != nil {
+		return nil, err
+	}
+	s.anonymousTypeDefinitions = append(s.anonymousTypeDefinitions, content)
+	return content, nil
+}
+
+// inheritAttributeUses adds the {attribute uses} and the {attribute wildcard} of a complex base type definition to
+// the ones created from the type's own [children], see 3.4.2.5.
+func inheritAttributeUses(s *schema, typeDef *complexTypeDefinition, baseDef *complexTypeDefinition, attrs []xsd.Attribute) {
+	own := make(map[xml.Name]bool, len(typeDef.attributeUses))
+	for _, a := range typeDef.attributeUses {
+		own[a.attributeDeclaration.name] = true
+	}
+
+	// The attribute uses of a restriction could be prohibited by an <attribute> with use="prohibited".
+	prohibited := map[xml.Name]bool{}
+	if typeDef.derivationMethod == "restriction" {
+		for _, attr := range attrs {
+			if attr.Use == "prohibited" {
+				prohibited[attributeName(s, &attr)] = true
+			}
+		}
+	}
+
+	// The {attribute uses} of the {base type definition} go first unless an attribute use with the same name is
+	// already present or it is prohibited.
+	uses := make([]*attributeUse, 0, len(baseDef.attributeUses)+len(typeDef.attributeUses))
+	for _, a := range baseDef.attributeUses {
+		name := a.attributeDeclaration.name
+		if !own[name] && !prohibited[name] {
+			uses = append(uses, a)
+		}
+	}
+	typeDef.attributeUses = append(uses, typeDef.attributeUses...)
+
+	// A restriction uses its complete wildcard only, an extension allows attributes of the base wildcard too.
+	if typeDef.derivationMethod == "extension" && baseDef.attributeWildcard != nil {
+		if typeDef.attributeWildcard == nil {
+			typeDef.attributeWildcard = baseDef.attributeWildcard
+		} else {
+			typeDef.attributeWildcard = unionWildcards(typeDef.attributeWildcard, baseDef.attributeWildcard)
+		}
+	}
+}
+
+// newAttributeUses creates attribute uses and a complete wildcard from the <attribute>, <attributeGroup> and
+// <anyAttribute> [children] of a complex type or an attribute group definition.
+func (g *Generator) newAttributeUses(s *schema, parent interface{}, attrs []xsd.Attribute, groups []xsd.AttributeGroup, anyAttribute *xsd.AnyAttribute) ([]*attributeUse, *wildcard, error) {
+	uses := []*attributeUse{}
+	seen := map[xml.Name]bool{}
+	add := func(a *attributeUse) {
+		// Two attribute uses with the same name are not allowed, the first one wins.
+		if !seen[a.attributeDeclaration.name] {
+			seen[a.attributeDeclaration.name] = true
+			uses = append(uses, a)
+		}
+	}
+
+	// The set of attribute uses corresponding to the <attribute> [children], if any. Prohibited attributes do not
+	// produce attribute uses.
+	for _, attr := range attrs {
+		if attr.Use == "prohibited" {
+			continue
+		}
+		a, err := g.newAttributeUse(s, parent, attr)
+		if err != nil {
+			return nil, nil, err
+		}
+		add(a)
+	}
+
+	// The {attribute uses} of the attribute groups ·resolved· to by the ·actual value·s of the ref [attribute] of
+	// the <attributeGroup> [children], if any.
+	var wildcards []*wildcard
+	for _, ref := range groups {
+		def, err := g.resolveAttributeGroup(s.resolveQName(ref.Ref))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, a := range def.attributeUses {
+			add(a)
+		}
+		if def.attributeWildcard != nil {
+			wildcards = append(wildcards, def.attributeWildcard)
+		}
+	}
+
+	// The local wildcard is the one corresponding to the <anyAttribute> [child]. The complete wildcard is the
+	// intersection of the local wildcard and the {attribute wildcard}s of the referenced attribute groups.
+	var w *wildcard
+	if anyAttribute != nil {
+		var err error
+		w, err = newWildcard(s, anyAttribute.Namespace, anyAttribute.NotNamespace, anyAttribute.NotQName, anyAttribute.ProcessConttents)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, other := range wildcards {
+		if w == nil {
+			// The {process contents} of the first attribute group's wildcard is used if there is no local one.
+			w = other
+			continue
+		}
+		w = intersectWildcards(w, other)
+	}
+
+	return uses, w, nil
+}
+
+// resolveAttributeGroup resolves a qname into Attribute Group Definition
+func (g *Generator) resolveAttributeGroup(name xml.Name) (*attributeGroupDefinition, error) {
+	key := componentKey{space: "attributeGroup", name: name}
+	if def, ok, err := g.resolveRedefined(key); ok {
 		if err != nil {
 			return nil, err
 		}
-		typeDef.attributeUses = append(typeDef.attributeUses, a)
+		return def.(*attributeGroupDefinition), nil
 	}
 
-	return &typeDef, nil
+	for _, s := range g.schemas {
+		if s.targetNamespace == name.Space {
+			// Check if the attribute group is already parsed
+			if def, ok := s.attributeGroupDefinitions[name]; ok {
+				if def.attributeUses == nil {
+					return nil, fmt.Errorf("Circular reference to attribute group '%s'.", xmlNameAsString(name))
+				}
+				return def, nil
+			}
+
+			// Find and parse attribute group definition
+			doc, top := s.findTopLevel(key)
+			if t, ok := top.(xsd.AttributeGroup); ok {
+				defer g.redefine(doc, key)()
+				return g.newAttributeGroupDefinition(doc, &t)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("Error resolving component '%s'.", xmlNameAsString(name))
+}
+
+// 3.6.2 XML Representation of Attribute Group Definition Schema Components
+func (g *Generator) newAttributeGroupDefinition(s *schema, node *xsd.AttributeGroup) (*attributeGroupDefinition, error) {
+	def := &attributeGroupDefinition{
+		// The ·actual value· of the name [attribute]
+		// The ·actual value· of the targetNamespace [attribute] of the <schema> ancestor element information item if
+		// present, otherwise ·absent·.
+		name: xml.Name{Space: s.targetNamespace, Local: node.Name},
+	}
+	// The definition is registered before its attribute uses are created, so a circular reference could be detected
+	// while {attribute uses} is still nil.
+	s.attributeGroupDefinitions[def.name] = def
+
+	uses, w, err := g.newAttributeUses(s, def, node.Attributes, node.AttributeGroups, node.AnyAttribute)
+	if err != nil {
+		delete(s.attributeGroupDefinitions, def.name)
+		return nil, err
+	}
+	def.attributeUses = uses
+	def.attributeWildcard = w
+
+	return def, nil
+}
+
+// 3.10.2.2 Mapping from <any> and <anyAttribute> to a Wildcard Component
+func newWildcard(s *schema, namespace string, notNamespace string, notQName string, processContents string) (*wildcard, error) {
+	if namespace != "" && notNamespace != "" {
+		return nil, fmt.Errorf("The namespace and notNamespace attributes must not both be present.")
+	}
+
+	w := &wildcard{
+		namespaceConstraint: wildcardNamespaceConstraint{
+			namespaces:      []string{},
+			disallowedNames: []string{},
+		},
+		// The ·actual value· of the processContents [attribute], if present, otherwise strict.
+		processContents: "strict",
+	}
+	if processContents != "" {
+		w.processContents = processContents
+	}
+
+	// Resolves ##targetNamespace and ##local keywords in a list of namespaces.
+	namespaces := func(list string) []string {
+		r := []string{}
+		for _, ns := range strings.Fields(list) {
+			switch ns {
+			case "##targetNamespace":
+				r = append(r, s.targetNamespace)
+			case "##local":
+				r = append(r, "")
+			default:
+				r = append(r, ns)
+			}
+		}
+		return r
+	}
+
+	switch {
+	case notNamespace != "":
+		w.namespaceConstraint.variety = "not"
+		w.namespaceConstraint.namespaces = namespaces(notNamespace)
+	case namespace == "" || namespace == "##any":
+		w.namespaceConstraint.variety = "any"
+	case namespace == "##other":
+		// ##other excludes the target namespace and unqualified names.
+		w.namespaceConstraint.variety = "not"
+		w.namespaceConstraint.namespaces = []string{"", s.targetNamespace}
+		if s.targetNamespace == "" {
+			w.namespaceConstraint.namespaces = []string{""}
+		}
+	default:
+		w.namespaceConstraint.variety = "enumeration"
+		w.namespaceConstraint.namespaces = namespaces(namespace)
+	}
+
+	w.namespaceConstraint.disallowedNames = strings.Fields(notQName)
+
+	return w, nil
+}
+
+// intersectWildcards returns a wildcard which allows only names allowed by both of wildcards, see 3.10.6.4
+// Attribute Wildcard Intersection. The {process contents} is taken from the first wildcard.
+func intersectWildcards(a *wildcard, b *wildcard) *wildcard {
+	ac, bc := a.namespaceConstraint, b.namespaceConstraint
+	r := &wildcard{
+		processContents:    a.processContents,
+		annotatedComponent: a.annotatedComponent,
+	}
+
+	contains := func(list []string, v string) bool {
+		for _, x := range list {
+			if x == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case ac.variety == "any":
+		r.namespaceConstraint.variety = bc.variety
+		r.namespaceConstraint.namespaces = append([]string{}, bc.namespaces...)
+	case bc.variety == "any":
+		r.namespaceConstraint.variety = ac.variety
+		r.namespaceConstraint.namespaces = append([]string{}, ac.namespaces...)
+	case ac.variety == "enumeration" && bc.variety == "enumeration":
+		r.namespaceConstraint.variety = "enumeration"
+		r.namespaceConstraint.namespaces = []string{}
+		for _, ns := range ac.namespaces {
+			if contains(bc.namespaces, ns) {
+				r.namespaceConstraint.namespaces = append(r.namespaceConstraint.namespaces, ns)
+			}
+		}
+	case ac.variety == "not" && bc.variety == "not":
+		r.namespaceConstraint.variety = "not"
+		r.namespaceConstraint.namespaces = append([]string{}, ac.namespaces...)
+		for _, ns := range bc.namespaces {
+			if !contains(r.namespaceConstraint.namespaces, ns) {
+				r.namespaceConstraint.namespaces = append(r.namespaceConstraint.namespaces, ns)
+			}
+		}
+	default:
+		// One is an enumeration and the other is a negation: the namespaces of the enumeration which are not
+		// excluded by the negation.
+		enum, not := ac, bc
+		if ac.variety == "not" {
+			enum, not = bc, ac
+		}
+		r.namespaceConstraint.variety = "enumeration"
+		r.namespaceConstraint.namespaces = []string{}
+		for _, ns := range enum.namespaces {
+			if !contains(not.namespaces, ns) {
+				r.namespaceConstraint.namespaces = append(r.namespaceConstraint.namespaces, ns)
+			}
+		}
+	}
+
+	r.namespaceConstraint.disallowedNames = append(append([]string{}, ac.disallowedNames...), bc.disallowedNames...)
+
+	return r
+}
+
+// unionWildcards returns a wildcard which allows names allowed by any of wildcards, see 3.10.6.3 Attribute Wildcard
+// Union. The {process contents} is taken from the first wildcard.
+func unionWildcards(a *wildcard, b *wildcard) *wildcard {
+	ac, bc := a.namespaceConstraint, b.namespaceConstraint
+	r := &wildcard{
+		processContents:    a.processContents,
+		annotatedComponent: a.annotatedComponent,
+	}
+
+	contains := func(list []string, v string) bool {
+		for _, x := range list {
+			if x == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case ac.variety == "any" || bc.variety == "any":
+		r.namespaceConstraint.variety = "any"
+		r.namespaceConstraint.namespaces = []string{}
+	case ac.variety == "enumeration" && bc.variety == "enumeration":
+		r.namespaceConstraint.variety = "enumeration"
+		r.namespaceConstraint.namespaces = append([]string{}, ac.namespaces...)
+		for _, ns := range bc.namespaces {
+			if !contains(r.namespaceConstraint.namespaces, ns) {
+				r.namespaceConstraint.namespaces = append(r.namespaceConstraint.namespaces, ns)
+			}
+		}
+	case ac.variety == "not" && bc.variety == "not":
+		r.namespaceConstraint.variety = "not"
+		r.namespaceConstraint.namespaces = []string{}
+		for _, ns := range ac.namespaces {
+			if contains(bc.namespaces, ns) {
+				r.namespaceConstraint.namespaces = append(r.namespaceConstraint.namespaces, ns)
+			}
+		}
+	default:
+		// One is an enumeration and the other is a negation: the negated namespaces which are not allowed by the
+		// enumeration.
+		enum, not := ac, bc
+		if ac.variety == "not" {
+			enum, not = bc, ac
+		}
+		r.namespaceConstraint.variety = "not"
+		r.namespaceConstraint.namespaces = []string{}
+		for _, ns := range not.namespaces {
+			if !contains(enum.namespaces, ns) {
+				r.namespaceConstraint.namespaces = append(r.namespaceConstraint.namespaces, ns)
+			}
+		}
+	}
+	if r.namespaceConstraint.variety == "not" && len(r.namespaceConstraint.namespaces) == 0 {
+		r.namespaceConstraint.variety = "any"
+	}
+
+	// Only names disallowed by both of wildcards stay disallowed.
+	r.namespaceConstraint.disallowedNames = []string{}
+	for _, name := range ac.disallowedNames {
+		if contains(bc.disallowedNames, name) {
+			r.namespaceConstraint.disallowedNames = append(r.namespaceConstraint.disallowedNames, name)
+		}
+	}
+
+	return r
 }
 
 func getExplicitContentType(typeDef complexTypeDefinition, effectiveContent *particle, effectiveMixed bool, explicitContent *particle) complexTypeContentType {
@@ -361,10 +1329,7 @@ func getExplicitContentType(typeDef complexTypeDefinition, effectiveContent *par
 					maxOccurs: 1,
 					term: &modelGroup{
 						compositor: "sequence",
-						particles: append(
-							append([]*particle{}, baseParticle.term.(*modelGroup).particles...),
-							effectiveContent.term.(*modelGroup).particles...,
-						),
+						particles:  []*particle{baseParticle, effectiveContent},
 					},
 				}
 			}
@@ -442,16 +1407,22 @@ func (g *Generator) newAttributeUse(s *schema, parent interface{}, node xsd.Attr
 }
 
 func (g *Generator) resolveAttribute(name xml.Name) (*attributeDeclaration, error) {
-	// Find and parse type definition
 	for _, s := range g.schemas {
 		if s.targetNamespace == name.Space {
-			for _, top := range s.xsdSchema.SchemaTop {
-				switch t := top.(type) {
-				case xsd.Attribute:
-					if t.Name == name.Local {
-						return g.newAttributeDeclaration(s, nil, &t)
-					}
+			// Check if the attribute is already parsed
+			if attr, ok := s.attributeDeclarations[name]; ok {
+				return attr, nil
+			}
+
+			// Find and parse attribute declaration
+			doc, top := s.findTopLevel(componentKey{space: "attribute", name: name})
+			if t, ok := top.(xsd.Attribute); ok {
+				attr, err := g.newAttributeDeclaration(doc, nil, &t)
+				if err != nil {
+					return nil, err
 				}
+				s.attributeDeclarations[name] = attr
+				return attr, nil
 			}
 		}
 	}
@@ -459,13 +1430,21 @@ func (g *Generator) resolveAttribute(name xml.Name) (*attributeDeclaration, erro
 	return nil, fmt.Errorf("Error resolving component '%s'.", xmlNameAsString(name))
 }
 
-func (g *Generator) newAttributeDeclaration(s *schema, parent interface{}, node *xsd.Attribute) (*attributeDeclaration, error) {
+// attributeName returns the name of an attribute declared or referenced by <attribute>.
+func attributeName(s *schema, node *xsd.Attribute) xml.Name {
+	if node.Ref != "" {
+		return s.resolveQName(node.Ref)
+	}
 	ns := ""
 	if node.TargetNamespace != "" {
 		ns = node.TargetNamespace
 	} else if node.Form == "qualified" || s.attributeFormDefault == "qualified" {
 		ns = s.targetNamespace
 	}
+	return xml.Name{Space: ns, Local: node.Name}
+}
+
+func (g *Generator) newAttributeDeclaration(s *schema, parent interface{}, node *xsd.Attribute) (*attributeDeclaration, error) {
 	scope := struct {
 		variety string
 		parent  interface{}
@@ -475,7 +1454,7 @@ func (g *Generator) newAttributeDeclaration(s *schema, parent interface{}, node
 	}
 
 	attr := &attributeDeclaration{
-		name:  xml.Name{Space: ns, Local: node.Name},
+		name:  attributeName(s, node),
 		scope: scope,
 	}
 
@@ -488,6 +1467,12 @@ func (g *Generator) newAttributeDeclaration(s *schema, parent interface{}, node
 			return nil, fmt.Errorf("Attribute's type should be a simple type")
 		}
 		attr.typeDefinition = typeDef.(*simpleTypeDefinition)
+	} else if node.SimpleType != nil {
+		typeDef, err := g.newSimpleType(s, attr, &xsd.XMLTopLevelSimpleType{SimpleType: *node.SimpleType})
+		if err != nil {
+			return nil, err
+		}
+		attr.typeDefinition = typeDef
 	} else {
 		attr.typeDefinition = anySimpleType
 	}
@@ -499,9 +1484,24 @@ func (g *Generator) newAttributeDeclaration(s *schema, parent interface{}, node
 	return attr, nil
 }
 
-func (g *Generator) newSequenceParticle(s *schema, parent interface{}, node *xsd.Sequence) (*particle, error) {
+// newTypeDefParticle creates a particle for the <sequence>, <choice> or <all> child of a type definition or a model
+// group definition. It returns nil if there is none of them.
+func (g *Generator) newTypeDefParticle(s *schema, parent interface{}, node xsd.TypeDefParticleGroup) (*particle, error) {
+	if node.Sequence != nil {
+		return g.newModelGroupParticle(s, parent, "sequence", &node.Sequence.ExplicitGroup)
+	} else if node.Choice != nil {
+		return g.newModelGroupParticle(s, parent, "choice", &node.Choice.ExplicitGroup)
+	} else if node.All != nil {
+		return g.newModelGroupParticle(s, parent, "all", &node.All.ExplicitGroup)
+	}
+	return nil, nil
+}
+
+// newModelGroupParticle creates a particle for a <sequence>, a <choice> or an <all> model group depending on the
+// compositor.
+func (g *Generator) newModelGroupParticle(s *schema, parent interface{}, compositor string, node *xsd.ExplicitGroup) (*particle, error) {
 	m := &modelGroup{
-		compositor: "sequence",
+		compositor: compositor,
 	}
 	p := &particle{
 		minOccurs: node.MinOccurs,
@@ -509,39 +1509,214 @@ func (g *Generator) newSequenceParticle(s *schema, parent interface{}, node *xsd
 		term:      m,
 	}
 
+	// 3.8.6.1 Model Group Correct
+	// An <all> group may occur at most once, and it may contain only elements, wildcards and other <all> groups.
+	if compositor == "all" && (p.maxOccurs != 1 || p.minOccurs > 1) {
+		return nil, fmt.Errorf("<all> model group must have maxOccurs=1 and minOccurs of 0 or 1, but found %d and %d", p.maxOccurs, p.minOccurs)
+	}
+
 	for _, child := range node.Content {
+		var x *particle
+		var err error
+
 		switch t := child.(type) {
 		case *xsd.Element:
-			x, err := g.newLocalElement(s, t)
-			if err != nil {
-				return nil, err
+			x, err = g.newLocalElement(s, t)
+		case *xsd.Sequence:
+			if compositor == "all" {
+				return nil, fmt.Errorf("<all> model group must not contain <sequence>")
 			}
-			m.particles = append(m.particles, x)
+			x, err = g.newModelGroupParticle(s, parent, "sequence", &t.ExplicitGroup)
+		case *xsd.Choice:
+			if compositor == "all" {
+				return nil, fmt.Errorf("<all> model group must not contain <choice>")
+			}
+			x, err = g.newModelGroupParticle(s, parent, "choice", &t.ExplicitGroup)
+		case *xsd.Group:
+			x, err = g.newGroupRefParticle(s, t)
+		case *xsd.Any:
+			x, err = newAnyParticle(s, t)
+		default:
+			continue
 		}
+		if err != nil {
+			return nil, err
+		}
+		m.particles = append(m.particles, x)
 	}
 
 	return p, nil
 }
 
+// newGroupRefParticle creates a particle for a <group> reference. Its term is the model group of the referenced model
+// group definition, so the group is expanded in place.
+func (g *Generator) newGroupRefParticle(s *schema, node *xsd.Group) (*particle, error) {
+	p, err := newParticle(node.MinOccurs, node.MaxOccurs)
+	if err != nil {
+		return nil, err
+	}
+
+	def, err := g.resolveModelGroup(s.resolveQName(node.Ref))
+	if err != nil {
+		return nil, err
+	}
+	// The model group may be still being created if it's referenced through an element declaration of its own, so
+	// the particle shares the pointer to the model group which will be filled.
+	p.term = def.modelGroup
+
+	return p, nil
+}
+
+// resolveModelGroup resolves a qname into Model Group Definition
+func (g *Generator) resolveModelGroup(name xml.Name) (*modelGroupDefinition, error) {
+	key := componentKey{space: "group", name: name}
+	if def, ok, err := g.resolveRedefined(key); ok {
+		if err != nil {
+			return nil, err
+		}
+		return def.(*modelGroupDefinition), nil
+	}
+
+	for _, s := range g.schemas {
+		if s.targetNamespace == name.Space {
+			// Check if the model group is already parsed
+			if def, ok := s.modelGroupDefinitions[name]; ok {
+				return def, nil
+			}
+
+			// Find and parse model group definition
+			doc, top := s.findTopLevel(key)
+			if t, ok := top.(xsd.Group); ok {
+				defer g.redefine(doc, key)()
+				return g.newModelGroupDefinition(doc, &t)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("Error resolving component '%s'.", xmlNameAsString(name))
+}
+
+// 3.7.2 XML Representation of Model Group Definition Schema Components
+func (g *Generator) newModelGroupDefinition(s *schema, node *xsd.Group) (*modelGroupDefinition, error) {
+	def := &modelGroupDefinition{
+		// The ·actual value· of the name [attribute]
+		// The ·actual value· of the targetNamespace [attribute] of the <schema> ancestor element information item if
+		// present, otherwise ·absent·.
+		name:       xml.Name{Space: s.targetNamespace, Local: node.Name},
+		modelGroup: &modelGroup{},
+	}
+	// The definition is registered before its model group is created, so a circular reference could be detected.
+	s.modelGroupDefinitions[def.name] = def
+
+	// A model group which is the {term} of a particle corresponding to the <all>, <choice> or <sequence> among the
+	// [children] (there must be one).
+	p, err := g.newTypeDefParticle(s, def, node.TypeDefParticleGroup)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, fmt.Errorf("Model group '%s' must contain <all>, <choice> or <sequence>.", xmlNameAsString(def.name))
+	}
+	*def.modelGroup = *p.term.(*modelGroup)
+
+	// 3.8.6.3 Model Group Correct
+	// Circular groups are disallowed. That is, within the {particles} of a group there must not be at any depth a
+	// particle whose {term} is the group itself.
+	if containsModelGroup(def.modelGroup, def.modelGroup, map[*modelGroup]bool{}) {
+		return nil, fmt.Errorf("Circular reference to model group '%s'.", xmlNameAsString(def.name))
+	}
+
+	return def, nil
+}
+
+// containsModelGroup reports whether the target model group is reachable from the model group's particles without
+// passing through an element declaration.
+func containsModelGroup(m *modelGroup, target *modelGroup, visited map[*modelGroup]bool) bool {
+	if visited[m] {
+		return false
+	}
+	visited[m] = true
+
+	for _, p := range m.particles {
+		if child, ok := p.term.(*modelGroup); ok {
+			if child == target || containsModelGroup(child, target, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (g *Generator) newLocalElement(s *schema, node *xsd.Element) (*particle, error) {
+	p, err := newParticle(node.MinOccurs, node.MaxOccurs)
+	if err != nil {
+		return nil, err
+	}
+
+	if node.Ref != "" {
+		p.term, err = g.resolveElement(s.resolveQName(node.Ref))
+	} else {
+		p.term, err = g.newElement(s, node, "local")
+	}
+	return p, err
+}
+
+// newAnyParticle creates a particle for an <any> element wildcard, see 3.10.2.1 Mapping from <any> to a Particle.
+func newAnyParticle(s *schema, node *xsd.Any) (*particle, error) {
+	p, err := newParticle(node.MinOccurs, node.MaxOccurs)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := newWildcard(s, node.Namespace, node.NotNamespace, node.NotQName, node.ProcessContents)
+	if err != nil {
+		return nil, err
+	}
+	p.term = w
+
+	return p, nil
+}
+
+// newParticle creates a particle with the ·actual values· of the minOccurs and maxOccurs [attributes], if present,
+// otherwise 1.
+func newParticle(minOccurs *int, maxOccurs *string) (*particle, error) {
 	var err error
 
 	p := &particle{minOccurs: 1, maxOccurs: 1}
-	if node.MinOccurs != nil {
-		p.minOccurs = *node.MinOccurs
+	if minOccurs != nil {
+		p.minOccurs = *minOccurs
 	}
-	if node.MaxOccurs != nil {
-		if *node.MaxOccurs == "unbounded" {
+	if maxOccurs != nil {
+		if *maxOccurs == "unbounded" {
 			p.maxOccurs = unbounded
 		} else {
-			p.maxOccurs, err = strconv.Atoi(*node.MaxOccurs)
+			p.maxOccurs, err = strconv.Atoi(*maxOccurs)
 			if err != nil {
 				return nil, fmt.Errorf("invalid maxOccurs attribute value: %v", err)
 			}
 		}
 	}
-	p.term, err = g.newElement(s, node)
-	return p, err
+	return p, nil
+}
+
+// resolveElement resolves a qname into a global Element Declaration
+func (g *Generator) resolveElement(name xml.Name) (*elementDeclaration, error) {
+	for _, s := range g.schemas {
+		if s.targetNamespace == name.Space {
+			// Check if the element is already parsed
+			if elm, ok := s.elementDeclarations[name]; ok {
+				return elm, nil
+			}
+
+			// Find and parse element declaration
+			doc, top := s.findTopLevel(componentKey{space: "element", name: name})
+			if t, ok := top.(xsd.Element); ok {
+				return g.newElement(doc, &t, "global")
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("Error resolving component '%s'.", xmlNameAsString(name))
 }
 
 // resolveType resolves a qname into Type Definition
@@ -551,6 +1726,14 @@ func (g *Generator) resolveType(name xml.Name) (TypeDefinition, error) {
 		return typeDef, nil
 	}
 
+	key := componentKey{space: "type", name: name}
+	if typeDef, ok, err := g.resolveRedefined(key); ok {
+		if err != nil {
+			return nil, err
+		}
+		return typeDef.(TypeDefinition), nil
+	}
+
 	// Check if type is already parsed
 	for _, s := range g.schemas {
 		if typeDef, ok := s.typeDefinitions[name]; ok {
@@ -559,17 +1742,14 @@ func (g *Generator) resolveType(name xml.Name) (TypeDefinition, error) {
 
 		// Find and parse type definition
 		if s.targetNamespace == name.Space {
-			for _, top := range s.xsdSchema.SchemaTop {
-				switch t := top.(type) {
-				case xsd.XMLTopLevelSimpleType:
-					if string(t.Name) == name.Local {
-						return g.newSimpleType(s, nil, &t)
-					}
-				case xsd.ComplexType:
-					if t.Name == name.Local {
-						return g.newComplexType(s, nil, &t)
-					}
-				}
+			doc, top := s.findTopLevel(key)
+			switch t := top.(type) {
+			case xsd.XMLTopLevelSimpleType:
+				defer g.redefine(doc, key)()
+				return g.newSimpleType(doc, nil, &t)
+			case xsd.ComplexType:
+				defer g.redefine(doc, key)()
+				return g.newComplexType(doc, nil, &t)
 			}
 		}
 	}
@@ -627,12 +1807,44 @@ func getFinals(node *xsd.ComplexType, s *schema, typeDef complexTypeDefinition)
 }
 
 // 3.3.2.1 Common Mapping Rules for Element Declarations
-func (g *Generator) newElement(s *schema, node *xsd.Element) (*elementDeclaration, error) {
+func (g *Generator) newElement(s *schema, node *xsd.Element, scope string) (*elementDeclaration, error) {
 	var err error
 
 	elm := &elementDeclaration{}
 	// The ·actual value· of the name [attribute].
 	elm.name.Local = node.Name
+	elm.scope.variety = scope
+
+	if scope == "global" {
+		// 3.3.2.2 Mapping Rules for Top-Level Element Declarations
+		elm.name.Space = s.targetNamespace
+
+		// The element is registered before its type definition is resolved, so the type could refer to it.
+		s.elementDeclarations[elm.name] = elm
+
+		// A set of the element declarations ·resolved· to by the items in the ·actual value· of the
+		// substitutionGroup [attribute], if present, otherwise the empty set.
+		for _, qname := range strings.Fields(node.SubstitutionGroup) {
+			head, err := g.resolveElement(s.resolveQName(qname))
+			if err != nil {
+				return nil, err
+			}
+			// The type of a head which is still being parsed is unknown, so the head refers to this element.
+			if head.typeDefinition == nil {
+				return nil, fmt.Errorf("Circular substitution group of element '%s'.", xmlNameAsString(elm.name))
+			}
+			elm.substitutionGroupAffiliations = append(elm.substitutionGroupAffiliations, head)
+		}
+	} else {
+		// 3.3.2.3 Mapping Rules for Local Element Declarations
+		// The ·actual value· of the targetNamespace [attribute], if present, otherwise the targetNamespace of the
+		// <schema> ancestor if the element is qualified by form or elementFormDefault, otherwise ·absent·.
+		if node.TargetNamespace != "" {
+			elm.namespace = node.TargetNamespace
+		} else if node.Form == "qualified" || node.Form == "" && s.elementFormDefault == "qualified" {
+			elm.namespace = s.targetNamespace
+		}
+	}
 	// The first of the following that applies:
 	// 1 The type definition corresponding to the <simpleType> or <complexType> element information item in the
 	//   [children], if either is present.
@@ -646,12 +1858,17 @@ func (g *Generator) newElement(s *schema, node *xsd.Element) (*elementDeclaratio
 			return nil, err
 		}
 	} else if node.SimpleType != nil {
-
+		elm.typeDefinition, err = g.newSimpleType(s, elm, &xsd.XMLTopLevelSimpleType{SimpleType: *node.SimpleType})
+		if err != nil {
+			return nil, err
+		}
 	} else if node.Type != "" {
 		elm.typeDefinition, err = g.resolveType(s.resolveQName(node.Type))
 		if err != nil {
 			return nil, err
 		}
+	} else if len(elm.substitutionGroupAffiliations) > 0 {
+		elm.typeDefinition = elm.substitutionGroupAffiliations[0].typeDefinition
 	} else {
 		elm.typeDefinition = anyType
 	}
@@ -686,9 +1903,6 @@ func (g *Generator) newElement(s *schema, node *xsd.Element) (*elementDeclaratio
 	// A set consisting of the identity-constraint-definitions corresponding to all the <key>, <unique> and
 	// <keyref> element information items in the [children], if any, otherwise the empty set.
 	// elm.identityConstraintDefinitions
-	// A set of the element declarations ·resolved· to by the items in the ·actual value· of the substitutionGroup
-	// [attribute], if present, otherwise the empty set.
-	// elm.substitutionGroupAffiliations
 	// A set depending on the ·actual value· of the block [attribute], if present, otherwise on the ·actual value·
 	// of the blockDefault [attribute] of the ancestor <schema> element information item, if present, otherwise
 	// on the empty string. Call this the EBV (for effective block value). Then the value of this property is the
@@ -702,10 +1916,25 @@ func (g *Generator) newElement(s *schema, node *xsd.Element) (*elementDeclaratio
 	// Note: Although the blockDefault [attribute] of <schema> may include values other than extension, restriction
 	// or substitution, those values are ignored in the determination of {disallowed substitutions} for element
 	// declarations (they are used elsewhere).
-	// elm.disallowedSubstitutions
+	elm.disallowedSubstitutions = derivationSet(node.Block, s.blockDefault, "substitution", "extension", "restriction")
 	// As for {disallowed substitutions} above, but using the final and finalDefault [attributes] in place of the
 	// block and blockDefault [attributes] and with the relevant set being {extension, restriction}.
-	//elm.substitutionGroupExclusions
+	elm.substitutionGroupExclusions = derivationSet(node.Final, s.finalDefault, "extension", "restriction")
+
+	// 3.3.6.3 Substitution Group OK (Transitive): the type of a member must be derived from the type of its head by
+	// methods which are not in the {substitution group exclusions} of the head.
+	for _, head := range elm.substitutionGroupAffiliations {
+		methods, ok := derivationMethods(elm.typeDefinition, head.typeDefinition)
+		if !ok {
+			return nil, fmt.Errorf("Type of element '%s' is not derived from the type of its substitution group head '%s'.", xmlNameAsString(elm.name), xmlNameAsString(head.name))
+		}
+		for _, method := range methods {
+			if containsString(head.substitutionGroupExclusions, method) {
+				return nil, fmt.Errorf("Element '%s' cannot be a member of the substitution group '%s', since %s is final.", xmlNameAsString(elm.name), xmlNameAsString(head.name), method)
+			}
+		}
+		head.substitutionGroupMembers = append(head.substitutionGroupMembers, elm)
+	}
 	// The ·actual value· of the abstract [attribute], if present, otherwise false.
 	elm.abstract = node.Abstract
 	// The ·annotation mapping· of the <element> element and any of its <unique>, <key> and <keyref> [children]
@@ -714,6 +1943,55 @@ func (g *Generator) newElement(s *schema, node *xsd.Element) (*elementDeclaratio
 	return elm, nil
 }
 
+// derivationSet returns members of the allowed set which are listed in the ·actual value· of a block or a final
+// [attribute], if present, otherwise in the default value of the <schema>. #all stands for all of them.
+func derivationSet(value string, defaultValue string, allowed ...string) []string {
+	if value == "" {
+		value = defaultValue
+	}
+	if value == "#all" {
+		return allowed
+	}
+	set := make([]string, 0)
+	for _, v := range strings.Fields(value) {
+		if containsString(allowed, v) {
+			set = append(set, v)
+		}
+	}
+	return set
+}
+
+// derivationMethods returns the {derivation method}s of the type definitions which lie between a type definition
+// and one of its ancestors. It returns false if the type definition isn't derived from the ancestor.
+func derivationMethods(typeDef interface{}, ancestor interface{}) ([]string, bool) {
+	methods := make([]string, 0)
+	for t := typeDef; t != ancestor; {
+		switch def := t.(type) {
+		case *complexTypeDefinition:
+			if def == anyType {
+				return nil, false
+			}
+			methods = append(methods, def.derivationMethod)
+			t = def.baseTypeDefinition
+		case *simpleTypeDefinition:
+			methods = append(methods, "restriction")
+			t = def.baseTypeDefinition
+		default:
+			return nil, false
+		}
+	}
+	return methods, true
+}
+
+func containsString(list []string, v string) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
 func normalizeValue(s string) string {
 	// replace
 	r := regexp.MustCompile("[\t\r\n]").ReplaceAllString(s, " ")
@@ -1,6 +1,9 @@
 package goxsd
 
-import "io"
+import (
+	"io"
+	"strings"
+)
 
 type ctrlSymbol int
 
@@ -134,6 +137,18 @@ func (p *printer) printNode(n Node) {
 		}
 		p.print(n.Type)
 
+	case *ConstDecl:
+		if n.Group == nil {
+			p.print(_Const, blank)
+		}
+		p.printNameList(n.NameList)
+		if n.Type != nil {
+			p.print(blank, n.Type)
+		}
+		if n.Values != nil {
+			p.print(blank, _Assign, blank, n.Values)
+		}
+
 	case *VarDecl:
 		if n.Group == nil {
 			p.print(_Var, blank)
@@ -165,6 +180,59 @@ func (p *printer) printNode(n Node) {
 		}
 		p.print(_Rbrace)
 
+	case *FuncDecl:
+		p.print(newline)
+		if n.Doc != "" {
+			for _, line := range strings.Split(n.Doc, "\n") {
+				p.print(_Name, strings.TrimRight("// "+line, " "), newline)
+			}
+		}
+		p.print(_Func, blank)
+		if n.Recv != nil {
+			p.print(_Lparen)
+			p.printField(n.Recv)
+			p.print(_Rparen, blank)
+		}
+		p.print(n.Name)
+		p.printSignature(n.Type)
+		if n.Body != nil {
+			p.print(blank, n.Body)
+		}
+
+	case *FuncType:
+		p.print(_Func)
+		p.printSignature(n)
+
+	case *BlockStmt:
+		p.print(_Lbrace)
+		if len(n.List) > 0 {
+			p.print(newline, indent)
+			for _, s := range n.List {
+				p.printNode(s)
+				p.print(_Semi, newline)
+			}
+			p.print(outdent)
+		}
+		p.print(_Rbrace)
+
+	case *ExprStmt:
+		p.print(n.X)
+
+	case *AssignStmt:
+		p.print(n.Lhs, blank)
+		if n.Define {
+			p.print(_Define)
+		} else {
+			p.print(_Assign)
+		}
+		p.print(blank, n.Rhs)
+
+	case *ReturnStmt:
+		p.print(_Return)
+		if n.Results != nil {
+			p.print(blank, n.Results)
+		}
+
 	case *ImportDecl:
 		if n.Group == nil {
 			p.print(_Import, blank)
@@ -177,6 +245,47 @@ func (p *printer) printNode(n Node) {
 	}
 }
 
+// printDeclList prints declarations, each followed by a newline. Consecutive declarations of the same group are
+// printed as a single grouped declaration.
+func (p *printer) printDeclList(list []Decl) {
+	i0 := 0
+	var tok token
+	var group *Group
+	for i, x := range list {
+		if s, g := groupFor(x); g == nil || g != group {
+			if i0 < i {
+				p.print(newline, &printGroup{Tok: tok, Decls: list[i0:i]}, newline)
+				i0 = i
+			}
+			if g == nil {
+				p.print(x, newline)
+				i0 = i + 1
+			}
+			tok, group = s, g
+		}
+	}
+	if i0 < len(list) {
+		p.print(newline, &printGroup{Tok: tok, Decls: list[i0:]}, newline)
+	}
+}
+
+func groupFor(d Decl) (token, *Group) {
+	switch d := d.(type) {
+	case *ImportDecl:
+		return _Import, d.Group
+	case *ConstDecl:
+		return _Const, d.Group
+	case *TypeDecl:
+		return _Type, d.Group
+	case *VarDecl:
+		return _Var, d.Group
+	case *FuncDecl:
+		return 0, nil
+	default:
+		panic("unreachable")
+	}
+}
+
 func (p *printer) printField(f *Field) {
 	if f.Name == nil {
 		// anonymous field
@@ -201,6 +310,28 @@ func (p *printer) printField(f *Field) {
 	}
 }
 
+func (p *printer) printSignature(t *FuncType) {
+	p.print(_Lparen)
+	p.printParameterList(t.ParamList)
+	p.print(_Rparen)
+	if len(t.ResultList) == 1 && t.ResultList[0].Name == nil {
+		p.print(blank, t.ResultList[0].Type)
+	} else if len(t.ResultList) > 0 {
+		p.print(blank, _Lparen)
+		p.printParameterList(t.ResultList)
+		p.print(_Rparen)
+	}
+}
+
+func (p *printer) printParameterList(list []*Field) {
+	for i, f := range list {
+		if i > 0 {
+			p.print(_Comma, blank)
+		}
+		p.printField(f)
+	}
+}
+
 func (p *printer) printFieldList(fields []*Field) {
 	for _, f := range fields {
 		p.printField(f)
@@ -0,0 +1,458 @@
+package goxsd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// XML Schema regular expressions (Appendix G of XML Schema Part 2) are close to the RE2 syntax of the regexp package,
+// but they are implicitly anchored, treat ^ and $ as ordinary characters, and have escapes and a character class
+// subtraction the regexp package doesn't know about. translatePattern rewrites such an expression into an equivalent
+// Go regular expression. Character classes which can't be expressed with RE2 are expanded into rune ranges.
+func translatePattern(pattern string) (string, error) {
+	p := &regexpParser{src: []rune(pattern)}
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		p.pos++
+		switch r {
+		case '\\':
+			item, err := p.parseEscape()
+			if err != nil {
+				return "", err
+			}
+			if item.single {
+				b.WriteString(regexp.QuoteMeta(string(item.set[0].lo)))
+			} else {
+				b.WriteString("[" + item.text + "]")
+			}
+		case '[':
+			c, err := p.parseClass()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(c.String())
+		case ']':
+			return "", fmt.Errorf("Unexpected ']' in pattern '%s'.", pattern)
+		case '.':
+			// Any character except line breaks.
+			b.WriteString(`[^\n\r]`)
+		case '^', '$':
+			// They are not anchors.
+			b.WriteString(`\` + string(r))
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	re := "^(?:" + b.String() + ")$"
+	if _, err := regexp.Compile(re); err != nil {
+		return "", fmt.Errorf("Invalid pattern '%s': %s", pattern, err)
+	}
+	return re, nil
+}
+
+type regexpParser struct {
+	src []rune
+	pos int
+}
+
+// classItem is a single character, a character range or a character class escape within a character class.
+type classItem struct {
+	// A set of characters matched by the item.
+	set runeSet
+	// A representation of the item inside a Go character class.
+	text string
+	// It's a single character.
+	single bool
+}
+
+// charClass is a character class expression, possibly with a subtraction.
+type charClass struct {
+	negated bool
+	items   []classItem
+	sub     *charClass
+}
+
+// runes returns the set of characters matched by the class.
+func (c *charClass) runes() runeSet {
+	var s runeSet
+	for _, item := range c.items {
+		s = append(s, item.set...)
+	}
+	s = s.normalize()
+	if c.negated {
+		s = s.negate()
+	}
+	if c.sub != nil {
+		s = s.subtract(c.sub.runes())
+	}
+	return s
+}
+
+func (c *charClass) String() string {
+	if c.sub != nil {
+		s := c.runes()
+		if len(s) == 0 {
+			// Nothing could match an empty class.
+			return `[^\x{0}-\x{10FFFF}]`
+		}
+		return "[" + s.String() + "]"
+	}
+
+	var b strings.Builder
+	b.WriteString("[")
+	if c.negated {
+		b.WriteString("^")
+	}
+	for _, item := range c.items {
+		b.WriteString(item.text)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// parseClass parses a character class expression after its opening bracket.
+func (p *regexpParser) parseClass() (*charClass, error) {
+	c := &charClass{}
+	if p.pos < len(p.src) && p.src[p.pos] == '^' {
+		c.negated = true
+		p.pos++
+	}
+
+	for {
+		if p.pos >= len(p.src) {
+			return nil, fmt.Errorf("Unterminated character class in pattern '%s'.", string(p.src))
+		}
+		r := p.src[p.pos]
+		if r == ']' && len(c.items) > 0 {
+			p.pos++
+			return c, nil
+		}
+		if r == '-' && p.pos+1 < len(p.src) && p.src[p.pos+1] == '[' && len(c.items) > 0 {
+			// Character class subtraction must be the last part of the class.
+			p.pos += 2
+			sub, err := p.parseClass()
+			if err != nil {
+				return nil, err
+			}
+			c.sub = sub
+			if p.pos >= len(p.src) || p.src[p.pos] != ']' {
+				return nil, fmt.Errorf("A subtraction must be the last part of a character class in pattern '%s'.", string(p.src))
+			}
+			p.pos++
+			return c, nil
+		}
+
+		item, err := p.parseClassAtom()
+		if err != nil {
+			return nil, err
+		}
+		// A character range, unless the hyphen is the last character of the class.
+		if item.single && p.pos+1 < len(p.src) && p.src[p.pos] == '-' && p.src[p.pos+1] != ']' && p.src[p.pos+1] != '[' {
+			p.pos++
+			hi, err := p.parseClassAtom()
+			if err != nil {
+				return nil, err
+			}
+			if !hi.single || hi.set[0].lo < item.set[0].lo {
+				return nil, fmt.Errorf("Invalid character range in pattern '%s'.", string(p.src))
+			}
+			item = classItem{
+				set:  runeSet{{item.set[0].lo, hi.set[0].lo}},
+				text: classRune(item.set[0].lo) + "-" + classRune(hi.set[0].lo),
+			}
+		}
+		c.items = append(c.items, item)
+	}
+}
+
+// parseClassAtom parses a single character or an escape within a character class.
+func (p *regexpParser) parseClassAtom() (classItem, error) {
+	r := p.src[p.pos]
+	p.pos++
+	switch r {
+	case '\\':
+		return p.parseEscape()
+	case '[':
+		return classItem{}, fmt.Errorf("Unescaped '[' in a character class in pattern '%s'.", string(p.src))
+	}
+	return runeItem(r), nil
+}
+
+// parseEscape parses an escape sequence after its backslash.
+func (p *regexpParser) parseEscape() (classItem, error) {
+	if p.pos >= len(p.src) {
+		return classItem{}, fmt.Errorf("Trailing backslash in pattern '%s'.", string(p.src))
+	}
+	r := p.src[p.pos]
+	p.pos++
+
+	switch r {
+	// Single character escapes
+	case 'n':
+		return runeItem('\n'), nil
+	case 'r':
+		return runeItem('\r'), nil
+	case 't':
+		return runeItem('\t'), nil
+	case '\\', '|', '.', '?', '*', '+', '(', ')', '{', '}', '-', '[', ']', '^':
+		return runeItem(r), nil
+
+	// Multi-character escapes
+	case 's':
+		return setItem(spaceChars), nil
+	case 'S':
+		return setItem(spaceChars.negate()), nil
+	case 'i':
+		return setItem(nameStartChars), nil
+	case 'I':
+		return setItem(nameStartChars.negate()), nil
+	case 'c':
+		return setItem(nameChars), nil
+	case 'C':
+		return setItem(nameChars.negate()), nil
+	case 'd':
+		return classItem{set: tableRunes(unicode.Nd), text: `\p{Nd}`}, nil
+	case 'D':
+		return classItem{set: tableRunes(unicode.Nd).negate(), text: `\P{Nd}`}, nil
+	case 'w':
+		// All characters except punctuation, separators and "other" characters.
+		return classItem{set: wordChars(), text: `\p{L}\p{M}\p{N}\p{S}`}, nil
+	case 'W':
+		return classItem{set: wordChars().negate(), text: `\p{P}\p{Z}\p{C}`}, nil
+
+	// Category escapes
+	case 'p', 'P':
+		if p.pos >= len(p.src) || p.src[p.pos] != '{' {
+			return classItem{}, fmt.Errorf("Expected '{' after \\%c in pattern '%s'.", r, string(p.src))
+		}
+		end := p.pos
+		for end < len(p.src) && p.src[end] != '}' {
+			end++
+		}
+		if end >= len(p.src) {
+			return classItem{}, fmt.Errorf("Unterminated \\%c{ in pattern '%s'.", r, string(p.src))
+		}
+		name := string(p.src[p.pos+1 : end])
+		p.pos = end + 1
+
+		var set runeSet
+		text := `\` + string(r) + `{` + name + `}`
+		if strings.HasPrefix(name, "Is") {
+			block, ok := unicodeBlocks[name[2:]]
+			if !ok {
+				return classItem{}, fmt.Errorf("Unsupported block escape \\%c{%s} in pattern '%s'.", r, name, string(p.src))
+			}
+			set = runeSet{block}
+			text = set.String()
+		} else {
+			table, ok := unicode.Categories[name]
+			if !ok {
+				return classItem{}, fmt.Errorf("Unknown category escape \\%c{%s} in pattern '%s'.", r, name, string(p.src))
+			}
+			set = tableRunes(table)
+		}
+		if r == 'P' {
+			set = set.negate()
+			if strings.HasPrefix(name, "Is") {
+				text = set.String()
+			}
+		}
+		return classItem{set: set, text: text}, nil
+	}
+
+	return classItem{}, fmt.Errorf("Unknown escape \\%c in pattern '%s'.", r, string(p.src))
+}
+
+func runeItem(r rune) classItem {
+	return classItem{set: runeSet{{r, r}}, text: classRune(r), single: true}
+}
+
+func setItem(set runeSet) classItem {
+	return classItem{set: set, text: set.String()}
+}
+
+// classRune returns a representation of the character inside a Go character class.
+func classRune(r rune) string {
+	switch r {
+	case '\\', ']', '[', '^', '-':
+		return `\` + string(r)
+	}
+	if r < 0x80 && unicode.IsPrint(r) && r != ' ' {
+		return string(r)
+	}
+	return fmt.Sprintf(`\x{%X}`, r)
+}
+
+//-----------------------------------------------------------------------------
+// Sets of characters
+
+type runeRange struct {
+	lo, hi rune
+}
+
+// runeSet is a set of characters. Normalized sets are sorted and have no overlapping or adjacent ranges.
+type runeSet []runeRange
+
+func (s runeSet) normalize() runeSet {
+	if len(s) == 0 {
+		return s
+	}
+	sorted := append(runeSet{}, s...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].lo < sorted[j].lo })
+
+	r := runeSet{sorted[0]}
+	for _, x := range sorted[1:] {
+		last := &r[len(r)-1]
+		if x.lo <= last.hi+1 {
+			if x.hi > last.hi {
+				last.hi = x.hi
+			}
+			continue
+		}
+		r = append(r, x)
+	}
+	return r
+}
+
+// negate returns a complement of a normalized set.
+func (s runeSet) negate() runeSet {
+	r := runeSet{}
+	next := rune(0)
+	for _, x := range s {
+		if x.lo > next {
+			r = append(r, runeRange{next, x.lo - 1})
+		}
+		next = x.hi + 1
+	}
+	if next <= unicode.MaxRune {
+		r = append(r, runeRange{next, unicode.MaxRune})
+	}
+	return r
+}
+
+// subtract returns characters of a normalized set which are not in another normalized set.
+func (s runeSet) subtract(o runeSet) runeSet {
+	not := o.negate()
+	r := runeSet{}
+	i, j := 0, 0
+	for i < len(s) && j < len(not) {
+		lo, hi := s[i].lo, s[i].hi
+		if not[j].lo > lo {
+			lo = not[j].lo
+		}
+		if not[j].hi < hi {
+			hi = not[j].hi
+		}
+		if lo <= hi {
+			r = append(r, runeRange{lo, hi})
+		}
+		if s[i].hi < not[j].hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return r
+}
+
+// String returns a representation of the set inside a Go character class.
+func (s runeSet) String() string {
+	var b strings.Builder
+	for _, x := range s {
+		b.WriteString(classRune(x.lo))
+		if x.hi != x.lo {
+			b.WriteString("-" + classRune(x.hi))
+		}
+	}
+	return b.String()
+}
+
+func tableRunes(t *unicode.RangeTable) runeSet {
+	var s runeSet
+	for _, r := range t.R16 {
+		for c := rune(r.Lo); c <= rune(r.Hi); c += rune(r.Stride) {
+			if r.Stride == 1 {
+				s = append(s, runeRange{rune(r.Lo), rune(r.Hi)})
+				break
+			}
+			s = append(s, runeRange{c, c})
+		}
+	}
+	for _, r := range t.R32 {
+		for c := rune(r.Lo); c <= rune(r.Hi); c += rune(r.Stride) {
+			if r.Stride == 1 {
+				s = append(s, runeRange{rune(r.Lo), rune(r.Hi)})
+				break
+			}
+			s = append(s, runeRange{c, c})
+		}
+	}
+	return s.normalize()
+}
+
+func wordChars() runeSet {
+	var s runeSet
+	s = append(s, tableRunes(unicode.P)...)
+	s = append(s, tableRunes(unicode.Z)...)
+	s = append(s, tableRunes(unicode.C)...)
+	return s.normalize().negate()
+}
+
+var spaceChars = runeSet{{'\t', '\n'}, {'\r', '\r'}, {' ', ' '}}
+
+// NameStartChar production of XML 1.0
+var nameStartChars = runeSet{
+	{':', ':'}, {'A', 'Z'}, {'_', '_'}, {'a', 'z'}, {0xC0, 0xD6}, {0xD8, 0xF6}, {0xF8, 0x2FF}, {0x370, 0x37D},
+	{0x37F, 0x1FFF}, {0x200C, 0x200D}, {0x2070, 0x218F}, {0x2C00, 0x2FEF}, {0x3001, 0xD7FF}, {0xF900, 0xFDCF},
+	{0xFDF0, 0xFFFD}, {0x10000, 0xEFFFF},
+}.normalize()
+
+// NameChar production of XML 1.0
+var nameChars = append(runeSet{
+	{'-', '.'}, {'0', '9'}, {0xB7, 0xB7}, {0x300, 0x36F}, {0x203F, 0x2040},
+}, nameStartChars...).normalize()
+
+// Unicode blocks which could be referred by \p{IsBlock} escapes.
+var unicodeBlocks = map[string]runeRange{
+	"BasicLatin":                  {0x0000, 0x007F},
+	"Latin-1Supplement":           {0x0080, 0x00FF},
+	"LatinExtended-A":             {0x0100, 0x017F},
+	"LatinExtended-B":             {0x0180, 0x024F},
+	"IPAExtensions":               {0x0250, 0x02AF},
+	"SpacingModifierLetters":      {0x02B0, 0x02FF},
+	"CombiningDiacriticalMarks":   {0x0300, 0x036F},
+	"Greek":                       {0x0370, 0x03FF},
+	"Cyrillic":                    {0x0400, 0x04FF},
+	"Armenian":                    {0x0530, 0x058F},
+	"Hebrew":                      {0x0590, 0x05FF},
+	"Arabic":                      {0x0600, 0x06FF},
+	"Devanagari":                  {0x0900, 0x097F},
+	"Thai":                        {0x0E00, 0x0E7F},
+	"Georgian":                    {0x10A0, 0x10FF},
+	"HangulJamo":                  {0x1100, 0x11FF},
+	"LatinExtendedAdditional":     {0x1E00, 0x1EFF},
+	"GreekExtended":               {0x1F00, 0x1FFF},
+	"GeneralPunctuation":          {0x2000, 0x206F},
+	"SuperscriptsandSubscripts":   {0x2070, 0x209F},
+	"CurrencySymbols":             {0x20A0, 0x20CF},
+	"LetterlikeSymbols":           {0x2100, 0x214F},
+	"NumberForms":                 {0x2150, 0x218F},
+	"Arrows":                      {0x2190, 0x21FF},
+	"MathematicalOperators":       {0x2200, 0x22FF},
+	"BoxDrawing":                  {0x2500, 0x257F},
+	"GeometricShapes":             {0x25A0, 0x25FF},
+	"CJKSymbolsandPunctuation":    {0x3000, 0x303F},
+	"Hiragana":                    {0x3040, 0x309F},
+	"Katakana":                    {0x30A0, 0x30FF},
+	"CJKUnifiedIdeographs":        {0x4E00, 0x9FFF},
+	"HangulSyllables":             {0xAC00, 0xD7A3},
+	"PrivateUse":                  {0xE000, 0xF8FF},
+	"AlphabeticPresentationForms": {0xFB00, 0xFB4F},
+	"HalfwidthandFullwidthForms":  {0xFF00, 0xFFEF},
+	"Specials":                    {0xFFF0, 0xFFFF},
+}
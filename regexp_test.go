@@ -0,0 +1,48 @@
+package goxsd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestTranslatePattern(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		match    []string
+		mismatch []string
+	}{
+		{`\d{5}(-\d{4})?`, []string{"12345", "12345-6789"}, []string{"1234", "x12345", "12345-"}},
+		{`a^b$`, []string{"a^b$"}, []string{"ab"}},
+		{`.+`, []string{"abc"}, []string{"a\nb", ""}},
+		{`\i\c*`, []string{"_x-1.y", "élan"}, []string{"1abc", "-x"}},
+		{`[\i-[:]][\c-[:]]*`, []string{"name"}, []string{"ns:name", ":x"}},
+		{`[a-z-[aeiou]]+`, []string{"xyz"}, []string{"xaz"}},
+		{`[^a-c-[x]]`, []string{"d"}, []string{"a", "x"}},
+		{`\p{Lu}\w*`, []string{"Abc1"}, []string{"abc", "A b"}},
+		{`\p{IsBasicLatin}+`, []string{"abc"}, []string{"ä"}},
+		{`[\-\[\]]+`, []string{"-[]"}, []string{"a"}},
+		{`[+-]?\s?\S`, []string{"+ x", "-x"}, []string{"  "}},
+	}
+
+	for _, c := range cases {
+		re, err := translatePattern(c.pattern)
+		if !assert.NoError(t, err, c.pattern) {
+			continue
+		}
+		r := regexp.MustCompile(re)
+		for _, s := range c.match {
+			assert.True(t, r.MatchString(s), c.pattern+" should match "+s)
+		}
+		for _, s := range c.mismatch {
+			assert.False(t, r.MatchString(s), c.pattern+" should not match "+s)
+		}
+	}
+}
+
+func TestTranslatePatternErrors(t *testing.T) {
+	for _, pattern := range []string{`[abc`, `\q`, `\p{IsUnknownBlock}`, `[a-[b]c]`, `a\`} {
+		_, err := translatePattern(pattern)
+		assert.Error(t, err, pattern)
+	}
+}
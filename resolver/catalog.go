@@ -0,0 +1,235 @@
+package resolver
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	nsCatalog = "urn:oasis:names:tc:entity:xmlns:xml:catalog"
+	nsXml     = "http://www.w3.org/XML/1998/namespace"
+)
+
+// catalog is an OASIS XML catalog, which maps system identifiers and URIs to other URIs. Entries of groups are
+// entries of the catalog, and public identifiers are not supported.
+//
+// Schema locations are looked up as system identifiers and then as URIs, and namespaces are looked up as URIs.
+type catalog struct {
+	entries []catalogEntry
+	next    []*catalog
+}
+
+// catalogEntry is an entry of a catalog.
+type catalogEntry struct {
+	// The local name of the entry element, like system or rewriteURI.
+	kind string
+	// A system identifier, a URI, a start string or a suffix which the entry matches.
+	match string
+	// The uri or rewritePrefix attribute, which is resolved against the base URI of the entry.
+	uri *url.URL
+	// The catalog of delegateSystem and delegateURI entries.
+	catalog *catalog
+}
+
+// readCatalog reads a catalog and the catalogs it refers to. The visited catalogs are not read again, so a catalog
+// could refer to itself.
+func (r *Resolver) readCatalog(u *url.URL, visited map[string]*catalog) (*catalog, error) {
+	location := u.String()
+	if c, ok := visited[location]; ok {
+		return c, nil
+	}
+	c := &catalog{}
+	visited[location] = c
+
+	data, err := r.read(u)
+	if err != nil {
+		return nil, err
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+	bases := []*url.URL{u}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", location, err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			base := bases[len(bases)-1]
+			if v, ok := attrValue(t, xml.Name{Space: nsXml, Local: "base"}); ok {
+				if base, err = resolveAgainst(base, v); err != nil {
+					return nil, err
+				}
+			}
+			bases = append(bases, base)
+			if t.Name.Space != nsCatalog {
+				continue
+			}
+
+			entry, err := r.newCatalogEntry(t, base, visited)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", location, err)
+			}
+			switch {
+			case entry == nil:
+			case entry.kind == "nextCatalog":
+				c.next = append(c.next, entry.catalog)
+			default:
+				c.entries = append(c.entries, *entry)
+			}
+		case xml.EndElement:
+			bases = bases[:len(bases)-1]
+		}
+	}
+
+	return c, nil
+}
+
+// newCatalogEntry creates an entry of a catalog element. It returns nil for elements which are not entries.
+func (r *Resolver) newCatalogEntry(t xml.StartElement, base *url.URL, visited map[string]*catalog) (*catalogEntry, error) {
+	var matchAttr, uriAttr string
+	switch t.Name.Local {
+	case "system":
+		matchAttr, uriAttr = "systemId", "uri"
+	case "rewriteSystem":
+		matchAttr, uriAttr = "systemIdStartString", "rewritePrefix"
+	case "systemSuffix":
+		matchAttr, uriAttr = "systemIdSuffix", "uri"
+	case "delegateSystem":
+		matchAttr, uriAttr = "systemIdStartString", "catalog"
+	case "uri":
+		matchAttr, uriAttr = "name", "uri"
+	case "rewriteURI":
+		matchAttr, uriAttr = "uriStartString", "rewritePrefix"
+	case "uriSuffix":
+		matchAttr, uriAttr = "uriSuffix", "uri"
+	case "delegateURI":
+		matchAttr, uriAttr = "uriStartString", "catalog"
+	case "nextCatalog":
+		uriAttr = "catalog"
+	default:
+		return nil, nil
+	}
+
+	entry := &catalogEntry{kind: t.Name.Local}
+	if matchAttr != "" {
+		v, ok := attrValue(t, xml.Name{Local: matchAttr})
+		if !ok {
+			return nil, fmt.Errorf("the %s attribute of %s is required", matchAttr, t.Name.Local)
+		}
+		entry.match = v
+	}
+	v, ok := attrValue(t, xml.Name{Local: uriAttr})
+	if !ok {
+		return nil, fmt.Errorf("the %s attribute of %s is required", uriAttr, t.Name.Local)
+	}
+	u, err := resolveAgainst(base, v)
+	if err != nil {
+		return nil, err
+	}
+	entry.uri = u
+
+	if uriAttr == "catalog" {
+		entry.catalog, err = r.readCatalog(u, visited)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// lookupSystem finds a location of a system identifier, see 7.2.2 Resolution of External Identifiers.
+func (c *catalog) lookupSystem(systemId string) (*url.URL, bool) {
+	return c.lookup(systemId, "system", "rewriteSystem", "systemSuffix", "delegateSystem", make(map[*catalog]bool))
+}
+
+// lookupURI finds a location of a URI, see 7.2.2 Resolution of URI references.
+func (c *catalog) lookupURI(uri string) (*url.URL, bool) {
+	return c.lookup(uri, "uri", "rewriteURI", "uriSuffix", "delegateURI", make(map[*catalog]bool))
+}
+
+// lookup finds a location by the entries of the kinds. An exact match wins, then the longest rewritten prefix, then
+// the longest suffix. If delegates match, only their catalogs are consulted, otherwise the next catalogs are.
+func (c *catalog) lookup(id, exact, rewrite, suffix, delegate string, visited map[*catalog]bool) (*url.URL, bool) {
+	if visited[c] {
+		return nil, false
+	}
+	visited[c] = true
+
+	var rewriting, suffixed *catalogEntry
+	var delegates []*catalogEntry
+	for i := range c.entries {
+		e := &c.entries[i]
+		switch e.kind {
+		case exact:
+			if e.match == id {
+				return e.uri, true
+			}
+		case rewrite:
+			if strings.HasPrefix(id, e.match) && (rewriting == nil || len(e.match) > len(rewriting.match)) {
+				rewriting = e
+			}
+		case suffix:
+			if strings.HasSuffix(id, e.match) && (suffixed == nil || len(e.match) > len(suffixed.match)) {
+				suffixed = e
+			}
+		case delegate:
+			if strings.HasPrefix(id, e.match) {
+				delegates = append(delegates, e)
+			}
+		}
+	}
+
+	if rewriting != nil {
+		u, err := url.Parse(rewriting.uri.String() + strings.TrimPrefix(id, rewriting.match))
+		return u, err == nil
+	}
+	if suffixed != nil {
+		return suffixed.uri, true
+	}
+	if len(delegates) > 0 {
+		sort.SliceStable(delegates, func(i, j int) bool {
+			return len(delegates[i].match) > len(delegates[j].match)
+		})
+		for _, e := range delegates {
+			if u, ok := e.catalog.lookup(id, exact, rewrite, suffix, delegate, visited); ok {
+				return u, true
+			}
+		}
+		return nil, false
+	}
+	for _, next := range c.next {
+		if u, ok := next.lookup(id, exact, rewrite, suffix, delegate, visited); ok {
+			return u, true
+		}
+	}
+	return nil, false
+}
+
+// attrValue returns a value of an attribute of an element.
+func attrValue(t xml.StartElement, name xml.Name) (string, bool) {
+	for _, a := range t.Attr {
+		if a.Name == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// resolveAgainst resolves a URI reference against a base URI.
+func resolveAgainst(base *url.URL, ref string) (*url.URL, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, err
+	}
+	return base.ResolveReference(u), nil
+}
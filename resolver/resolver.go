@@ -0,0 +1,172 @@
+// Package resolver finds and reads schema documents which are imported, included, redefined or overridden by other
+// schema documents. Documents are read from a local file system, or from an fs.FS, and an OASIS XML Catalog could map
+// namespaces and remote locations of documents to their local copies.
+//
+// Locations of schema documents are URIs. A file is referred to by a file URI, or by a path which is relative to the
+// current directory, or to the root of an fs.FS.
+package resolver
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xsd"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Resolver resolves schema documents. Each document is decoded once, and the same *xsd.Schema is returned for all
+// references to it.
+type Resolver struct {
+	fsys     fs.FS
+	base     *url.URL
+	catalogs []*catalog
+
+	mu      sync.Mutex
+	schemas map[string]*xsd.Schema
+}
+
+// New returns a resolver which reads documents from fsys. A nil fsys is the local file system.
+func New(fsys fs.FS) *Resolver {
+	base := &url.URL{Scheme: "file", Path: "/"}
+	if fsys == nil {
+		if wd, err := os.Getwd(); err == nil {
+			base.Path = filepath.ToSlash(wd) + "/"
+			if !strings.HasPrefix(base.Path, "/") {
+				base.Path = "/" + base.Path
+			}
+		}
+	}
+	return &Resolver{
+		fsys:    fsys,
+		base:    base,
+		schemas: make(map[string]*xsd.Schema),
+	}
+}
+
+// AddCatalog reads an XML Catalog along with the catalogs it refers to. Catalogs are consulted in the order
+// they are added.
+func (r *Resolver) AddCatalog(location string) error {
+	u, err := r.resolve(location)
+	if err != nil {
+		return err
+	}
+	c, err := r.readCatalog(u, make(map[string]*catalog))
+	if err != nil {
+		return err
+	}
+	r.catalogs = append(r.catalogs, c)
+	return nil
+}
+
+// Load returns a schema document. The location is mapped by catalogs if they have an entry for it.
+func (r *Resolver) Load(location string) (*xsd.Schema, error) {
+	u, err := r.resolve(location)
+	if err != nil {
+		return nil, err
+	}
+	if mapped, ok := r.lookupLocation(u.String()); ok {
+		u = mapped
+	}
+	return r.load(u)
+}
+
+// Resolve returns a schema document of a namespace, which is expected at the schemaLocation. Catalog entries of the
+// schemaLocation take precedence over entries of the namespace. It could be used as goxsd.Generator.ImportResolver.
+func (r *Resolver) Resolve(namespace string, schemaLocation string) (*xsd.Schema, error) {
+	if schemaLocation != "" {
+		u, err := r.resolve(schemaLocation)
+		if err != nil {
+			return nil, err
+		}
+		if mapped, ok := r.lookupLocation(u.String()); ok {
+			return r.load(mapped)
+		}
+		if mapped, ok := r.lookupURI(namespace); ok {
+			return r.load(mapped)
+		}
+		return r.load(u)
+	}
+
+	if mapped, ok := r.lookupURI(namespace); ok {
+		return r.load(mapped)
+	}
+	return nil, fmt.Errorf("could not find a location of %s", namespace)
+}
+
+// lookupLocation finds a location of a document in catalogs by its system identifier or URI.
+func (r *Resolver) lookupLocation(location string) (*url.URL, bool) {
+	for _, c := range r.catalogs {
+		if u, ok := c.lookupSystem(location); ok {
+			return u, true
+		}
+	}
+	return r.lookupURI(location)
+}
+
+// lookupURI finds a location of a resource in catalogs by its URI.
+func (r *Resolver) lookupURI(uri string) (*url.URL, bool) {
+	if uri == "" {
+		return nil, false
+	}
+	for _, c := range r.catalogs {
+		if u, ok := c.lookupURI(uri); ok {
+			return u, true
+		}
+	}
+	return nil, false
+}
+
+// resolve resolves a location against the current directory or the root of the file system.
+func (r *Resolver) resolve(location string) (*url.URL, error) {
+	if filepath.IsAbs(location) && filepath.Separator != '/' {
+		return &url.URL{Scheme: "file", Path: "/" + filepath.ToSlash(location)}, nil
+	}
+	ref, err := url.Parse(filepath.ToSlash(location))
+	if err != nil {
+		return nil, err
+	}
+	return r.base.ResolveReference(ref), nil
+}
+
+// load reads and decodes a schema document once.
+func (r *Resolver) load(u *url.URL) (*xsd.Schema, error) {
+	location := u.String()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.schemas[location]; ok {
+		return s, nil
+	}
+
+	data, err := r.read(u)
+	if err != nil {
+		return nil, err
+	}
+	s := &xsd.Schema{}
+	if err := xml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("%s: %w", location, err)
+	}
+	s.Location = location
+	r.schemas[location] = s
+	return s, nil
+}
+
+// read reads a local document.
+func (r *Resolver) read(u *url.URL) ([]byte, error) {
+	if u.Scheme != "file" || (u.Host != "" && u.Host != "localhost") {
+		return nil, fmt.Errorf("could not read %s: it is not a local document, and no catalog maps it", u)
+	}
+	if r.fsys != nil {
+		return fs.ReadFile(r.fsys, strings.TrimPrefix(u.Path, "/"))
+	}
+
+	name := u.Path
+	if filepath.Separator != '/' {
+		name = strings.TrimPrefix(name, "/")
+	}
+	return os.ReadFile(filepath.FromSlash(name))
+}
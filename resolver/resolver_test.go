@@ -0,0 +1,87 @@
+package resolver
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"testing/fstest"
+)
+
+const catalogXML = `<catalog xmlns="urn:oasis:names:tc:entity:xmlns:xml:catalog">
+	<system systemId="http://example.com/common.xsd" uri="local/common.xsd"/>
+	<uri name="urn:ext" uri="local/ext.xsd"/>
+	<group xml:base="local/">
+		<rewriteSystem systemIdStartString="http://example.com/types/" rewritePrefix="types/"/>
+	</group>
+	<nextCatalog catalog="next.xml"/>
+</catalog>`
+
+const nextCatalogXML = `<catalog xmlns="urn:oasis:names:tc:entity:xmlns:xml:catalog">
+	<uriSuffix uriSuffix="/other.xsd" uri="local/other.xsd"/>
+	<nextCatalog catalog="catalog.xml"/>
+</catalog>`
+
+func schemaXML(ns string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="` + ns + `">
+		<xs:element name="root" type="xs:string"/>
+	</xs:schema>`)}
+}
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"catalog.xml":           {Data: []byte(catalogXML)},
+		"next.xml":              {Data: []byte(nextCatalogXML)},
+		"schemas/main.xsd":      schemaXML("urn:main"),
+		"local/common.xsd":      schemaXML("urn:common"),
+		"local/ext.xsd":         schemaXML("urn:ext"),
+		"local/other.xsd":       schemaXML("urn:other"),
+		"local/types/dates.xsd": schemaXML("urn:dates"),
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r := New(testFS())
+	assert.NoError(t, r.AddCatalog("catalog.xml"))
+
+	main, err := r.Load("schemas/main.xsd")
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:main", main.TargetNamespace)
+	assert.Equal(t, "file:///schemas/main.xsd", main.Location)
+
+	s, err := r.Resolve("urn:main", "file:///schemas/main.xsd")
+	assert.NoError(t, err)
+	assert.True(t, s == main, "a document is decoded once")
+
+	s, err = r.Resolve("urn:common", "http://example.com/common.xsd")
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:common", s.TargetNamespace)
+	assert.Equal(t, "file:///local/common.xsd", s.Location)
+
+	s, err = r.Resolve("urn:ext", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:ext", s.TargetNamespace)
+
+	s, err = r.Resolve("urn:ext", "http://example.com/unknown.xsd")
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:ext", s.TargetNamespace)
+
+	s, err = r.Resolve("urn:dates", "http://example.com/types/dates.xsd")
+	assert.NoError(t, err)
+	assert.Equal(t, "file:///local/types/dates.xsd", s.Location)
+
+	s, err = r.Resolve("urn:other", "http://example.com/any/other.xsd")
+	assert.NoError(t, err)
+	assert.Equal(t, "urn:other", s.TargetNamespace)
+}
+
+func TestResolveNotFound(t *testing.T) {
+	r := New(testFS())
+	assert.NoError(t, r.AddCatalog("catalog.xml"))
+
+	_, err := r.Resolve("urn:unknown", "")
+	assert.Error(t, err)
+	_, err = r.Resolve("urn:unknown", "http://example.com/unknown.xsd")
+	assert.Error(t, err)
+	_, err = r.Resolve("urn:unknown", "file:///schemas/unknown.xsd")
+	assert.Error(t, err)
+	assert.Error(t, r.AddCatalog("unknown.xml"))
+}
@@ -34,6 +34,15 @@ type (
 		PkgName  string
 		Imports  []Decl
 		DeclList []Decl
+
+		// goTypes replaces Go types of built-in types, see Generator.goTypes.
+		goTypes map[string]string
+		// wildcards holds names of Go types of element wildcards, see createWildcardDecls.
+		wildcards map[*wildcard]string
+		// packages holds packages of components referred to by qualified names, see Generator.GeneratePackages.
+		packages map[string]*goPackage
+		// names resolves Go identifiers of the file, see naming.
+		names *naming
 	}
 )
 
@@ -47,6 +56,17 @@ type (
 		decl
 	}
 
+	// NameList
+	// NameList      = Values
+	// NameList Type = Values
+	ConstDecl struct {
+		NameList []*Name
+		Type     Expr   // nil means no type
+		Values   Expr   // nil means no values
+		Group    *Group // nil means not part of a group
+		decl
+	}
+
 	// NameList Type
 	// NameList Type = Values
 	// NameList      = Values
@@ -66,6 +86,17 @@ type (
 		decl
 	}
 
+	// func          Name Type { Body }
+	// func (Recv)   Name Type { Body }
+	FuncDecl struct {
+		Doc  string // doc comment without the leading //, "" means no comment
+		Recv *Field // nil means regular function
+		Name *Name
+		Type *FuncType
+		Body *BlockStmt // nil means no body (forward declaration)
+		decl
+	}
+
 	Decl interface {
 		Node
 		aDecl()
@@ -138,12 +169,59 @@ type (
 		Key, Value Expr
 		expr
 	}
+
+	// func(ParamList) ResultList
+	FuncType struct {
+		ParamList  []*Field
+		ResultList []*Field
+		expr
+	}
 )
 
 type expr struct{ node }
 
 func (*expr) aExpr() {}
 
+//-----------------------------------
+// Statements
+
+type (
+	Stmt interface {
+		Node
+		aStmt()
+	}
+
+	// { List[0]; List[1]; ... }
+	BlockStmt struct {
+		List []Stmt
+		stmt
+	}
+
+	// X
+	ExprStmt struct {
+		X Expr
+		stmt
+	}
+
+	// Lhs = Rhs
+	// Lhs := Rhs
+	AssignStmt struct {
+		Define   bool
+		Lhs, Rhs Expr
+		stmt
+	}
+
+	// return Results
+	ReturnStmt struct {
+		Results Expr // nil means no explicit return values
+		stmt
+	}
+)
+
+type stmt struct{ node }
+
+func (*stmt) aStmt() {}
+
 //-----------------------------------
 // Functions
 
@@ -159,6 +237,16 @@ func (f *File) Require(path string) {
 	}
 }
 
+// RequireNamed adds an import of a package under a local name.
+func (f *File) RequireNamed(path string, name string) {
+	f.Require(path)
+	for _, i := range f.Imports {
+		if i.(*ImportDecl).Path.Value == `"`+path+`"` {
+			i.(*ImportDecl).LocalPkgName = &Name{Value: name}
+		}
+	}
+}
+
 func (f *File) HasImport(path string) bool {
 	for _, i := range f.Imports {
 		if i.(*ImportDecl).Path.Value == `"`+path+`"` {
@@ -174,13 +262,20 @@ func (f *File) Write(buf *bytes.Buffer) {
 
 	writePackageName(buf, f)
 	p.print(&printGroup{Tok: _Import, Decls: f.Imports}, newline, newline)
-	for _, t := range f.DeclList {
-		p.print(t, newline)
-	}
+	p.printDeclList(f.DeclList)
 
 	p.flush(_EOF)
 }
 
+// exprString returns the source of an expression as the printer prints it.
+func exprString(x Expr) string {
+	buf := new(bytes.Buffer)
+	p := printer{output: buf}
+	p.print(x)
+	p.flush(_EOF)
+	return buf.String()
+}
+
 func writePackageName(w *bytes.Buffer, file *File) (int, error) {
 	return w.WriteString("package " + file.PkgName + "\n\n")
 }
@@ -14,3 +14,35 @@ func TestTypeDecl(t *testing.T) {
 	p.flush(_EOF)
 	assert.Equal(t, "\ntype Lastname string", buf.String())
 }
+
+func TestFuncDecl(t *testing.T) {
+	d := &FuncDecl{
+		Recv: &Field{Name: &Name{Value: "t"}, Type: &Name{Value: "Lastname"}},
+		Name: &Name{Value: "String"},
+		Type: &FuncType{ResultList: []*Field{{Type: &Name{Value: "string"}}}},
+		Body: &BlockStmt{List: []Stmt{&ReturnStmt{Results: &BasicLit{Value: "string(t)"}}}},
+	}
+	buf := new(bytes.Buffer)
+	p := printer{output: buf}
+	p.print(d)
+	p.flush(_EOF)
+	assert.Equal(t, "\nfunc (t Lastname) String() string {\nreturn string(t)\n}", buf.String())
+}
+
+func TestConstDeclGroup(t *testing.T) {
+	g := &Group{}
+	list := []Decl{
+		&ConstDecl{NameList: []*Name{{Value: "ColorRed"}}, Type: &Name{Value: "Color"}, Values: &BasicLit{Value: `"red"`}, Group: g},
+		&ConstDecl{NameList: []*Name{{Value: "ColorBlue"}}, Type: &Name{Value: "Color"}, Values: &BasicLit{Value: `"blue"`}, Group: g},
+	}
+	buf := new(bytes.Buffer)
+	p := printer{output: buf}
+	p.printDeclList(list)
+	p.flush(_EOF)
+	assert.Equal(t, "\nconst (\nColorRed Color = \"red\"\nColorBlue Color = \"blue\"\n)\n", buf.String())
+}
+
+func TestExprString(t *testing.T) {
+	assert.Equal(t, "string", exprString(&Name{Value: "string"}))
+	assert.Equal(t, "[]*common.Address", exprString(&SliceType{Elem: &PointerType{Elem: &Name{Value: "common.Address"}}}))
+}
@@ -2,13 +2,124 @@ package simple04
 
 import (
 	"encoding/xml"
+	"fmt"
+	"strings"
 )
 
-type Employee struct {
-	XMLName   xml.Name `xml:"urn:caementarii:simple employee"`
-	Firstname string   `xml:"firstname"`
-	Lastname  string   `xml:"lastname"`
-	Address   string   `xml:"address"`
-	City      string   `xml:"city"`
-	Country   string   `xml:"country"`
+var nsEmployeeQName = xml.Name{Space: "urn:caementarii:simple", Local: "employee"}
+
+type Employee Fullpersoninfo
+
+func (t *Employee) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*Fullpersoninfo)(t), &start)
+}
+
+func (t Employee) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsEmployeeQName
+	return e.EncodeElement(Fullpersoninfo(t), start)
+}
+
+type Fullpersoninfo struct {
+	Personinfo
+	Address string `xml:"address"`
+	City    string `xml:"city"`
+	Country string `xml:"country"`
+}
+
+type Personinfo struct {
+	Firstname string `xml:"firstname"`
+	Lastname  string `xml:"lastname"`
+}
+
+type PersoninfoValue interface {
+	isPersoninfo()
+}
+
+func (Personinfo) isPersoninfo() {}
+
+func (Fullpersoninfo) isPersoninfo() {}
+
+type AnyPersoninfo struct {
+	Value PersoninfoValue
+}
+
+func (t *AnyPersoninfo) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	name, ok, err := xsiType(&start)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		name = xml.Name{Space: "urn:caementarii:simple", Local: "personinfo"}
+	}
+	switch name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "personinfo"}:
+		var v Personinfo
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "fullpersoninfo"}:
+		var v Fullpersoninfo
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	}
+	return fmt.Errorf("xsi:type {%s}%s is not allowed for personinfo", name.Space, name.Local)
+}
+
+func (t AnyPersoninfo) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch v := t.Value.(type) {
+	case nil:
+		return nil
+	case Personinfo, *Personinfo:
+		return e.EncodeElement(v, start)
+	case Fullpersoninfo, *Fullpersoninfo:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "fullpersoninfo", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	}
+	return fmt.Errorf("%T is not allowed for personinfo", t.Value)
+}
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// xsiType returns the type name of the xsi:type attribute of an element and removes the attribute.
+//
+// The decoder of encoding/xml doesn't report namespaces declared by ancestors of the element, so the
+// prefix of the name is resolved only by declarations of the element itself. Decode by xs.NewDecoder,
+// which copies them from the ancestors: with xml.NewDecoder or xml.Unmarshal a prefix declared by an
+// ancestor is an error "prefix ... is not declared", and a name without a prefix has no namespace
+// unless the element declares a default one.
+func xsiType(start *xml.StartElement) (xml.Name, bool, error) {
+	for i, attr := range start.Attr {
+		if attr.Name.Space != xsiNamespace || attr.Name.Local != "type" {
+			continue
+		}
+		start.Attr = append(start.Attr[:i:i], start.Attr[i+1:]...)
+		prefix, local, found := strings.Cut(strings.TrimSpace(attr.Value), ":")
+		if !found {
+			prefix, local = "", prefix
+		}
+		for _, ns := range start.Attr {
+			if (prefix != "" && ns.Name.Space == "xmlns" && ns.Name.Local == prefix) || (prefix == "" && ns.Name.Space == "" && ns.Name.Local == "xmlns") {
+				return xml.Name{Space: ns.Value, Local: local}, true, nil
+			}
+		}
+		if prefix != "" {
+			return xml.Name{}, true, fmt.Errorf("xsi:type %q: prefix %s is not declared", attr.Value, prefix)
+		}
+		return xml.Name{Local: local}, true, nil
+	}
+	return xml.Name{}, false, nil
+}
+
+func xsiTypeAttrs(element string, space string, local string, prefix string) []xml.Attr {
+	attr := xml.Attr{Name: xml.Name{Space: xsiNamespace, Local: "type"}, Value: local}
+	if space == "" || space == element {
+		return []xml.Attr{attr}
+	}
+	attr.Value = prefix + ":" + local
+	return []xml.Attr{{Name: xml.Name{Local: "xmlns:" + prefix}, Value: space}, attr}
 }
@@ -60,7 +60,7 @@ func TestUnmarshaler(t *testing.T) {
 		t.Fatal(e)
 	}
 
-	expected := Employee{XMLName: xml.Name{Space: "urn:caementarii:simple", Local: "employee"}}
+	expected := Employee{}
 	expected.Firstname = "first"
 	expected.Lastname = "last"
 	expected.Address = "address"
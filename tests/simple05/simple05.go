@@ -0,0 +1,43 @@
+package simple05
+
+import (
+	"encoding/xml"
+)
+
+var nsCustomerQName = xml.Name{Space: "urn:caementarii:simple", Local: "customer"}
+
+type Customer Personinfo
+
+func (t *Customer) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*Personinfo)(t), &start)
+}
+
+func (t Customer) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsCustomerQName
+	return e.EncodeElement(Personinfo(t), start)
+}
+
+type Order struct {
+	XMLName   xml.Name     `xml:"urn:caementarii:simple order"`
+	Buyer     Personinfo   `xml:"buyer"`
+	Recipient *Personinfo  `xml:"recipient"`
+	Contact   []Personinfo `xml:"contact"`
+}
+
+type Personinfo struct {
+	Firstname string `xml:"firstname"`
+	Lastname  string `xml:"lastname"`
+}
+
+var nsSupplierQName = xml.Name{Space: "urn:caementarii:simple", Local: "supplier"}
+
+type Supplier Personinfo
+
+func (t *Supplier) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*Personinfo)(t), &start)
+}
+
+func (t Supplier) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsSupplierQName
+	return e.EncodeElement(Personinfo(t), start)
+}
@@ -0,0 +1,83 @@
+package simple05
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple05(t *testing.T) {
+	data, err := os.ReadFile("simple05.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple05",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple05.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestMarshaler(t *testing.T) {
+	tests := []struct {
+		in  interface{}
+		out string
+	}{
+		{Customer{Firstname: "first", Lastname: "last"}, `<customer xmlns="urn:caementarii:simple"><firstname>first</firstname><lastname>last</lastname></customer>`},
+		{Supplier{Firstname: "first", Lastname: "last"}, `<supplier xmlns="urn:caementarii:simple"><firstname>first</firstname><lastname>last</lastname></supplier>`},
+		{
+			Order{Buyer: Personinfo{Firstname: "a", Lastname: "b"}, Contact: []Personinfo{{Firstname: "c", Lastname: "d"}}},
+			`<order xmlns="urn:caementarii:simple"><buyer><firstname>a</firstname><lastname>b</lastname></buyer><contact><firstname>c</firstname><lastname>d</lastname></contact></order>`,
+		},
+	}
+
+	for _, tt := range tests {
+		data, e := xml.Marshal(tt.in)
+		if e != nil {
+			t.Fatal(e)
+		}
+		assert.Equal(t, tt.out, string(data))
+	}
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<order xmlns="urn:caementarii:simple"><buyer><firstname>a</firstname><lastname>b</lastname></buyer><recipient><firstname>c</firstname><lastname>d</lastname></recipient></order>`
+	out := Order{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	expected := Order{
+		XMLName:   xml.Name{Space: "urn:caementarii:simple", Local: "order"},
+		Buyer:     Personinfo{Firstname: "a", Lastname: "b"},
+		Recipient: &Personinfo{Firstname: "c", Lastname: "d"},
+	}
+	assert.Equal(t, expected, out)
+
+	var customer Customer
+	e = xml.Unmarshal([]byte(`<customer xmlns="urn:caementarii:simple"><firstname>a</firstname><lastname>b</lastname></customer>`), &customer)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, Customer{Firstname: "a", Lastname: "b"}, customer)
+}
@@ -0,0 +1,160 @@
+package simple06
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+type Contact struct {
+	XMLName xml.Name `xml:"urn:caementarii:simple contact"`
+	Name    string   `xml:"name"`
+	Email   *string  `xml:"email"`
+	Phone   *string  `xml:"phone"`
+}
+
+func (t Contact) Which() string {
+	switch {
+	case t.Email != nil:
+		return "email"
+	case t.Phone != nil:
+		return "phone"
+	}
+	return ""
+}
+
+func (t Contact) Validate() error {
+	n := 0
+	if t.Email != nil {
+		n++
+	}
+	if t.Phone != nil {
+		n++
+	}
+	if n != 1 {
+		return fmt.Errorf("exactly one of email, phone must be present in Contact, got %d", n)
+	}
+	return nil
+}
+
+func (t Contact) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	type alias Contact
+	return e.Encode(alias(t))
+}
+
+func (t *Contact) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Contact
+	if err := d.DecodeElement((*alias)(t), &start); err != nil {
+		return err
+	}
+	return t.Validate()
+}
+
+type Order struct {
+	XMLName xml.Name      `xml:"urn:caementarii:simple order"`
+	Payment Payment       `xml:"payment"`
+	Choice  []OrderChoice `xml:",any"`
+}
+
+type OrderChoice struct {
+	Item *string
+	Note *string
+}
+
+func (t OrderChoice) Which() string {
+	switch {
+	case t.Item != nil:
+		return "item"
+	case t.Note != nil:
+		return "note"
+	}
+	return ""
+}
+
+func (t OrderChoice) Validate() error {
+	n := 0
+	if t.Item != nil {
+		n++
+	}
+	if t.Note != nil {
+		n++
+	}
+	if n != 1 {
+		return fmt.Errorf("exactly one of item, note must be present in OrderChoice, got %d", n)
+	}
+	return nil
+}
+
+func (t *OrderChoice) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "item"}:
+		return d.DecodeElement(&t.Item, &start)
+	case xml.Name{Space: "urn:caementarii:simple", Local: "note"}:
+		return d.DecodeElement(&t.Note, &start)
+	}
+	return fmt.Errorf("element %s of namespace %q is not allowed in OrderChoice", start.Name.Local, start.Name.Space)
+}
+
+func (t OrderChoice) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	switch {
+	case t.Item != nil:
+		return e.EncodeElement(t.Item, xml.StartElement{Name: xml.Name{Space: "urn:caementarii:simple", Local: "item"}})
+	case t.Note != nil:
+		return e.EncodeElement(t.Note, xml.StartElement{Name: xml.Name{Space: "urn:caementarii:simple", Local: "note"}})
+	}
+	return nil
+}
+
+type Payment struct {
+	Card *string `xml:"card"`
+	Iban *string `xml:"iban"`
+	Bic  *string `xml:"bic"`
+}
+
+func (t Payment) Which() string {
+	switch {
+	case t.Card != nil:
+		return "card"
+	case t.Iban != nil || t.Bic != nil:
+		return "iban"
+	}
+	return ""
+}
+
+func (t Payment) Validate() error {
+	n := 0
+	if t.Card != nil {
+		n++
+	}
+	if t.Iban != nil || t.Bic != nil {
+		n++
+	}
+	if n > 1 {
+		return fmt.Errorf("at most one of card, iban must be present in Payment, got %d", n)
+	}
+	if t.Bic != nil && t.Iban == nil {
+		return fmt.Errorf("element iban is missing in Payment")
+	}
+	return nil
+}
+
+func (t Payment) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	type alias Payment
+	return e.EncodeElement(alias(t), start)
+}
+
+func (t *Payment) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Payment
+	if err := d.DecodeElement((*alias)(t), &start); err != nil {
+		return err
+	}
+	return t.Validate()
+}
@@ -0,0 +1,142 @@
+package simple06
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple06(t *testing.T) {
+	data, err := os.ReadFile("simple06.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple06",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple06.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestMarshaler(t *testing.T) {
+	tests := []struct {
+		in  interface{}
+		out string
+	}{
+		{Contact{Name: "name", Email: xsstring("a@b.c")}, `<contact xmlns="urn:caementarii:simple"><name>name</name><email>a@b.c</email></contact>`},
+		{Contact{Name: "name", Phone: xsstring("123")}, `<contact xmlns="urn:caementarii:simple"><name>name</name><phone>123</phone></contact>`},
+		{
+			Order{Payment: Payment{Iban: xsstring("DE00")}, Choice: []OrderChoice{{Item: xsstring("a")}, {Note: xsstring("n")}, {Item: xsstring("b")}}},
+			`<order xmlns="urn:caementarii:simple"><payment><iban>DE00</iban></payment><item xmlns="urn:caementarii:simple">a</item>` +
+				`<note xmlns="urn:caementarii:simple">n</note><item xmlns="urn:caementarii:simple">b</item></order>`,
+		},
+		{Order{}, `<order xmlns="urn:caementarii:simple"><payment></payment></order>`},
+	}
+
+	for _, tt := range tests {
+		data, e := xml.Marshal(tt.in)
+		if e != nil {
+			t.Fatal(e)
+		}
+		assert.Equal(t, tt.out, string(data))
+	}
+}
+
+func TestMarshalerChecksChoice(t *testing.T) {
+	tests := []interface{}{
+		Contact{Name: "name"},
+		Contact{Name: "name", Email: xsstring("a@b.c"), Phone: xsstring("123")},
+		Order{Payment: Payment{Card: xsstring("1234"), Iban: xsstring("DE00")}},
+		Order{Payment: Payment{Bic: xsstring("DEUT")}},
+		Order{Choice: []OrderChoice{{Item: xsstring("a"), Note: xsstring("n")}}},
+		Order{Choice: []OrderChoice{{}}},
+	}
+
+	for _, tt := range tests {
+		_, e := xml.Marshal(tt)
+		if e == nil {
+			t.Errorf("expected an error for %+v", tt)
+		}
+	}
+}
+
+func TestWhich(t *testing.T) {
+	var r Contact
+	e := xml.Unmarshal([]byte(`<contact xmlns="urn:caementarii:simple"><name>name</name><phone>123</phone></contact>`), &r)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, "phone", r.Which())
+
+	assert.Equal(t, "", Payment{}.Which())
+	assert.Equal(t, "iban", Payment{Iban: xsstring("DE00")}.Which())
+	// The branch is reported by its first element.
+	assert.Equal(t, "iban", Payment{Iban: xsstring("DE00"), Bic: xsstring("DEUT")}.Which())
+	assert.Equal(t, "iban", Payment{Bic: xsstring("DEUT")}.Which())
+	assert.Equal(t, "note", OrderChoice{Note: xsstring("n")}.Which())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	// Items of the repeated choice keep their order.
+	var order Order
+	e := xml.Unmarshal([]byte(`<order xmlns="urn:caementarii:simple"><payment><iban>DE00</iban><bic>DEUT</bic></payment>`+
+		`<item>a</item><note>n</note><item>b</item></order>`), &order)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, Payment{Iban: xsstring("DE00"), Bic: xsstring("DEUT")}, order.Payment)
+	assert.Equal(t, []OrderChoice{{Item: xsstring("a")}, {Note: xsstring("n")}, {Item: xsstring("b")}}, order.Choice)
+
+	data, e := xml.Marshal(order)
+	if e != nil {
+		t.Fatal(e)
+	}
+	// Items are written in the namespace of the elements.
+	assert.Equal(t, `<order xmlns="urn:caementarii:simple"><payment><iban>DE00</iban><bic>DEUT</bic></payment>`+
+		`<item xmlns="urn:caementarii:simple">a</item><note xmlns="urn:caementarii:simple">n</note>`+
+		`<item xmlns="urn:caementarii:simple">b</item></order>`, string(data))
+}
+
+func TestUnmarshalerChecksChoice(t *testing.T) {
+	tests := []struct {
+		in  string
+		out interface{}
+	}{
+		{`<contact xmlns="urn:caementarii:simple"><name>name</name></contact>`, &Contact{}},
+		{`<contact xmlns="urn:caementarii:simple"><name>name</name><email>a@b.c</email><phone>123</phone></contact>`, &Contact{}},
+		{`<order xmlns="urn:caementarii:simple"><payment><card>1234</card><iban>DE00</iban></payment></order>`, &Order{}},
+		// The required iban is missing in the branch.
+		{`<order xmlns="urn:caementarii:simple"><payment><bic>DEUT</bic></payment></order>`, &Order{}},
+		{`<order xmlns="urn:caementarii:simple"><payment/><item>a</item><other>x</other></order>`, &Order{}},
+		// Items of the choice are qualified.
+		{`<order xmlns="urn:caementarii:simple"><payment/><item xmlns="urn:other">a</item></order>`, &Order{}},
+	}
+
+	for _, tt := range tests {
+		if e := xml.Unmarshal([]byte(tt.in), tt.out); e == nil {
+			t.Errorf("expected an error for %s", tt.in)
+		}
+	}
+}
+
+func xsstring(s string) *string {
+	return &s
+}
@@ -0,0 +1,113 @@
+package simple07
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+var nsAddressQName = xml.Name{Space: "urn:caementarii:simple", Local: "address"}
+
+type Address AddressType
+
+func (t *Address) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*AddressType)(t), &start)
+}
+
+func (t Address) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsAddressQName
+	return e.EncodeElement(AddressType(t), start)
+}
+
+type AddressType struct {
+	Street string   `xml:"street"`
+	City   string   `xml:"city"`
+	Zip    *string  `xml:"zip"`
+	Note   []string `xml:"note"`
+}
+
+func (t *AddressType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type XMLAlias AddressType
+	v := struct {
+		*XMLAlias
+		Street []string `xml:"street"`
+		City   []string `xml:"city"`
+		Zip    []string `xml:"zip"`
+		Note   []string `xml:"note"`
+	}{
+		XMLAlias: (*XMLAlias)(t),
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	if len(v.Street) < 1 {
+		return fmt.Errorf("element street is missing in AddressType")
+	}
+	if len(v.City) < 1 {
+		return fmt.Errorf("element city is missing in AddressType")
+	}
+	if len(v.Street) > 1 {
+		return fmt.Errorf("element street occurs %d times in AddressType", len(v.Street))
+	}
+	if len(v.Street) == 1 {
+		t.Street = v.Street[0]
+	}
+	if len(v.City) > 1 {
+		return fmt.Errorf("element city occurs %d times in AddressType", len(v.City))
+	}
+	if len(v.City) == 1 {
+		t.City = v.City[0]
+	}
+	if len(v.Zip) > 1 {
+		return fmt.Errorf("element zip occurs %d times in AddressType", len(v.Zip))
+	}
+	if len(v.Zip) == 1 {
+		t.Zip = &v.Zip[0]
+	}
+	if len(v.Note) > 2 {
+		return fmt.Errorf("element note occurs %d times in AddressType, but at most 2 allowed", len(v.Note))
+	}
+	t.Note = v.Note
+	return nil
+}
+
+type Options struct {
+	XMLName xml.Name `xml:"urn:caementarii:simple options"`
+	Color   *string  `xml:"color"`
+	Size    *string  `xml:"size"`
+}
+
+func (t *Options) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type XMLAlias Options
+	v := struct {
+		*XMLAlias
+		Color []string `xml:"color"`
+		Size  []string `xml:"size"`
+	}{
+		XMLAlias: (*XMLAlias)(t),
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	t.XMLName = start.Name
+	if len(v.Color)+len(v.Size) > 0 {
+		if len(v.Color) < 1 {
+			return fmt.Errorf("element color is missing in Options")
+		}
+		if len(v.Size) < 1 {
+			return fmt.Errorf("element size is missing in Options")
+		}
+	}
+	if len(v.Color) > 1 {
+		return fmt.Errorf("element color occurs %d times in Options", len(v.Color))
+	}
+	if len(v.Color) == 1 {
+		t.Color = &v.Color[0]
+	}
+	if len(v.Size) > 1 {
+		return fmt.Errorf("element size occurs %d times in Options", len(v.Size))
+	}
+	if len(v.Size) == 1 {
+		t.Size = &v.Size[0]
+	}
+	return nil
+}
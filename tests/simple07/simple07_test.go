@@ -0,0 +1,102 @@
+package simple07
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple07(t *testing.T) {
+	data, err := os.ReadFile("simple07.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple07",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple07.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	tests := []struct {
+		in  string
+		out Address
+	}{
+		{
+			`<address xmlns="urn:caementarii:simple"><city>c</city><street>s</street></address>`,
+			Address{Street: "s", City: "c"},
+		},
+		{
+			`<address xmlns="urn:caementarii:simple"><note>a</note><zip>z</zip><city>c</city><note>b</note><street>s</street></address>`,
+			Address{Street: "s", City: "c", Zip: xsstring("z"), Note: []string{"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		var r Address
+		e := xml.Unmarshal([]byte(tt.in), &r)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		assert.Equal(t, tt.out, r)
+	}
+}
+
+func TestUnmarshalerChecksOccurrences(t *testing.T) {
+	tests := []string{
+		`<address xmlns="urn:caementarii:simple"><street>s</street></address>`,
+		`<address xmlns="urn:caementarii:simple"><street>s</street><city>c</city><city>d</city></address>`,
+		`<address xmlns="urn:caementarii:simple"><street>s</street><city>c</city><note>a</note><note>b</note><note>c</note></address>`,
+		`<options xmlns="urn:caementarii:simple"><size>s</size></options>`,
+	}
+
+	for _, tt := range tests {
+		var r interface{} = &Address{}
+		if bytes.HasPrefix([]byte(tt), []byte("<options")) {
+			r = &Options{}
+		}
+		if e := xml.Unmarshal([]byte(tt), r); e == nil {
+			t.Errorf("expected an error for %s", tt)
+		}
+	}
+}
+
+func TestOptionalGroup(t *testing.T) {
+	var r Options
+	e := xml.Unmarshal([]byte(`<options xmlns="urn:caementarii:simple"/>`), &r)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, Options{XMLName: xml.Name{Space: "urn:caementarii:simple", Local: "options"}}, r)
+
+	e = xml.Unmarshal([]byte(`<options xmlns="urn:caementarii:simple"><size>s</size><color>c</color></options>`), &r)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, "c", *r.Color)
+	assert.Equal(t, "s", *r.Size)
+}
+
+func xsstring(s string) *string {
+	return &s
+}
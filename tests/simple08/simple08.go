@@ -0,0 +1,73 @@
+package simple08
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+type Employee struct {
+	XMLName   xml.Name `xml:"urn:caementarii:simple employee"`
+	Firstname string   `xml:"firstname"`
+	Lastname  string   `xml:"lastname"`
+}
+
+var nsNoteQName = xml.Name{Space: "urn:caementarii:simple", Local: "note"}
+
+type Note string
+
+func (t *Note) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*string)(t), &start)
+}
+
+func (t Note) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsNoteQName
+	return e.EncodeElement(string(t), start)
+}
+
+type Person struct {
+	Firstname string  `xml:"firstname"`
+	Lastname  string  `xml:"lastname"`
+	Email     *string `xml:"email"`
+	Phone     *string `xml:"phone"`
+	Note      []Note  `xml:"urn:caementarii:simple note"`
+}
+
+func (t Person) Which() string {
+	switch {
+	case t.Email != nil:
+		return "email"
+	case t.Phone != nil:
+		return "phone"
+	}
+	return ""
+}
+
+func (t Person) Validate() error {
+	n := 0
+	if t.Email != nil {
+		n++
+	}
+	if t.Phone != nil {
+		n++
+	}
+	if n > 1 {
+		return fmt.Errorf("at most one of email, phone must be present in Person, got %d", n)
+	}
+	return nil
+}
+
+func (t Person) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	type alias Person
+	return e.EncodeElement(alias(t), start)
+}
+
+func (t *Person) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Person
+	if err := d.DecodeElement((*alias)(t), &start); err != nil {
+		return err
+	}
+	return t.Validate()
+}
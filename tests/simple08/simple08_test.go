@@ -0,0 +1,94 @@
+package simple08
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple08(t *testing.T) {
+	data, err := os.ReadFile("simple08.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple08",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple08.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestCircularGroup(t *testing.T) {
+	data := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:caementarii:simple" targetNamespace="urn:caementarii:simple">
+    <xs:group name="a"><xs:sequence><xs:group ref="tns:b"/></xs:sequence></xs:group>
+    <xs:group name="b"><xs:choice><xs:group ref="tns:a"/></xs:choice></xs:group>
+</xs:schema>`
+
+	s := xsd.Schema{}
+	err := xml.Unmarshal([]byte(data), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := goxsd.Generator{
+		PkgName: "simple08",
+	}
+	err = g.Generate(&s, new(bytes.Buffer))
+	if err == nil {
+		t.Fatal("expected an error for circular model groups")
+	}
+}
+
+func TestMarshaler(t *testing.T) {
+	data, e := xml.Marshal(Employee{Firstname: "first", Lastname: "last"})
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, `<employee xmlns="urn:caementarii:simple"><firstname>first</firstname><lastname>last</lastname></employee>`, string(data))
+
+	buf := new(bytes.Buffer)
+	enc := xml.NewEncoder(buf)
+	person := Person{Firstname: "first", Lastname: "last", Phone: xsstring("123"), Note: []Note{"a"}}
+	e = enc.EncodeElement(person, xml.StartElement{Name: xml.Name{Space: "urn:caementarii:simple", Local: "person"}})
+	if e != nil {
+		t.Fatal(e)
+	}
+	enc.Flush()
+	assert.Equal(t, `<person xmlns="urn:caementarii:simple"><firstname>first</firstname><lastname>last</lastname><phone>123</phone><note xmlns="urn:caementarii:simple">a</note></person>`, buf.String())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<person xmlns="urn:caementarii:simple"><firstname>first</firstname><lastname>last</lastname><email>a@b.c</email><note>a</note><note>b</note></person>`
+	out := Person{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	expected := Person{Firstname: "first", Lastname: "last", Email: xsstring("a@b.c"), Note: []Note{"a", "b"}}
+	assert.Equal(t, expected, out)
+	assert.Equal(t, "email", out.Which())
+}
+
+func xsstring(s string) *string {
+	return &s
+}
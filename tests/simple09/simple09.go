@@ -0,0 +1,53 @@
+package simple09
+
+import (
+	"encoding/xml"
+)
+
+type Message struct {
+	XMLName  xml.Name   `xml:"urn:caementarii:simple message"`
+	ID       *string    `xml:"id,attr,omitempty"`
+	Lang     *string    `xml:"lang,attr,omitempty"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
+	Note     Note       `xml:"note"`
+	Plain    Plain      `xml:"plain"`
+}
+
+func (t *Message) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	attrs := make([]xml.Attr, 0, len(start.Attr))
+	for _, a := range start.Attr {
+		if a.Name.Space != "xmlns" && !(a.Name.Space == "" && a.Name.Local == "xmlns") {
+			attrs = append(attrs, a)
+		}
+	}
+	start.Attr = attrs
+	type alias Message
+	return d.DecodeElement((*alias)(t), &start)
+}
+
+type Note struct {
+	Version  *string    `xml:"version,attr,omitempty"`
+	Created  string     `xml:"created,attr"`
+	Author   *string    `xml:"author,attr,omitempty"`
+	ID       *string    `xml:"id,attr,omitempty"`
+	Lang     *string    `xml:"lang,attr,omitempty"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
+	Body     string     `xml:"body"`
+}
+
+func (t *Note) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	attrs := make([]xml.Attr, 0, len(start.Attr))
+	for _, a := range start.Attr {
+		if a.Name.Space != "xmlns" && !(a.Name.Space == "" && a.Name.Local == "xmlns") {
+			attrs = append(attrs, a)
+		}
+	}
+	start.Attr = attrs
+	type alias Note
+	return d.DecodeElement((*alias)(t), &start)
+}
+
+type Plain struct {
+	Author *string `xml:"author,attr,omitempty"`
+	Body   string  `xml:"body"`
+}
@@ -0,0 +1,100 @@
+package simple09
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple09(t *testing.T) {
+	data, err := os.ReadFile("simple09.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple09",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple09.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestCircularAttributeGroup(t *testing.T) {
+	data := `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:caementarii:simple" targetNamespace="urn:caementarii:simple">
+    <xs:attributeGroup name="a"><xs:attributeGroup ref="tns:b"/></xs:attributeGroup>
+    <xs:attributeGroup name="b"><xs:attributeGroup ref="tns:a"/></xs:attributeGroup>
+</xs:schema>`
+
+	s := xsd.Schema{}
+	err := xml.Unmarshal([]byte(data), &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := goxsd.Generator{
+		PkgName: "simple09",
+	}
+	err = g.Generate(&s, new(bytes.Buffer))
+	if err == nil {
+		t.Fatal("expected an error for circular attribute groups")
+	}
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<message xmlns="urn:caementarii:simple" xmlns:o="urn:other" id="m1" o:trace="abc">` +
+		`<note created="today" version="2" o:flag="on"><body>hello</body></note>` +
+		`<plain author="me"><body>world</body></plain></message>`
+	out := Message{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert.Equal(t, xsstring("m1"), out.ID)
+	assert.Equal(t, []xml.Attr{{Name: xml.Name{Space: "urn:other", Local: "trace"}, Value: "abc"}}, out.AnyAttrs)
+	assert.Equal(t, "today", out.Note.Created)
+	assert.Equal(t, xsstring("2"), out.Note.Version)
+	assert.Equal(t, []xml.Attr{{Name: xml.Name{Space: "urn:other", Local: "flag"}, Value: "on"}}, out.Note.AnyAttrs)
+	assert.Equal(t, Plain{Author: xsstring("me"), Body: "world"}, out.Plain)
+}
+
+func TestRoundTrip(t *testing.T) {
+	in := `<message xmlns="urn:caementarii:simple" xmlns:o="urn:other" o:trace="abc"><note created="today"><body>hello</body></note><plain><body>world</body></plain></message>`
+	out := Message{}
+	if e := xml.Unmarshal([]byte(in), &out); e != nil {
+		t.Fatal(e)
+	}
+
+	data, e := xml.Marshal(out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	again := Message{}
+	if e := xml.Unmarshal(data, &again); e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, out, again)
+}
+
+func xsstring(s string) *string {
+	return &s
+}
@@ -0,0 +1,192 @@
+package simple10
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+	"strconv"
+)
+
+type Color string
+
+const (
+	ColorRed       Color = "red"
+	ColorDarkGreen Color = "dark-green"
+	ColorLightBlue Color = "light blue"
+)
+
+func (t Color) IsValid() bool {
+	switch t {
+	case ColorRed, ColorDarkGreen, ColorLightBlue:
+		return true
+	}
+	return false
+}
+
+func (t Color) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Color: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Color) UnmarshalText(text []byte) error {
+	v := Color(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Item struct {
+	XMLName  xml.Name  `xml:"urn:caementarii:simple item"`
+	Priority *Priority `xml:"priority,attr,omitempty"`
+	Status   *Status   `xml:"status,attr,omitempty"`
+	Color    Color     `xml:"color"`
+	Primary  *Primary  `xml:"primary"`
+	Shade    *Color    `xml:"shade"`
+	Size     Size      `xml:"urn:caementarii:simple size"`
+}
+
+type Primary string
+
+const (
+	PrimaryRed Primary = "red"
+)
+
+func (t Primary) IsValid() bool {
+	switch t {
+	case PrimaryRed:
+		return true
+	}
+	return false
+}
+
+func (t Primary) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Primary: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Primary) UnmarshalText(text []byte) error {
+	v := Primary(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Priority int
+
+const (
+	Priority1      Priority = 1
+	Priority2      Priority = 2
+	PriorityMinus1 Priority = -1
+)
+
+func (t Priority) IsValid() bool {
+	switch t {
+	case Priority1, Priority2, PriorityMinus1:
+		return true
+	}
+	return false
+}
+
+func (t Priority) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Priority: %v is not a valid value", t)
+	}
+	return nil
+}
+
+func (t *Priority) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	x, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Priority: %q is not a valid value: %w", text, err)
+	}
+	v := Priority(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+var nsSizeQName = xml.Name{Space: "urn:caementarii:simple", Local: "size"}
+
+type Size SizeType
+
+func (t *Size) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*SizeType)(t), &start)
+}
+
+func (t Size) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsSizeQName
+	return e.EncodeElement(SizeType(t), start)
+}
+
+type SizeType string
+
+const (
+	SizeTypeSmall SizeType = "small"
+	SizeTypeLarge SizeType = "large"
+)
+
+func (t SizeType) IsValid() bool {
+	switch t {
+	case SizeTypeSmall, SizeTypeLarge:
+		return true
+	}
+	return false
+}
+
+func (t SizeType) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("SizeType: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *SizeType) UnmarshalText(text []byte) error {
+	v := SizeType(xs.Collapse(string(text)))
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Status string
+
+const (
+	StatusNew  Status = "new"
+	StatusDone Status = "done"
+)
+
+func (t Status) IsValid() bool {
+	switch t {
+	case StatusNew, StatusDone:
+		return true
+	}
+	return false
+}
+
+func (t Status) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Status: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Status) UnmarshalText(text []byte) error {
+	v := Status(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
@@ -0,0 +1,84 @@
+package simple10
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple10(t *testing.T) {
+	data, err := os.ReadFile("simple10.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple10",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple10.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestIsValid(t *testing.T) {
+	assert.True(t, ColorDarkGreen.IsValid())
+	assert.False(t, Color("green").IsValid())
+	assert.True(t, PriorityMinus1.IsValid())
+	assert.False(t, Priority(3).IsValid())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<item xmlns="urn:caementarii:simple" priority="2" status="done"><color>light blue</color><primary>red</primary><size> large </size></item>`
+	out := Item{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	priority := Priority2
+	status := StatusDone
+	primary := PrimaryRed
+	assert.Equal(t, &priority, out.Priority)
+	assert.Equal(t, &status, out.Status)
+	assert.Equal(t, ColorLightBlue, out.Color)
+	assert.Equal(t, &primary, out.Primary)
+	assert.Equal(t, Size(SizeTypeLarge), out.Size)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, in := range []string{
+		`<item xmlns="urn:caementarii:simple"><color>green</color><size>small</size></item>`,
+		`<item xmlns="urn:caementarii:simple"><color>red</color><primary>light blue</primary><size>small</size></item>`,
+		`<item xmlns="urn:caementarii:simple" priority="3"><color>red</color><size>small</size></item>`,
+		`<item xmlns="urn:caementarii:simple" status="open"><color>red</color><size>small</size></item>`,
+		`<item xmlns="urn:caementarii:simple"><color>red</color><size>medium</size></item>`,
+	} {
+		out := Item{}
+		assert.Error(t, xml.Unmarshal([]byte(in), &out), in)
+	}
+}
+
+func TestMarshaler(t *testing.T) {
+	data, e := xml.Marshal(Item{Color: ColorRed, Size: Size(SizeTypeSmall)})
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, `<item xmlns="urn:caementarii:simple"><color>red</color><size xmlns="urn:caementarii:simple">small</size></item>`, string(data))
+}
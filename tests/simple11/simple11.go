@@ -0,0 +1,244 @@
+package simple11
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type Amount float64
+
+func (t Amount) Validate() error {
+	if t <= 0 {
+		return fmt.Errorf("Amount: %v must be greater than 0", t)
+	}
+	if t >= 1000000 {
+		return fmt.Errorf("Amount: %v must be less than 1000000", t)
+	}
+	if n := len(strings.TrimLeft(strings.Replace(strconv.FormatFloat(math.Abs(float64(t)), 'f', -1, 64), ".", "", 1), "0")); n > 8 {
+		return fmt.Errorf("Amount: %v has %d digits, but at most 8 allowed", t, n)
+	}
+	if s := strconv.FormatFloat(float64(t), 'f', -1, 64); strings.Contains(s, ".") && len(s)-strings.Index(s, ".")-1 > 2 {
+		return fmt.Errorf("Amount: %v has more than 2 fraction digits", t)
+	}
+	return nil
+}
+
+func (t *Amount) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	x, err := xs.ParseDouble(s)
+	if err != nil {
+		return fmt.Errorf("Amount: %q is not a valid value: %w", text, err)
+	}
+	v := Amount(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+var patternCode = regexp.MustCompile(`^(?:[A-Z]{2}[\p{Nd}]{3})$`)
+
+type Code string
+
+func (t Code) Validate() error {
+	if !patternCode.MatchString(string(t)) {
+		return fmt.Errorf("Code: %q does not match the pattern [A-Z]{2}\\d{3}", string(t))
+	}
+	if n := utf8.RuneCountInString(string(t)); n != 5 {
+		return fmt.Errorf("Code: length of %q is %d, but it must be 5", string(t), n)
+	}
+	return nil
+}
+
+func (t *Code) UnmarshalText(text []byte) error {
+	v := Code(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+var patternEuCode = regexp.MustCompile(`^(?:[A-Z]{2}[\p{Nd}]{3})$`)
+var patternEuCode2 = regexp.MustCompile(`^(?:(EU[^\n\r]*)|(CH[^\n\r]*))$`)
+
+type EuCode string
+
+func (t EuCode) Validate() error {
+	if !patternEuCode.MatchString(string(t)) {
+		return fmt.Errorf("EuCode: %q does not match the pattern [A-Z]{2}\\d{3}", string(t))
+	}
+	if !patternEuCode2.MatchString(string(t)) {
+		return fmt.Errorf("EuCode: %q does not match the pattern (EU.*)|(CH.*)", string(t))
+	}
+	if n := utf8.RuneCountInString(string(t)); n != 5 {
+		return fmt.Errorf("EuCode: length of %q is %d, but it must be 5", string(t), n)
+	}
+	return nil
+}
+
+func (t *EuCode) UnmarshalText(text []byte) error {
+	v := EuCode(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+var patternFlag = regexp.MustCompile(`^(?:true|false)$`)
+
+type Flag bool
+
+func (t Flag) Validate() error {
+	return nil
+}
+
+func (t *Flag) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	if !patternFlag.MatchString(s) {
+		return fmt.Errorf("Flag: %q does not match the pattern true|false", text)
+	}
+	if !(s == "true" || s == "1" || s == "false" || s == "0") {
+		return fmt.Errorf("Flag: %q is not a valid value", text)
+	}
+	v := Flag(s == "true" || s == "1")
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Label string
+
+func (t Label) Validate() error {
+	if n := utf8.RuneCountInString(string(t)); n < 1 {
+		return fmt.Errorf("Label: length of %q is %d, but it must be at least 1", string(t), n)
+	}
+	if n := utf8.RuneCountInString(string(t)); n > 10 {
+		return fmt.Errorf("Label: length of %q is %d, but it must be at most 10", string(t), n)
+	}
+	return nil
+}
+
+func (t *Label) UnmarshalText(text []byte) error {
+	v := Label(xs.Collapse(string(text)))
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+var patternNcName = regexp.MustCompile(`^(?:[A-Z_a-z\x{C0}-\x{D6}\x{D8}-\x{F6}\x{F8}-\x{2FF}\x{370}-\x{37D}\x{37F}-\x{1FFF}\x{200C}-\x{200D}\x{2070}-\x{218F}\x{2C00}-\x{2FEF}\x{3001}-\x{D7FF}\x{F900}-\x{FDCF}\x{FDF0}-\x{FFFD}\x{10000}-\x{EFFFF}][\--.0-9A-Z_a-z\x{B7}\x{C0}-\x{D6}\x{D8}-\x{F6}\x{F8}-\x{37D}\x{37F}-\x{1FFF}\x{200C}-\x{200D}\x{203F}-\x{2040}\x{2070}-\x{218F}\x{2C00}-\x{2FEF}\x{3001}-\x{D7FF}\x{F900}-\x{FDCF}\x{FDF0}-\x{FFFD}\x{10000}-\x{EFFFF}]*)$`)
+
+type NcName string
+
+func (t NcName) Validate() error {
+	if !patternNcName.MatchString(string(t)) {
+		return fmt.Errorf("NcName: %q does not match the pattern [\\i-[:]][\\c-[:]]*", string(t))
+	}
+	return nil
+}
+
+func (t *NcName) UnmarshalText(text []byte) error {
+	v := NcName(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Order struct {
+	XMLName  xml.Name `xml:"urn:caementarii:simple order"`
+	Discount *Percent `xml:"discount,attr,omitempty"`
+	Urgent   *Flag    `xml:"urgent,attr,omitempty"`
+	Code     Code     `xml:"code"`
+	EuCode   *EuCode  `xml:"euCode"`
+	Label    Label    `xml:"label"`
+	Name     *NcName  `xml:"name"`
+	Amount   Amount   `xml:"amount"`
+	Year     *Year    `xml:"year"`
+	Remark   *Remark  `xml:"remark"`
+}
+
+type Percent int
+
+func (t Percent) Validate() error {
+	if t < 0 {
+		return fmt.Errorf("Percent: %v must be at least 0", t)
+	}
+	if t > 100 {
+		return fmt.Errorf("Percent: %v must be at most 100", t)
+	}
+	return nil
+}
+
+func (t *Percent) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	x, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Percent: %q is not a valid value: %w", text, err)
+	}
+	v := Percent(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Remark string
+
+func (t Remark) Validate() error {
+	if n := utf8.RuneCountInString(string(t)); n > 20 {
+		return fmt.Errorf("Remark: length of %q is %d, but it must be at most 20", string(t), n)
+	}
+	return nil
+}
+
+func (t *Remark) UnmarshalText(text []byte) error {
+	v := Remark(xs.Replace(string(text)))
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+var patternYear = regexp.MustCompile(`^(?:[\p{Nd}]{4})$`)
+
+type Year int
+
+func (t Year) Validate() error {
+	if n := len(strings.TrimPrefix(strconv.FormatInt(int64(t), 10), "-")); n > 4 {
+		return fmt.Errorf("Year: %v has %d digits, but at most 4 allowed", t, n)
+	}
+	return nil
+}
+
+func (t *Year) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	if !patternYear.MatchString(s) {
+		return fmt.Errorf("Year: %q does not match the pattern \\d{4}", text)
+	}
+	x, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Year: %q is not a valid value: %w", text, err)
+	}
+	v := Year(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
@@ -0,0 +1,136 @@
+package simple11
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple11(t *testing.T) {
+	data, err := os.ReadFile("simple11.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple11",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple11.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Code("AB123").Validate())
+	assert.Error(t, Code("ab123").Validate())
+	assert.Error(t, Code("AB1234").Validate())
+
+	assert.NoError(t, EuCode("EU123").Validate())
+	assert.NoError(t, EuCode("CH123").Validate())
+	assert.Error(t, EuCode("DE123").Validate())
+	assert.Error(t, EuCode("EU12").Validate())
+
+	assert.NoError(t, Label("x").Validate())
+	assert.Error(t, Label("").Validate())
+	assert.Error(t, Label("abcdefghijk").Validate())
+
+	assert.NoError(t, NcName("a-b.c").Validate())
+	assert.Error(t, NcName("a:b").Validate())
+	assert.Error(t, NcName("1a").Validate())
+
+	assert.NoError(t, Percent(0).Validate())
+	assert.NoError(t, Percent(100).Validate())
+	assert.Error(t, Percent(-1).Validate())
+	assert.Error(t, Percent(101).Validate())
+
+	assert.NoError(t, Amount(123456.78).Validate())
+	assert.Error(t, Amount(0).Validate())
+	assert.Error(t, Amount(1000000).Validate())
+	assert.Error(t, Amount(1.234).Validate())
+
+	assert.NoError(t, Year(2024).Validate())
+	assert.Error(t, Year(20245).Validate())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<order xmlns="urn:caementarii:simple" discount="15"><code>AB123</code><label>  big   box </label><amount>10.5</amount><year>2024</year></order>`
+	out := Order{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	discount := Percent(15)
+	year := Year(2024)
+	assert.Equal(t, &discount, out.Discount)
+	assert.Equal(t, Code("AB123"), out.Code)
+	assert.Equal(t, Label("big box"), out.Label)
+	assert.Equal(t, Amount(10.5), out.Amount)
+	assert.Equal(t, &year, out.Year)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, in := range []string{
+		`<order xmlns="urn:caementarii:simple"><code>AB12</code><label>x</label><amount>1</amount></order>`,
+		`<order xmlns="urn:caementarii:simple" discount="150"><code>AB123</code><label>x</label><amount>1</amount></order>`,
+		`<order xmlns="urn:caementarii:simple"><code>AB123</code><label>x</label><amount>1.001</amount></order>`,
+		`<order xmlns="urn:caementarii:simple"><code>AB123</code><label>x</label><amount>1</amount><year>+024</year></order>`,
+	} {
+		out := Order{}
+		assert.Error(t, xml.Unmarshal([]byte(in), &out), in)
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+	// Leading and trailing whitespace is removed, and patterns are matched against the rest.
+	var year Year
+	assert.NoError(t, year.UnmarshalText([]byte(" 2024\n")))
+	assert.Equal(t, Year(2024), year)
+	var amount Amount
+	assert.NoError(t, amount.UnmarshalText([]byte(" 12.5 ")))
+	assert.Equal(t, Amount(12.5), amount)
+	var urgent Flag
+	assert.NoError(t, urgent.UnmarshalText([]byte(" true ")))
+	assert.Equal(t, Flag(true), urgent)
+
+	// Values are parsed entirely in their lexical space.
+	for _, in := range []string{"12abc", "0x1F", "1_000", "1e3", "50 60", ""} {
+		var percent Percent
+		assert.Error(t, percent.UnmarshalText([]byte(in)), in)
+	}
+	for _, in := range []string{"0x1p4", "1_000", "Infinity", "1.5 2"} {
+		assert.Error(t, amount.UnmarshalText([]byte(in)), in)
+	}
+	for _, in := range []string{"T", "TRUE", "yes", "1 0"} {
+		assert.Error(t, urgent.UnmarshalText([]byte(in)), in)
+	}
+	// 1 is a valid boolean, but it doesn't match the pattern.
+	assert.Error(t, urgent.UnmarshalText([]byte("1")))
+
+	// Only XML whitespace is replaced, collapsed or trimmed, so NBSP is kept in a value.
+	var label Label
+	assert.NoError(t, label.UnmarshalText([]byte("\u00a0big \n box\u00a0")))
+	assert.Equal(t, Label("\u00a0big box\u00a0"), label)
+	var remark Remark
+	assert.NoError(t, remark.UnmarshalText([]byte("\tfirst\r\nsecond\u00a0 ")))
+	assert.Equal(t, Remark(" first  second\u00a0 "), remark)
+	assert.Error(t, year.UnmarshalText([]byte("\u00a02024")))
+	assert.Error(t, amount.UnmarshalText([]byte("12.5\u00a0")))
+}
@@ -0,0 +1,243 @@
+package simple12
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type Badgedinfo struct {
+	Personinfo
+	Badge string `xml:"badge,attr"`
+}
+
+var nsContractorQName = xml.Name{Space: "urn:caementarii:simple", Local: "contractor"}
+
+type Contractor Contractorinfo
+
+func (t *Contractor) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*Contractorinfo)(t), &start)
+}
+
+func (t Contractor) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsContractorQName
+	return e.EncodeElement(Contractorinfo(t), start)
+}
+
+type Contractorinfo struct {
+	ID        string `xml:"id,attr"`
+	Firstname string `xml:"firstname"`
+	Lastname  string `xml:"lastname"`
+}
+
+type Employeeinfo struct {
+	Personinfo
+	Grade      *int   `xml:"grade,attr,omitempty"`
+	Department string `xml:"department"`
+}
+
+type EmployeeinfoValue interface {
+	isEmployeeinfo()
+}
+
+func (Employeeinfo) isEmployeeinfo() {}
+
+func (Managerinfo) isEmployeeinfo() {}
+
+type AnyEmployeeinfo struct {
+	Value EmployeeinfoValue
+}
+
+func (t *AnyEmployeeinfo) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	name, ok, err := xsiType(&start)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		name = xml.Name{Space: "urn:caementarii:simple", Local: "employeeinfo"}
+	}
+	switch name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "employeeinfo"}:
+		var v Employeeinfo
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "managerinfo"}:
+		var v Managerinfo
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	}
+	return fmt.Errorf("xsi:type {%s}%s is not allowed for employeeinfo", name.Space, name.Local)
+}
+
+func (t AnyEmployeeinfo) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch v := t.Value.(type) {
+	case nil:
+		return nil
+	case Employeeinfo, *Employeeinfo:
+		return e.EncodeElement(v, start)
+	case Managerinfo, *Managerinfo:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "managerinfo", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	}
+	return fmt.Errorf("%T is not allowed for employeeinfo", t.Value)
+}
+
+var nsManagerQName = xml.Name{Space: "urn:caementarii:simple", Local: "manager"}
+
+type Manager Managerinfo
+
+func (t *Manager) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*Managerinfo)(t), &start)
+}
+
+func (t Manager) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsManagerQName
+	return e.EncodeElement(Managerinfo(t), start)
+}
+
+type Managerinfo struct {
+	Employeeinfo
+	Reports int `xml:"reports"`
+}
+
+type Personinfo struct {
+	ID        string  `xml:"id,attr"`
+	Note      *string `xml:"note,attr,omitempty"`
+	Firstname string  `xml:"firstname"`
+	Lastname  string  `xml:"lastname"`
+}
+
+type PersoninfoValue interface {
+	isPersoninfo()
+}
+
+func (Personinfo) isPersoninfo() {}
+
+func (Badgedinfo) isPersoninfo() {}
+
+func (Contractorinfo) isPersoninfo() {}
+
+func (Employeeinfo) isPersoninfo() {}
+
+func (Managerinfo) isPersoninfo() {}
+
+type AnyPersoninfo struct {
+	Value PersoninfoValue
+}
+
+func (t *AnyPersoninfo) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	name, ok, err := xsiType(&start)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		name = xml.Name{Space: "urn:caementarii:simple", Local: "personinfo"}
+	}
+	switch name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "personinfo"}:
+		var v Personinfo
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "badgedinfo"}:
+		var v Badgedinfo
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "contractorinfo"}:
+		var v Contractorinfo
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "employeeinfo"}:
+		var v Employeeinfo
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "managerinfo"}:
+		var v Managerinfo
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	}
+	return fmt.Errorf("xsi:type {%s}%s is not allowed for personinfo", name.Space, name.Local)
+}
+
+func (t AnyPersoninfo) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch v := t.Value.(type) {
+	case nil:
+		return nil
+	case Personinfo, *Personinfo:
+		return e.EncodeElement(v, start)
+	case Badgedinfo, *Badgedinfo:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "badgedinfo", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	case Contractorinfo, *Contractorinfo:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "contractorinfo", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	case Employeeinfo, *Employeeinfo:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "employeeinfo", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	case Managerinfo, *Managerinfo:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "managerinfo", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	}
+	return fmt.Errorf("%T is not allowed for personinfo", t.Value)
+}
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// xsiType returns the type name of the xsi:type attribute of an element and removes the attribute.
+//
+// The decoder of encoding/xml doesn't report namespaces declared by ancestors of the element, so the
+// prefix of the name is resolved only by declarations of the element itself. Decode by xs.NewDecoder,
+// which copies them from the ancestors: with xml.NewDecoder or xml.Unmarshal a prefix declared by an
+// ancestor is an error "prefix ... is not declared", and a name without a prefix has no namespace
+// unless the element declares a default one.
+func xsiType(start *xml.StartElement) (xml.Name, bool, error) {
+	for i, attr := range start.Attr {
+		if attr.Name.Space != xsiNamespace || attr.Name.Local != "type" {
+			continue
+		}
+		start.Attr = append(start.Attr[:i:i], start.Attr[i+1:]...)
+		prefix, local, found := strings.Cut(strings.TrimSpace(attr.Value), ":")
+		if !found {
+			prefix, local = "", prefix
+		}
+		for _, ns := range start.Attr {
+			if (prefix != "" && ns.Name.Space == "xmlns" && ns.Name.Local == prefix) || (prefix == "" && ns.Name.Space == "" && ns.Name.Local == "xmlns") {
+				return xml.Name{Space: ns.Value, Local: local}, true, nil
+			}
+		}
+		if prefix != "" {
+			return xml.Name{}, true, fmt.Errorf("xsi:type %q: prefix %s is not declared", attr.Value, prefix)
+		}
+		return xml.Name{Local: local}, true, nil
+	}
+	return xml.Name{}, false, nil
+}
+
+func xsiTypeAttrs(element string, space string, local string, prefix string) []xml.Attr {
+	attr := xml.Attr{Name: xml.Name{Space: xsiNamespace, Local: "type"}, Value: local}
+	if space == "" || space == element {
+		return []xml.Attr{attr}
+	}
+	attr.Value = prefix + ":" + local
+	return []xml.Attr{{Name: xml.Name{Local: "xmlns:" + prefix}, Value: space}, attr}
+}
@@ -0,0 +1,102 @@
+package simple12
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple12(t *testing.T) {
+	data, err := os.ReadFile("simple12.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple12",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple12.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func fullName(p *Personinfo) string {
+	return p.Firstname + " " + p.Lastname
+}
+
+func TestEmbeddedBase(t *testing.T) {
+	m := Manager{}
+	m.ID = "m1"
+	m.Firstname = "first"
+	m.Lastname = "last"
+	m.Department = "sales"
+	m.Reports = 3
+
+	assert.Equal(t, "first last", fullName(&m.Personinfo))
+}
+
+func TestMarshaler(t *testing.T) {
+	grade := 7
+	m := Manager{}
+	m.ID = "m1"
+	m.Firstname = "first"
+	m.Lastname = "last"
+	m.Grade = &grade
+	m.Department = "sales"
+	m.Reports = 3
+
+	data, e := xml.Marshal(m)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, `<manager xmlns="urn:caementarii:simple" id="m1" grade="7"><firstname>first</firstname><lastname>last</lastname><department>sales</department><reports>3</reports></manager>`, string(data))
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<manager xmlns="urn:caementarii:simple" id="m1" note="n" grade="7"><firstname>first</firstname><lastname>last</lastname><department>sales</department><reports>3</reports></manager>`
+	out := Manager{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	note := "n"
+	grade := 7
+	expected := Manager{}
+	expected.ID = "m1"
+	expected.Note = &note
+	expected.Firstname = "first"
+	expected.Lastname = "last"
+	expected.Grade = &grade
+	expected.Department = "sales"
+	expected.Reports = 3
+	assert.Equal(t, expected, out)
+}
+
+func TestProhibitedAttribute(t *testing.T) {
+	in := `<contractor xmlns="urn:caementarii:simple" id="c1" note="n"><firstname>first</firstname><lastname>last</lastname></contractor>`
+	out := Contractor{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, Contractor{ID: "c1", Firstname: "first", Lastname: "last"}, out)
+}
@@ -0,0 +1,255 @@
+package simple13
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type Animal struct {
+	Name string `xml:"name"`
+}
+
+type AnimalValue interface {
+	isAnimal()
+}
+
+func (Animal) isAnimal() {}
+
+func (Dog) isAnimal() {}
+
+func (Puppy) isAnimal() {}
+
+type AnyAnimal struct {
+	Value AnimalValue
+}
+
+func (t *AnyAnimal) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	name, ok, err := xsiType(&start)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		name = xml.Name{Space: "urn:caementarii:simple", Local: "animal"}
+	}
+	switch name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "animal"}:
+		var v Animal
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "dog"}:
+		var v Dog
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "puppy"}:
+		var v Puppy
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	}
+	return fmt.Errorf("xsi:type {%s}%s is not allowed for animal", name.Space, name.Local)
+}
+
+func (t AnyAnimal) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch v := t.Value.(type) {
+	case nil:
+		return nil
+	case Animal, *Animal:
+		return e.EncodeElement(v, start)
+	case Dog, *Dog:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "dog", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	case Puppy, *Puppy:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "puppy", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	}
+	return fmt.Errorf("%T is not allowed for animal", t.Value)
+}
+
+type Bike struct {
+	Wheels int `xml:"wheels"`
+}
+
+type Car struct {
+	Vehicle
+	Model string `xml:"model"`
+}
+
+type Dog struct {
+	Animal
+	Breed string `xml:"breed"`
+}
+
+type DogValue interface {
+	isDog()
+}
+
+func (Dog) isDog() {}
+
+func (Puppy) isDog() {}
+
+type AnyDog struct {
+	Value DogValue
+}
+
+func (t *AnyDog) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	name, ok, err := xsiType(&start)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		name = xml.Name{Space: "urn:caementarii:simple", Local: "dog"}
+	}
+	switch name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "dog"}:
+		var v Dog
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "puppy"}:
+		var v Puppy
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	}
+	return fmt.Errorf("xsi:type {%s}%s is not allowed for dog", name.Space, name.Local)
+}
+
+func (t AnyDog) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch v := t.Value.(type) {
+	case nil:
+		return nil
+	case Dog, *Dog:
+		return e.EncodeElement(v, start)
+	case Puppy, *Puppy:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "puppy", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	}
+	return fmt.Errorf("%T is not allowed for dog", t.Value)
+}
+
+var nsPetQName = xml.Name{Space: "urn:caementarii:simple", Local: "pet"}
+
+type Pet AnyAnimal
+
+func (t *Pet) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*AnyAnimal)(t), &start)
+}
+
+func (t Pet) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsPetQName
+	return e.EncodeElement(AnyAnimal(t), start)
+}
+
+type Puppy struct {
+	Dog
+	Age *int `xml:"age,attr,omitempty"`
+}
+
+type Vehicle struct {
+	Wheels int `xml:"wheels"`
+}
+
+type VehicleValue interface {
+	isVehicle()
+}
+
+func (Vehicle) isVehicle() {}
+
+func (Bike) isVehicle() {}
+
+func (Car) isVehicle() {}
+
+type AnyVehicle struct {
+	Value VehicleValue
+}
+
+func (t *AnyVehicle) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	name, ok, err := xsiType(&start)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("xsi:type is required for the abstract type vehicle")
+	}
+	switch name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "car"}:
+		var v Car
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	}
+	return fmt.Errorf("xsi:type {%s}%s is not allowed for vehicle", name.Space, name.Local)
+}
+
+func (t AnyVehicle) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch v := t.Value.(type) {
+	case nil:
+		return nil
+	case Car, *Car:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "car", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	}
+	return fmt.Errorf("%T is not allowed for vehicle", t.Value)
+}
+
+type Zoo struct {
+	XMLName xml.Name    `xml:"urn:caementarii:simple zoo"`
+	Animal  []AnyAnimal `xml:"animal"`
+	Vehicle *AnyVehicle `xml:"vehicle"`
+}
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// xsiType returns the type name of the xsi:type attribute of an element and removes the attribute.
+//
+// The decoder of encoding/xml doesn't report namespaces declared by ancestors of the element, so the
+// prefix of the name is resolved only by declarations of the element itself. Decode by xs.NewDecoder,
+// which copies them from the ancestors: with xml.NewDecoder or xml.Unmarshal a prefix declared by an
+// ancestor is an error "prefix ... is not declared", and a name without a prefix has no namespace
+// unless the element declares a default one.
+func xsiType(start *xml.StartElement) (xml.Name, bool, error) {
+	for i, attr := range start.Attr {
+		if attr.Name.Space != xsiNamespace || attr.Name.Local != "type" {
+			continue
+		}
+		start.Attr = append(start.Attr[:i:i], start.Attr[i+1:]...)
+		prefix, local, found := strings.Cut(strings.TrimSpace(attr.Value), ":")
+		if !found {
+			prefix, local = "", prefix
+		}
+		for _, ns := range start.Attr {
+			if (prefix != "" && ns.Name.Space == "xmlns" && ns.Name.Local == prefix) || (prefix == "" && ns.Name.Space == "" && ns.Name.Local == "xmlns") {
+				return xml.Name{Space: ns.Value, Local: local}, true, nil
+			}
+		}
+		if prefix != "" {
+			return xml.Name{}, true, fmt.Errorf("xsi:type %q: prefix %s is not declared", attr.Value, prefix)
+		}
+		return xml.Name{Local: local}, true, nil
+	}
+	return xml.Name{}, false, nil
+}
+
+func xsiTypeAttrs(element string, space string, local string, prefix string) []xml.Attr {
+	attr := xml.Attr{Name: xml.Name{Space: xsiNamespace, Local: "type"}, Value: local}
+	if space == "" || space == element {
+		return []xml.Attr{attr}
+	}
+	attr.Value = prefix + ":" + local
+	return []xml.Attr{{Name: xml.Name{Local: "xmlns:" + prefix}, Value: space}, attr}
+}
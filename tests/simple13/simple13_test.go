@@ -0,0 +1,144 @@
+package simple13
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xs"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSimple13(t *testing.T) {
+	data, err := os.ReadFile("simple13.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple13",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple13.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<zoo xmlns="urn:caementarii:simple" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:tns="urn:caementarii:simple">` +
+		`<animal><name>generic</name></animal>` +
+		`<animal xsi:type="tns:dog"><name>rex</name><breed>collie</breed></animal>` +
+		`<animal xmlns:p="urn:caementarii:simple" xsi:type="p:puppy" age="1"><name>bit</name><breed>pug</breed></animal>` +
+		`<vehicle xsi:type="tns:car"><wheels>4</wheels><model>T</model></vehicle>` +
+		`</zoo>`
+	out := Zoo{}
+
+	// The tns prefix is declared by the root element, which is visible to xsi:type through xs.NewDecoder only.
+	assert.Error(t, xml.Unmarshal([]byte(in), &Zoo{}))
+	e := xs.NewDecoder(strings.NewReader(in)).Decode(&out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	age := 1
+	dog := Dog{Breed: "collie"}
+	dog.Name = "rex"
+	puppy := Puppy{Age: &age}
+	puppy.Name = "bit"
+	puppy.Breed = "pug"
+	car := Car{Model: "T"}
+	car.Wheels = 4
+
+	assert.Equal(t, []AnyAnimal{{Value: Animal{Name: "generic"}}, {Value: dog}, {Value: puppy}}, out.Animal)
+	assert.Equal(t, &AnyVehicle{Value: car}, out.Vehicle)
+}
+
+func TestUnmarshalAncestorPrefix(t *testing.T) {
+	in := `<zoo xmlns="urn:caementarii:simple" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:tns="urn:caementarii:simple">` +
+		`<animal xsi:type="tns:dog"><name>rex</name><breed>collie</breed></animal>` +
+		`</zoo>`
+
+	// The decoder of encoding/xml doesn't let xsi:type see the tns prefix of the root element.
+	e := xml.NewDecoder(strings.NewReader(in)).Decode(&Zoo{})
+	if assert.Error(t, e) {
+		assert.Equal(t, `xsi:type "tns:dog": prefix tns is not declared`, e.Error())
+	}
+
+	out := Zoo{}
+	assert.NoError(t, xs.NewDecoder(strings.NewReader(in)).Decode(&out))
+	dog := Dog{Breed: "collie"}
+	dog.Name = "rex"
+	assert.Equal(t, []AnyAnimal{{Value: dog}}, out.Animal)
+}
+
+func TestUnmarshalerRejects(t *testing.T) {
+	for _, in := range []string{
+		// An abstract type requires xsi:type.
+		`<zoo xmlns="urn:caementarii:simple"><animal><name>a</name></animal><vehicle><wheels>4</wheels></vehicle></zoo>`,
+		// The restriction is blocked by the vehicle type.
+		`<zoo xmlns="urn:caementarii:simple" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><animal><name>a</name></animal><vehicle xsi:type="bike"><wheels>2</wheels></vehicle></zoo>`,
+		// A type which isn't derived from the declared type.
+		`<pet xmlns="urn:caementarii:simple" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="car"><name>a</name></pet>`,
+		// A prefix which isn't declared.
+		`<pet xmlns="urn:caementarii:simple" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="p:dog"><name>a</name></pet>`,
+		// A QName without a prefix has no namespace without a default one.
+		`<p:pet xmlns:p="urn:caementarii:simple" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="dog"><p:name>a</p:name></p:pet>`,
+	} {
+		var out interface{} = &Zoo{}
+		if strings.Contains(in, "pet xmlns") {
+			out = &Pet{}
+		}
+		assert.Error(t, xs.NewDecoder(strings.NewReader(in)).Decode(out), in)
+	}
+}
+
+func TestMarshaler(t *testing.T) {
+	dog := Dog{Breed: "collie"}
+	dog.Name = "rex"
+
+	data, e := xml.Marshal(Pet{Value: dog})
+	if e != nil {
+		t.Fatal(e)
+	}
+	// The type is in the default namespace of the element, and the encoder declares the xsi prefix.
+	assert.Equal(t, `<pet xmlns="urn:caementarii:simple" xmlns:_XMLSchema-instance="http://www.w3.org/2001/XMLSchema-instance" _XMLSchema-instance:type="dog"><name>rex</name><breed>collie</breed></pet>`, string(data))
+
+	out := Pet{}
+	e = xml.Unmarshal(data, &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, Pet{Value: dog}, out)
+
+	_, e = xml.Marshal(Zoo{Vehicle: &AnyVehicle{Value: Vehicle{Wheels: 4}}})
+	assert.Error(t, e)
+
+	// An unqualified element gets a prefix of the type's namespace, which doesn't shadow prefixes of the document.
+	car := Car{Model: "T"}
+	car.Wheels = 4
+	zoo := Zoo{Animal: []AnyAnimal{{Value: dog}}, Vehicle: &AnyVehicle{Value: car}}
+	data, e = xml.Marshal(zoo)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.True(t, strings.Contains(string(data), `<animal xmlns:ns1iafv9g="urn:caementarii:simple" xmlns:_XMLSchema-instance="http://www.w3.org/2001/XMLSchema-instance" _XMLSchema-instance:type="ns1iafv9g:dog">`), string(data))
+
+	out2 := Zoo{}
+	assert.NoError(t, xml.Unmarshal(data, &out2))
+	assert.Equal(t, zoo.Animal, out2.Animal)
+	assert.Equal(t, zoo.Vehicle, out2.Vehicle)
+}
@@ -0,0 +1,368 @@
+package simple14
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+var nsBookQName = xml.Name{Space: "urn:caementarii:simple", Local: "book"}
+
+type Book string
+
+func (t *Book) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*string)(t), &start)
+}
+
+func (t Book) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsBookQName
+	return e.EncodeElement(string(t), start)
+}
+
+type BookElement interface {
+	isBookElement()
+}
+
+func (Book) isBookElement() {}
+
+func (Novel) isBookElement() {}
+
+type bookElements []BookElement
+
+func (t *bookElements) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "book"}:
+		var v Book
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		*t = append(*t, v)
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "novel"}:
+		var v Novel
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		*t = append(*t, v)
+		return nil
+	}
+	return fmt.Errorf("element {%s}%s is not allowed in place of book", start.Name.Space, start.Name.Local)
+}
+
+var nsCircleQName = xml.Name{Space: "urn:caementarii:simple", Local: "circle"}
+
+type Circle CircleType
+
+func (t *Circle) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*CircleType)(t), &start)
+}
+
+func (t Circle) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsCircleQName
+	return e.EncodeElement(CircleType(t), start)
+}
+
+type CircleType struct {
+	ShapeType
+	Radius int `xml:"radius"`
+}
+
+type Dot struct {
+	XMLName xml.Name `xml:"urn:caementarii:simple dot"`
+	Name    string   `xml:"name"`
+}
+
+type Drawing struct {
+	XMLName     xml.Name           `xml:"urn:caementarii:simple drawing"`
+	Shape       []ShapeElement     `xml:"urn:caementarii:simple shape"`
+	Publication PublicationElement `xml:"urn:caementarii:simple publication"`
+}
+
+func (t *Drawing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type XMLAlias Drawing
+	var elmShape shapeElements
+	var elmPublication publicationElements
+	v := struct {
+		*XMLAlias
+		Shape       *shapeElements       `xml:"urn:caementarii:simple shape"`
+		Circle      *shapeElements       `xml:"urn:caementarii:simple circle"`
+		Square      *shapeElements       `xml:"urn:caementarii:simple square"`
+		Publication *publicationElements `xml:"urn:caementarii:simple publication"`
+		Book        *publicationElements `xml:"urn:caementarii:simple book"`
+		Novel       *publicationElements `xml:"urn:caementarii:simple novel"`
+	}{
+		XMLAlias:    (*XMLAlias)(t),
+		Shape:       &elmShape,
+		Circle:      &elmShape,
+		Square:      &elmShape,
+		Publication: &elmPublication,
+		Book:        &elmPublication,
+		Novel:       &elmPublication,
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	t.XMLName = start.Name
+	t.Shape = elmShape
+	if len(elmPublication) > 1 {
+		return fmt.Errorf("element publication occurs %d times in Drawing", len(elmPublication))
+	}
+	if len(elmPublication) == 1 {
+		t.Publication = elmPublication[0]
+	}
+	return nil
+}
+
+type Frame struct {
+	XMLName xml.Name     `xml:"urn:caementarii:simple frame"`
+	Shape   ShapeElement `xml:"urn:caementarii:simple shape"`
+	Title   *string      `xml:"title"`
+}
+
+func (t *Frame) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type XMLAlias Frame
+	var elmShape shapeElements
+	v := struct {
+		*XMLAlias
+		Shape  *shapeElements `xml:"urn:caementarii:simple shape"`
+		Circle *shapeElements `xml:"urn:caementarii:simple circle"`
+		Square *shapeElements `xml:"urn:caementarii:simple square"`
+		Title  []string       `xml:"title"`
+	}{
+		XMLAlias: (*XMLAlias)(t),
+		Shape:    &elmShape,
+		Circle:   &elmShape,
+		Square:   &elmShape,
+	}
+	if err := d.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+	t.XMLName = start.Name
+	if len(elmShape) < 1 {
+		return fmt.Errorf("element shape is missing in Frame")
+	}
+	if len(elmShape) > 1 {
+		return fmt.Errorf("element shape occurs %d times in Frame", len(elmShape))
+	}
+	if len(elmShape) == 1 {
+		t.Shape = elmShape[0]
+	}
+	if len(v.Title) > 1 {
+		return fmt.Errorf("element title occurs %d times in Frame", len(v.Title))
+	}
+	if len(v.Title) == 1 {
+		t.Title = &v.Title[0]
+	}
+	return nil
+}
+
+var nsNovelQName = xml.Name{Space: "urn:caementarii:simple", Local: "novel"}
+
+type Novel string
+
+func (t *Novel) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*string)(t), &start)
+}
+
+func (t Novel) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsNovelQName
+	return e.EncodeElement(string(t), start)
+}
+
+var nsPublicationQName = xml.Name{Space: "urn:caementarii:simple", Local: "publication"}
+
+type Publication string
+
+func (t *Publication) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*string)(t), &start)
+}
+
+func (t Publication) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsPublicationQName
+	return e.EncodeElement(string(t), start)
+}
+
+type PublicationElement interface {
+	isPublicationElement()
+}
+
+func (Book) isPublicationElement() {}
+
+func (Novel) isPublicationElement() {}
+
+type publicationElements []PublicationElement
+
+func (t *publicationElements) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "book"}:
+		var v Book
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		*t = append(*t, v)
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "novel"}:
+		var v Novel
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		*t = append(*t, v)
+		return nil
+	}
+	return fmt.Errorf("element {%s}%s is not allowed in place of publication", start.Name.Space, start.Name.Local)
+}
+
+var nsShapeQName = xml.Name{Space: "urn:caementarii:simple", Local: "shape"}
+
+type Shape AnyShapeType
+
+func (t *Shape) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*AnyShapeType)(t), &start)
+}
+
+func (t Shape) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsShapeQName
+	return e.EncodeElement(AnyShapeType(t), start)
+}
+
+type ShapeElement interface {
+	isShapeElement()
+}
+
+func (Circle) isShapeElement() {}
+
+func (Shape) isShapeElement() {}
+
+func (Square) isShapeElement() {}
+
+type shapeElements []ShapeElement
+
+func (t *shapeElements) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "circle"}:
+		var v Circle
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		*t = append(*t, v)
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "shape"}:
+		var v Shape
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		*t = append(*t, v)
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "square"}:
+		var v Square
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		*t = append(*t, v)
+		return nil
+	}
+	return fmt.Errorf("element {%s}%s is not allowed in place of shape", start.Name.Space, start.Name.Local)
+}
+
+type ShapeType struct {
+	Name string `xml:"name"`
+}
+
+type ShapeTypeValue interface {
+	isShapeType()
+}
+
+func (ShapeType) isShapeType() {}
+
+func (CircleType) isShapeType() {}
+
+type AnyShapeType struct {
+	Value ShapeTypeValue
+}
+
+func (t *AnyShapeType) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	name, ok, err := xsiType(&start)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		name = xml.Name{Space: "urn:caementarii:simple", Local: "shapeType"}
+	}
+	switch name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "shapeType"}:
+		var v ShapeType
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "circleType"}:
+		var v CircleType
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	}
+	return fmt.Errorf("xsi:type {%s}%s is not allowed for shapeType", name.Space, name.Local)
+}
+
+func (t AnyShapeType) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch v := t.Value.(type) {
+	case nil:
+		return nil
+	case ShapeType, *ShapeType:
+		return e.EncodeElement(v, start)
+	case CircleType, *CircleType:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "circleType", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	}
+	return fmt.Errorf("%T is not allowed for shapeType", t.Value)
+}
+
+type Square struct {
+	XMLName xml.Name `xml:"urn:caementarii:simple square"`
+	ShapeType
+	Side int `xml:"side"`
+}
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// xsiType returns the type name of the xsi:type attribute of an element and removes the attribute.
+//
+// The decoder of encoding/xml doesn't report namespaces declared by ancestors of the element, so the
+// prefix of the name is resolved only by declarations of the element itself. Decode by xs.NewDecoder,
+// which copies them from the ancestors: with xml.NewDecoder or xml.Unmarshal a prefix declared by an
+// ancestor is an error "prefix ... is not declared", and a name without a prefix has no namespace
+// unless the element declares a default one.
+func xsiType(start *xml.StartElement) (xml.Name, bool, error) {
+	for i, attr := range start.Attr {
+		if attr.Name.Space != xsiNamespace || attr.Name.Local != "type" {
+			continue
+		}
+		start.Attr = append(start.Attr[:i:i], start.Attr[i+1:]...)
+		prefix, local, found := strings.Cut(strings.TrimSpace(attr.Value), ":")
+		if !found {
+			prefix, local = "", prefix
+		}
+		for _, ns := range start.Attr {
+			if (prefix != "" && ns.Name.Space == "xmlns" && ns.Name.Local == prefix) || (prefix == "" && ns.Name.Space == "" && ns.Name.Local == "xmlns") {
+				return xml.Name{Space: ns.Value, Local: local}, true, nil
+			}
+		}
+		if prefix != "" {
+			return xml.Name{}, true, fmt.Errorf("xsi:type %q: prefix %s is not declared", attr.Value, prefix)
+		}
+		return xml.Name{Local: local}, true, nil
+	}
+	return xml.Name{}, false, nil
+}
+
+func xsiTypeAttrs(element string, space string, local string, prefix string) []xml.Attr {
+	attr := xml.Attr{Name: xml.Name{Space: xsiNamespace, Local: "type"}, Value: local}
+	if space == "" || space == element {
+		return []xml.Attr{attr}
+	}
+	attr.Value = prefix + ":" + local
+	return []xml.Attr{{Name: xml.Name{Local: "xmlns:" + prefix}, Value: space}, attr}
+}
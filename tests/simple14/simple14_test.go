@@ -0,0 +1,137 @@
+package simple14
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple14(t *testing.T) {
+	data, err := os.ReadFile("simple14.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple14",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple14.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestSubstitutionGroupErrors(t *testing.T) {
+	for _, data := range []string{
+		// The head's type is final for extension.
+		`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:caementarii:simple" targetNamespace="urn:caementarii:simple">
+    <xs:complexType name="a"><xs:sequence><xs:element name="x" type="xs:string"/></xs:sequence></xs:complexType>
+    <xs:complexType name="b"><xs:complexContent><xs:extension base="tns:a"/></xs:complexContent></xs:complexType>
+    <xs:element name="head" type="tns:a" final="extension"/>
+    <xs:element name="member" type="tns:b" substitutionGroup="tns:head"/>
+</xs:schema>`,
+		// The member's type is not derived from the head's type.
+		`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:caementarii:simple" targetNamespace="urn:caementarii:simple">
+    <xs:complexType name="a"><xs:sequence><xs:element name="x" type="xs:string"/></xs:sequence></xs:complexType>
+    <xs:element name="head" type="tns:a"/>
+    <xs:element name="member" type="xs:string" substitutionGroup="tns:head"/>
+</xs:schema>`,
+		// Circular substitution group.
+		`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:caementarii:simple" targetNamespace="urn:caementarii:simple">
+    <xs:element name="a" substitutionGroup="tns:b"/>
+    <xs:element name="b" substitutionGroup="tns:a"/>
+</xs:schema>`,
+	} {
+		s := xsd.Schema{}
+		if err := xml.Unmarshal([]byte(data), &s); err != nil {
+			t.Fatal(err)
+		}
+		g := goxsd.Generator{
+			PkgName: "simple14",
+		}
+		assert.Error(t, g.Generate(&s, new(bytes.Buffer)), data)
+	}
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<drawing xmlns="urn:caementarii:simple">` +
+		`<shape><name>s</name></shape>` +
+		`<circle><name>c</name><radius>2</radius></circle>` +
+		`<square><name>q</name><side>3</side></square>` +
+		`<novel>n</novel>` +
+		`</drawing>`
+	out := Drawing{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	circle := Circle{Radius: 2}
+	circle.Name = "c"
+	square := Square{XMLName: xml.Name{Space: "urn:caementarii:simple", Local: "square"}, Side: 3}
+	square.Name = "q"
+	assert.Equal(t, []ShapeElement{Shape{Value: ShapeType{Name: "s"}}, circle, square}, out.Shape)
+	assert.Equal(t, Novel("n"), out.Publication)
+}
+
+func TestUnmarshalerRejects(t *testing.T) {
+	for _, in := range []string{
+		// The head is abstract.
+		`<drawing xmlns="urn:caementarii:simple"><shape><name>s</name></shape><publication>p</publication></drawing>`,
+		// At most one publication is allowed.
+		`<drawing xmlns="urn:caementarii:simple"><shape><name>s</name></shape><book>a</book><book>b</book></drawing>`,
+		// The shape is required in the all group.
+		`<frame xmlns="urn:caementarii:simple"><title>t</title></frame>`,
+	} {
+		var out interface{} = &Drawing{}
+		if bytes.HasPrefix([]byte(in), []byte("<frame")) {
+			out = &Frame{}
+		}
+		assert.Error(t, xml.Unmarshal([]byte(in), out), in)
+	}
+}
+
+func TestMarshaler(t *testing.T) {
+	circle := Circle{Radius: 2}
+	circle.Name = "c"
+	square := Square{Side: 3}
+	square.Name = "q"
+	drawing := Drawing{Shape: []ShapeElement{circle, square}, Publication: Book("b")}
+
+	data, e := xml.Marshal(drawing)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, `<drawing xmlns="urn:caementarii:simple"><circle xmlns="urn:caementarii:simple"><name>c</name><radius>2</radius></circle><square xmlns="urn:caementarii:simple"><name>q</name><side>3</side></square><book xmlns="urn:caementarii:simple">b</book></drawing>`, string(data))
+
+	out := Drawing{}
+	e = xml.Unmarshal(data, &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+	square.XMLName = xml.Name{Space: "urn:caementarii:simple", Local: "square"}
+	assert.Equal(t, []ShapeElement{circle, square}, out.Shape)
+
+	frame := Frame{Shape: circle}
+	data, e = xml.Marshal(frame)
+	if e != nil {
+		t.Fatal(e)
+	}
+	assert.Equal(t, `<frame xmlns="urn:caementarii:simple"><circle xmlns="urn:caementarii:simple"><name>c</name><radius>2</radius></circle></frame>`, string(data))
+}
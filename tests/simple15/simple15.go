@@ -0,0 +1,117 @@
+package simple15
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+	"time"
+)
+
+type BirthDate xs.Date
+
+func (t BirthDate) Validate() error {
+	if t.Time.Before(time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		return fmt.Errorf("BirthDate: %q must be at least 1900-01-01", xs.Date(t).String())
+	}
+	if t.Timezone {
+		return fmt.Errorf("BirthDate: %q must not have a timezone", xs.Date(t).String())
+	}
+	return nil
+}
+
+func (t BirthDate) MarshalText() ([]byte, error) {
+	return xs.Date(t).MarshalText()
+}
+
+func (t *BirthDate) UnmarshalText(text []byte) error {
+	var x xs.Date
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("BirthDate: %q is not a valid value: %w", text, err)
+	}
+	v := BirthDate(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Event struct {
+	XMLName   xml.Name          `xml:"urn:caementarii:simple event"`
+	Date      *xs.Date          `xml:"date,attr,omitempty"`
+	Start     xs.DateTime       `xml:"start"`
+	Stamp     *xs.DateTimeStamp `xml:"stamp"`
+	BirthDate *BirthDate        `xml:"birthDate"`
+	Opening   *OpeningTime      `xml:"opening"`
+	Holiday   *Holiday          `xml:"holiday"`
+	Month     *xs.GYearMonth    `xml:"month"`
+	Year      *xs.GYear         `xml:"year"`
+	Day       *xs.GDay          `xml:"day"`
+	GMonth    *xs.GMonth        `xml:"gMonth"`
+}
+
+type Holiday xs.GMonthDay
+
+func (t Holiday) IsValid() bool {
+	switch xs.GMonthDay(t).String() {
+	case "--01-01", "--12-25":
+		return true
+	}
+	return false
+}
+
+func (t Holiday) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Holiday: %q is not a valid value", xs.GMonthDay(t).String())
+	}
+	return nil
+}
+
+func (t Holiday) MarshalText() ([]byte, error) {
+	return xs.GMonthDay(t).MarshalText()
+}
+
+func (t *Holiday) UnmarshalText(text []byte) error {
+	var x xs.GMonthDay
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("Holiday: %q is not a valid value: %w", text, err)
+	}
+	v := Holiday(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type OpeningTime xs.Time
+
+func (t OpeningTime) Validate() error {
+	if t.Time.Before(time.Date(2000, 1, 1, 8, 0, 0, 0, time.UTC)) {
+		return fmt.Errorf("OpeningTime: %q must be at least 08:00:00Z", xs.Time(t).String())
+	}
+	if !t.Time.Before(time.Date(2000, 1, 1, 20, 0, 0, 0, time.UTC)) {
+		return fmt.Errorf("OpeningTime: %q must be earlier than 20:00:00Z", xs.Time(t).String())
+	}
+	if !t.Timezone {
+		return fmt.Errorf("OpeningTime: %q must have a timezone", xs.Time(t).String())
+	}
+	return nil
+}
+
+func (t OpeningTime) MarshalText() ([]byte, error) {
+	return xs.Time(t).MarshalText()
+}
+
+func (t *OpeningTime) UnmarshalText(text []byte) error {
+	var x xs.Time
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("OpeningTime: %q is not a valid value: %w", text, err)
+	}
+	v := OpeningTime(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
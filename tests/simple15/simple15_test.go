@@ -0,0 +1,129 @@
+package simple15
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xs"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSimple15(t *testing.T) {
+	data, err := os.ReadFile("simple15.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple15",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple15.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestExplicitTimezoneErrors(t *testing.T) {
+	for _, data := range []string{
+		// A required timezone of xs:dateTimeStamp can't be made optional.
+		`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:caementarii:simple" targetNamespace="urn:caementarii:simple">
+    <xs:simpleType name="a"><xs:restriction base="xs:dateTimeStamp"><xs:explicitTimezone value="optional"/></xs:restriction></xs:simpleType>
+    <xs:element name="x" type="tns:a"/>
+</xs:schema>`,
+		// A prohibited timezone can't be required by a derived type.
+		`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:caementarii:simple" targetNamespace="urn:caementarii:simple">
+    <xs:simpleType name="a"><xs:restriction base="xs:date"><xs:explicitTimezone value="prohibited"/></xs:restriction></xs:simpleType>
+    <xs:simpleType name="b"><xs:restriction base="tns:a"><xs:explicitTimezone value="required"/></xs:restriction></xs:simpleType>
+    <xs:element name="x" type="tns:b"/>
+</xs:schema>`,
+		// An unknown value.
+		`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:tns="urn:caementarii:simple" targetNamespace="urn:caementarii:simple">
+    <xs:simpleType name="a"><xs:restriction base="xs:date"><xs:explicitTimezone value="sometimes"/></xs:restriction></xs:simpleType>
+    <xs:element name="x" type="tns:a"/>
+</xs:schema>`,
+	} {
+		s := xsd.Schema{}
+		if err := xml.Unmarshal([]byte(data), &s); err != nil {
+			t.Fatal(err)
+		}
+		g := goxsd.Generator{
+			PkgName: "simple15",
+		}
+		assert.Error(t, g.Generate(&s, new(bytes.Buffer)), data)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, BirthDate{Time: time.Date(1980, 5, 17, 0, 0, 0, 0, time.UTC)}.Validate())
+	assert.Error(t, BirthDate{Time: time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)}.Validate())
+	assert.Error(t, BirthDate{Time: time.Date(1980, 5, 17, 0, 0, 0, 0, time.UTC), Timezone: true}.Validate())
+
+	assert.NoError(t, OpeningTime{Time: time.Date(2000, 1, 1, 8, 0, 0, 0, time.UTC), Timezone: true}.Validate())
+	assert.Error(t, OpeningTime{Time: time.Date(2000, 1, 1, 20, 0, 0, 0, time.UTC), Timezone: true}.Validate())
+	assert.Error(t, OpeningTime{Time: time.Date(2000, 1, 1, 12, 0, 0, 0, time.UTC)}.Validate())
+
+	assert.NoError(t, Holiday{Time: time.Date(2000, 12, 25, 0, 0, 0, 0, time.UTC)}.Validate())
+	assert.Error(t, Holiday{Time: time.Date(2000, 12, 24, 0, 0, 0, 0, time.UTC)}.Validate())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<event xmlns="urn:caementarii:simple" date="2024-02-29"><start>2024-03-01T10:30:00.5+02:00</start><stamp>2024-03-01T08:30:00Z</stamp><birthDate>1980-05-17</birthDate><opening>09:00:00-01:00</opening><holiday>--12-25</holiday><month>2024-03</month><year>-0044</year><day>---15</day><gMonth>--03</gMonth></event>`
+	out := Event{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert.Equal(t, "2024-02-29", out.Date.String())
+	assert.Equal(t, "2024-03-01T10:30:00.5+02:00", out.Start.String())
+	assert.True(t, out.Start.Timezone)
+	assert.True(t, out.Start.Time.Equal(out.Stamp.Time.Add(500*time.Millisecond)))
+	assert.Equal(t, "1980-05-17", xs.Date(*out.BirthDate).String())
+	assert.Equal(t, "09:00:00-01:00", xs.Time(*out.Opening).String())
+	assert.Equal(t, "--12-25", xs.GMonthDay(*out.Holiday).String())
+	assert.Equal(t, "2024-03", out.Month.String())
+	assert.Equal(t, -44, out.Year.Time.Year())
+	assert.Equal(t, 15, out.Day.Time.Day())
+	assert.Equal(t, time.March, out.GMonth.Time.Month())
+}
+
+func TestMarshaler(t *testing.T) {
+	start, _ := xs.ParseDateTime("2024-03-01T10:30:00Z")
+	birthDate, _ := xs.ParseDate("1980-05-17")
+	b := BirthDate(birthDate)
+	in := Event{Start: start, BirthDate: &b}
+
+	out, err := xml.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `<event xmlns="urn:caementarii:simple"><start>2024-03-01T10:30:00Z</start><birthDate>1980-05-17</birthDate></event>`, string(out))
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, in := range []string{
+		`<event xmlns="urn:caementarii:simple"><start>2024-03-01</start></event>`,
+		`<event xmlns="urn:caementarii:simple" date="2023-02-29"><start>2024-03-01T10:30:00</start></event>`,
+		`<event xmlns="urn:caementarii:simple"><start>2024-03-01T10:30:00</start><stamp>2024-03-01T10:30:00</stamp></event>`,
+		`<event xmlns="urn:caementarii:simple"><start>2024-03-01T10:30:00</start><birthDate>1980-05-17Z</birthDate></event>`,
+		`<event xmlns="urn:caementarii:simple"><start>2024-03-01T10:30:00</start><opening>21:00:00Z</opening></event>`,
+		`<event xmlns="urn:caementarii:simple"><start>2024-03-01T10:30:00</start><holiday>--07-04</holiday></event>`,
+	} {
+		out := Event{}
+		assert.Error(t, xml.Unmarshal([]byte(in), &out), in)
+	}
+}
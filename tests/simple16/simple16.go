@@ -0,0 +1,105 @@
+package simple16
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+)
+
+type Contract struct {
+	XMLName   xml.Name     `xml:"urn:caementarii:simple contract"`
+	Timeout   *Timeout     `xml:"timeout,attr,omitempty"`
+	Term      Term         `xml:"term"`
+	Notice    xs.Duration  `xml:"notice"`
+	Retention *Retention   `xml:"retention"`
+	Grace     *xs.Duration `xml:"grace"`
+}
+
+type Retention xs.Duration
+
+func (t Retention) Validate() error {
+	if c, ok := xs.Duration(t).Compare(xs.Duration{Months: 1, Seconds: 0}); !ok || c > 0 {
+		return fmt.Errorf("Retention: %q must be at most P1M", xs.Duration(t).String())
+	}
+	return nil
+}
+
+func (t Retention) MarshalText() ([]byte, error) {
+	return xs.Duration(t).MarshalText()
+}
+
+func (t *Retention) UnmarshalText(text []byte) error {
+	var x xs.Duration
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("Retention: %q is not a valid value: %w", text, err)
+	}
+	v := Retention(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Term xs.Duration
+
+func (t Term) IsValid() bool {
+	switch xs.Duration(t).String() {
+	case "P6M", "P1Y":
+		return true
+	}
+	return false
+}
+
+func (t Term) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Term: %q is not a valid value", xs.Duration(t).String())
+	}
+	return nil
+}
+
+func (t Term) MarshalText() ([]byte, error) {
+	return xs.Duration(t).MarshalText()
+}
+
+func (t *Term) UnmarshalText(text []byte) error {
+	var x xs.Duration
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("Term: %q is not a valid value: %w", text, err)
+	}
+	v := Term(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Timeout xs.Duration
+
+func (t Timeout) Validate() error {
+	if c, ok := xs.Duration(t).Compare(xs.Duration{Months: 0, Seconds: 0}); !ok || c <= 0 {
+		return fmt.Errorf("Timeout: %q must be longer than PT0S", xs.Duration(t).String())
+	}
+	if c, ok := xs.Duration(t).Compare(xs.Duration{Months: 0, Seconds: 3600000000000}); !ok || c > 0 {
+		return fmt.Errorf("Timeout: %q must be at most PT1H", xs.Duration(t).String())
+	}
+	return nil
+}
+
+func (t Timeout) MarshalText() ([]byte, error) {
+	return xs.Duration(t).MarshalText()
+}
+
+func (t *Timeout) UnmarshalText(text []byte) error {
+	var x xs.Duration
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("Timeout: %q is not a valid value: %w", text, err)
+	}
+	v := Timeout(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
@@ -0,0 +1,93 @@
+package simple16
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xs"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSimple16(t *testing.T) {
+	data, err := os.ReadFile("simple16.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple16",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple16.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Timeout{Seconds: time.Minute}.Validate())
+	assert.NoError(t, Timeout{Seconds: time.Hour}.Validate())
+	assert.Error(t, Timeout{}.Validate())
+	assert.Error(t, Timeout{Seconds: time.Hour + time.Second}.Validate())
+
+	assert.NoError(t, Term{Months: 12}.Validate())
+	assert.Error(t, Term{Months: 3}.Validate())
+
+	// P30D is incomparable with P1M, so it isn't at most P1M.
+	assert.NoError(t, Retention{Seconds: 27 * 24 * time.Hour}.Validate())
+	assert.Error(t, Retention{Seconds: 30 * 24 * time.Hour}.Validate())
+	assert.Error(t, Retention{Seconds: 32 * 24 * time.Hour}.Validate())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<contract xmlns="urn:caementarii:simple" timeout="PT30S"><term>P1Y</term><notice>-P1M2DT3H</notice><retention>P1M</retention><grace>PT1.5S</grace></contract>`
+	out := Contract{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	timeout := Timeout{Seconds: 30 * time.Second}
+	retention := Retention{Months: 1}
+	assert.Equal(t, &timeout, out.Timeout)
+	assert.Equal(t, Term{Months: 12}, out.Term)
+	assert.Equal(t, xs.Duration{Months: -1, Seconds: -(2*24*time.Hour + 3*time.Hour)}, out.Notice)
+	assert.Equal(t, &retention, out.Retention)
+	assert.Equal(t, &xs.Duration{Seconds: 1500 * time.Millisecond}, out.Grace)
+}
+
+func TestMarshaler(t *testing.T) {
+	timeout := Timeout{Seconds: 90 * time.Second}
+	in := Contract{Timeout: &timeout, Term: Term{Months: 6}, Notice: xs.Duration{Seconds: 14 * 24 * time.Hour}}
+
+	out, err := xml.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `<contract xmlns="urn:caementarii:simple" timeout="PT1M30S"><term>P6M</term><notice>P14D</notice></contract>`, string(out))
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, in := range []string{
+		`<contract xmlns="urn:caementarii:simple"><term>P1Y</term><notice>P1H</notice></contract>`,
+		`<contract xmlns="urn:caementarii:simple" timeout="PT2H"><term>P1Y</term><notice>P1D</notice></contract>`,
+		`<contract xmlns="urn:caementarii:simple"><term>P2Y</term><notice>P1D</notice></contract>`,
+		`<contract xmlns="urn:caementarii:simple"><term>P1Y</term><notice>P1D</notice><retention>P31D</retention></contract>`,
+	} {
+		out := Contract{}
+		assert.Error(t, xml.Unmarshal([]byte(in), &out), in)
+	}
+}
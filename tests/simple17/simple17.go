@@ -0,0 +1,85 @@
+package simple17
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+)
+
+type Amount xs.Decimal
+
+func (t Amount) Validate() error {
+	if xs.Decimal(t).Cmp(xs.MustParseDecimal("0")) <= 0 {
+		return fmt.Errorf("Amount: %q must be greater than 0", xs.Decimal(t).String())
+	}
+	if xs.Decimal(t).Cmp(xs.MustParseDecimal("1000000")) >= 0 {
+		return fmt.Errorf("Amount: %q must be less than 1000000", xs.Decimal(t).String())
+	}
+	if n := xs.Decimal(t).TotalDigits(); n > 8 {
+		return fmt.Errorf("Amount: %q has %d digits, but at most 8 allowed", xs.Decimal(t).String(), n)
+	}
+	if xs.Decimal(t).FractionDigits() > 2 {
+		return fmt.Errorf("Amount: %q has more than 2 fraction digits", xs.Decimal(t).String())
+	}
+	return nil
+}
+
+func (t Amount) MarshalText() ([]byte, error) {
+	return xs.Decimal(t).MarshalText()
+}
+
+func (t *Amount) UnmarshalText(text []byte) error {
+	var x xs.Decimal
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("Amount: %q is not a valid value: %w", text, err)
+	}
+	v := Amount(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type InvoiceLine struct {
+	XMLName  xml.Name    `xml:"urn:caementarii:simple invoiceLine"`
+	Vat      *VatRate    `xml:"vat,attr,omitempty"`
+	Price    Amount      `xml:"price"`
+	Quantity xs.Decimal  `xml:"quantity"`
+	Discount *xs.Decimal `xml:"discount"`
+	Count    *int        `xml:"count"`
+}
+
+type VatRate xs.Decimal
+
+func (t VatRate) IsValid() bool {
+	switch xs.Decimal(t).Canonical() {
+	case "0", "0.07", "0.19":
+		return true
+	}
+	return false
+}
+
+func (t VatRate) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("VatRate: %q is not a valid value", xs.Decimal(t).String())
+	}
+	return nil
+}
+
+func (t VatRate) MarshalText() ([]byte, error) {
+	return xs.Decimal(t).MarshalText()
+}
+
+func (t *VatRate) UnmarshalText(text []byte) error {
+	var x xs.Decimal
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("VatRate: %q is not a valid value: %w", text, err)
+	}
+	v := VatRate(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
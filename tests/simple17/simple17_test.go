@@ -0,0 +1,89 @@
+package simple17
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xs"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"os"
+	"testing"
+)
+
+func TestSimple17(t *testing.T) {
+	data, err := os.ReadFile("simple17.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple17",
+		Decimal: goxsd.DecimalExact,
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple17.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Amount(xs.MustParseDecimal("123456.78")).Validate())
+	assert.NoError(t, Amount(xs.MustParseDecimal("0.10")).Validate())
+	assert.Error(t, Amount(xs.MustParseDecimal("0")).Validate())
+	assert.Error(t, Amount(xs.MustParseDecimal("1000000")).Validate())
+	assert.Error(t, Amount(xs.MustParseDecimal("1.234")).Validate())
+
+	assert.NoError(t, VatRate(xs.MustParseDecimal("0.070")).Validate())
+	assert.Error(t, VatRate(xs.MustParseDecimal("0.16")).Validate())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<invoiceLine xmlns="urn:caementarii:simple" vat="0.19"><price>19.90</price><quantity> 0.1 </quantity><discount>-0.30</discount></invoiceLine>`
+	out := InvoiceLine{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	vat := VatRate(xs.MustParseDecimal("0.19"))
+	discount := xs.MustParseDecimal("-0.30")
+	assert.Equal(t, &vat, out.Vat)
+	assert.Equal(t, Amount(xs.MustParseDecimal("19.90")), out.Price)
+	assert.Equal(t, xs.MustParseDecimal("0.1"), out.Quantity)
+	assert.Equal(t, &discount, out.Discount)
+	assert.Equal(t, 0, xs.Decimal(out.Price).Cmp(xs.NewDecimal(big.NewInt(199), 1)))
+}
+
+func TestMarshaler(t *testing.T) {
+	in := InvoiceLine{Price: Amount(xs.MustParseDecimal("19.90")), Quantity: xs.MustParseDecimal("3")}
+
+	out, err := xml.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `<invoiceLine xmlns="urn:caementarii:simple"><price>19.90</price><quantity>3</quantity></invoiceLine>`, string(out))
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, in := range []string{
+		`<invoiceLine xmlns="urn:caementarii:simple"><price>1e3</price><quantity>1</quantity></invoiceLine>`,
+		`<invoiceLine xmlns="urn:caementarii:simple"><price>0.001</price><quantity>1</quantity></invoiceLine>`,
+		`<invoiceLine xmlns="urn:caementarii:simple" vat="0.2"><price>1</price><quantity>1</quantity></invoiceLine>`,
+		`<invoiceLine xmlns="urn:caementarii:simple"><price>1</price><quantity>one</quantity></invoiceLine>`,
+	} {
+		out := InvoiceLine{}
+		assert.Error(t, xml.Unmarshal([]byte(in), &out), in)
+	}
+}
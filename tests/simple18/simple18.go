@@ -0,0 +1,91 @@
+package simple18
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+)
+
+type Amount xs.Rat
+
+func (t Amount) Validate() error {
+	if !xs.Rat(t).IsDecimal() {
+		return fmt.Errorf("Amount: %q has no finite decimal representation", xs.Rat(t).String())
+	}
+	if xs.Rat(t).Decimal().Cmp(xs.MustParseDecimal("0")) <= 0 {
+		return fmt.Errorf("Amount: %q must be greater than 0", xs.Rat(t).String())
+	}
+	if xs.Rat(t).Decimal().Cmp(xs.MustParseDecimal("1000000")) >= 0 {
+		return fmt.Errorf("Amount: %q must be less than 1000000", xs.Rat(t).String())
+	}
+	if n := xs.Rat(t).Decimal().TotalDigits(); n > 8 {
+		return fmt.Errorf("Amount: %q has %d digits, but at most 8 allowed", xs.Rat(t).String(), n)
+	}
+	if xs.Rat(t).Decimal().FractionDigits() > 2 {
+		return fmt.Errorf("Amount: %q has more than 2 fraction digits", xs.Rat(t).String())
+	}
+	return nil
+}
+
+func (t Amount) MarshalText() ([]byte, error) {
+	return xs.Rat(t).MarshalText()
+}
+
+func (t *Amount) UnmarshalText(text []byte) error {
+	var x xs.Rat
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("Amount: %q is not a valid value: %w", text, err)
+	}
+	v := Amount(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type InvoiceLine struct {
+	XMLName  xml.Name `xml:"urn:caementarii:simple invoiceLine"`
+	Vat      *VatRate `xml:"vat,attr,omitempty"`
+	Price    Amount   `xml:"price"`
+	Quantity xs.Rat   `xml:"quantity"`
+	Discount *xs.Rat  `xml:"discount"`
+	Count    *int     `xml:"count"`
+}
+
+type VatRate xs.Rat
+
+func (t VatRate) IsValid() bool {
+	switch xs.Rat(t).Decimal().Canonical() {
+	case "0", "0.07", "0.19":
+		return true
+	}
+	return false
+}
+
+func (t VatRate) Validate() error {
+	if !xs.Rat(t).IsDecimal() {
+		return fmt.Errorf("VatRate: %q has no finite decimal representation", xs.Rat(t).String())
+	}
+	if !t.IsValid() {
+		return fmt.Errorf("VatRate: %q is not a valid value", xs.Rat(t).String())
+	}
+	return nil
+}
+
+func (t VatRate) MarshalText() ([]byte, error) {
+	return xs.Rat(t).MarshalText()
+}
+
+func (t *VatRate) UnmarshalText(text []byte) error {
+	var x xs.Rat
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("VatRate: %q is not a valid value: %w", text, err)
+	}
+	v := VatRate(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
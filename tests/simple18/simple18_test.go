@@ -0,0 +1,78 @@
+package simple18
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xs"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"os"
+	"testing"
+)
+
+func TestSimple18(t *testing.T) {
+	data, err := os.ReadFile("simple18.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple18",
+		Decimal: goxsd.DecimalBigRat,
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple18.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Amount{big.NewRat(12345678, 100)}.Validate())
+	assert.Error(t, Amount{}.Validate())
+	assert.Error(t, Amount{big.NewRat(1, 3)}.Validate())
+	assert.Error(t, Amount{big.NewRat(1, 1000)}.Validate())
+
+	assert.NoError(t, VatRate{big.NewRat(7, 100)}.Validate())
+	assert.NoError(t, VatRate{}.Validate())
+	assert.Error(t, VatRate{big.NewRat(16, 100)}.Validate())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<invoiceLine xmlns="urn:caementarii:simple" vat="0.19"><price>19.90</price><quantity>0.1</quantity></invoiceLine>`
+	out := InvoiceLine{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert.Equal(t, big.NewRat(19, 100), out.Vat.Rat)
+	assert.Equal(t, big.NewRat(199, 10), out.Price.Rat)
+	assert.Equal(t, xs.Rat{Rat: big.NewRat(1, 10)}, out.Quantity)
+	assert.Nil(t, out.Discount)
+}
+
+func TestMarshaler(t *testing.T) {
+	in := InvoiceLine{Price: Amount{big.NewRat(199, 10)}, Quantity: xs.Rat{Rat: big.NewRat(3, 4)}}
+
+	out, err := xml.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `<invoiceLine xmlns="urn:caementarii:simple"><price>19.9</price><quantity>0.75</quantity></invoiceLine>`, string(out))
+
+	in.Quantity = xs.Rat{Rat: big.NewRat(1, 3)}
+	_, err = xml.Marshal(in)
+	assert.Error(t, err)
+}
@@ -0,0 +1,91 @@
+package simple19
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple19(t *testing.T) {
+	data, err := os.ReadFile("simple19.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple19",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple19.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Port(8080).Validate())
+	assert.Error(t, Port(0).Validate())
+
+	assert.NoError(t, PriorityMinus1.Validate())
+	assert.Error(t, Priority(2).Validate())
+
+	assert.NoError(t, Serial(1000).Validate())
+	assert.Error(t, Serial(999).Validate())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<sample xmlns="urn:caementarii:simple" port="443" priority="-1"><long>-9223372036854775808</long><int>2147483647</int><short>-32768</short><byte>127</byte><unsignedLong>18446744073709551615</unsignedLong><unsignedInt>4294967295</unsignedInt><unsignedByte>255</unsignedByte><negative>-5</negative><nonPositive>0</nonPositive><integer>+42</integer><serial>1234</serial></sample>`
+	out := Sample{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	port := Port(443)
+	priority := PriorityMinus1
+	serial := Serial(1234)
+	assert.Equal(t, Sample{
+		XMLName:      xml.Name{Space: "urn:caementarii:simple", Local: "sample"},
+		Port:         &port,
+		Priority:     &priority,
+		Long:         -9223372036854775808,
+		Int:          2147483647,
+		Short:        -32768,
+		Byte:         127,
+		UnsignedLong: 18446744073709551615,
+		UnsignedInt:  4294967295,
+		UnsignedByte: 255,
+		Negative:     -5,
+		NonPositive:  0,
+		Integer:      42,
+		Serial:       &serial,
+	}, out)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	const rest = `<unsignedLong>0</unsignedLong><unsignedInt>0</unsignedInt><unsignedByte>0</unsignedByte><negative>-1</negative><nonPositive>0</nonPositive><integer>0</integer></sample>`
+	for _, in := range []string{
+		`<sample xmlns="urn:caementarii:simple"><long>0</long><int>2147483648</int><short>0</short><byte>0</byte>` + rest,
+		`<sample xmlns="urn:caementarii:simple"><long>0</long><int>0</int><short>0</short><byte>128</byte>` + rest,
+		`<sample xmlns="urn:caementarii:simple" port="65536"><long>0</long><int>0</int><short>0</short><byte>0</byte>` + rest,
+		`<sample xmlns="urn:caementarii:simple" priority="5"><long>0</long><int>0</int><short>0</short><byte>0</byte>` + rest,
+	} {
+		out := Sample{}
+		assert.Error(t, xml.Unmarshal([]byte(in), &out), in)
+	}
+}
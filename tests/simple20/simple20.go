@@ -0,0 +1,114 @@
+package simple20
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+	"strconv"
+)
+
+type Port uint16
+
+func (t Port) Validate() error {
+	if t < 1 {
+		return fmt.Errorf("Port: %v must be at least 1", t)
+	}
+	return nil
+}
+
+func (t *Port) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	x, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return fmt.Errorf("Port: %q is not a valid value: %w", text, err)
+	}
+	v := Port(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Priority int8
+
+const (
+	PriorityMinus1 Priority = -1
+	Priority0      Priority = 0
+	Priority1      Priority = 1
+)
+
+func (t Priority) IsValid() bool {
+	switch t {
+	case PriorityMinus1, Priority0, Priority1:
+		return true
+	}
+	return false
+}
+
+func (t Priority) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Priority: %v is not a valid value", t)
+	}
+	return nil
+}
+
+func (t *Priority) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	x, err := strconv.ParseInt(s, 10, 8)
+	if err != nil {
+		return fmt.Errorf("Priority: %q is not a valid value: %w", text, err)
+	}
+	v := Priority(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Sample struct {
+	XMLName      xml.Name  `xml:"urn:caementarii:simple sample"`
+	Port         *Port     `xml:"port,attr,omitempty"`
+	Priority     *Priority `xml:"priority,attr,omitempty"`
+	Long         int64     `xml:"long"`
+	Int          int32     `xml:"int"`
+	Short        int16     `xml:"short"`
+	Byte         int8      `xml:"byte"`
+	UnsignedLong uint64    `xml:"unsignedLong"`
+	UnsignedInt  uint32    `xml:"unsignedInt"`
+	UnsignedByte uint8     `xml:"unsignedByte"`
+	Negative     xs.Int    `xml:"negative"`
+	NonPositive  xs.Int    `xml:"nonPositive"`
+	Integer      xs.Int    `xml:"integer"`
+	Serial       *Serial   `xml:"serial"`
+}
+
+type Serial xs.Int
+
+func (t Serial) Validate() error {
+	if xs.Int(t).Decimal().Cmp(xs.MustParseDecimal("1000")) < 0 {
+		return fmt.Errorf("Serial: %q must be at least 1000", xs.Int(t).String())
+	}
+	if n := xs.Int(t).Decimal().TotalDigits(); n > 30 {
+		return fmt.Errorf("Serial: %q has %d digits, but at most 30 allowed", xs.Int(t).String(), n)
+	}
+	return nil
+}
+
+func (t Serial) MarshalText() ([]byte, error) {
+	return xs.Int(t).MarshalText()
+}
+
+func (t *Serial) UnmarshalText(text []byte) error {
+	var x xs.Int
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("Serial: %q is not a valid value: %w", text, err)
+	}
+	v := Serial(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
@@ -0,0 +1,68 @@
+package simple20
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xs"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple20(t *testing.T) {
+	data, err := os.ReadFile("simple20.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple20",
+		Integer: goxsd.IntegerBigInt,
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple20.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<sample xmlns="urn:caementarii:simple"><long>1</long><int>1</int><short>1</short><byte>1</byte><unsignedLong>1</unsignedLong><unsignedInt>1</unsignedInt><unsignedByte>1</unsignedByte><negative>-123456789012345678901234567890</negative><nonPositive>0</nonPositive><integer>010</integer><serial>123456789012345678901234567890</serial></sample>`
+	out := Sample{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	assert.Equal(t, "-123456789012345678901234567890", out.Negative.String())
+	assert.Equal(t, "10", out.Integer.String())
+	assert.Equal(t, "123456789012345678901234567890", xs.Int(*out.Serial).String())
+
+	serial, _ := xs.ParseInt("999")
+	assert.Error(t, Serial(serial).Validate())
+	serial, _ = xs.ParseInt("1234567890123456789012345678901")
+	assert.Error(t, Serial(serial).Validate())
+}
+
+func TestMarshaler(t *testing.T) {
+	integer, _ := xs.ParseInt("-98765432109876543210")
+	serial := Serial(integer)
+	in := Sample{Integer: integer, Serial: &serial}
+
+	out, err := xml.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `<sample xmlns="urn:caementarii:simple"><long>0</long><int>0</int><short>0</short><byte>0</byte><unsignedLong>0</unsignedLong><unsignedInt>0</unsignedInt><unsignedByte>0</unsignedByte><negative>0</negative><nonPositive>0</nonPositive><integer>-98765432109876543210</integer><serial>-98765432109876543210</serial></sample>`, string(out))
+}
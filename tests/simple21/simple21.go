@@ -0,0 +1,70 @@
+package simple21
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+)
+
+type Attachment struct {
+	XMLName   xml.Name        `xml:"urn:caementarii:simple attachment"`
+	Digest    Sha256          `xml:"digest,attr"`
+	Salt      *xs.HexBinary   `xml:"salt,attr,omitempty"`
+	Content   xs.Base64Binary `xml:"content"`
+	Thumbnail *Thumbnail      `xml:"thumbnail"`
+}
+
+type Sha256 xs.HexBinary
+
+func (t Sha256) Validate() error {
+	if n := len(t); n != 32 {
+		return fmt.Errorf("Sha256: length of %q is %d, but it must be 32", xs.HexBinary(t).String(), n)
+	}
+	return nil
+}
+
+func (t Sha256) MarshalText() ([]byte, error) {
+	return xs.HexBinary(t).MarshalText()
+}
+
+func (t *Sha256) UnmarshalText(text []byte) error {
+	var x xs.HexBinary
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("Sha256: %q is not a valid value: %w", text, err)
+	}
+	v := Sha256(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Thumbnail xs.Base64Binary
+
+func (t Thumbnail) Validate() error {
+	if n := len(t); n < 1 {
+		return fmt.Errorf("Thumbnail: length of %q is %d, but it must be at least 1", xs.Base64Binary(t).String(), n)
+	}
+	if n := len(t); n > 16 {
+		return fmt.Errorf("Thumbnail: length of %q is %d, but it must be at most 16", xs.Base64Binary(t).String(), n)
+	}
+	return nil
+}
+
+func (t Thumbnail) MarshalText() ([]byte, error) {
+	return xs.Base64Binary(t).MarshalText()
+}
+
+func (t *Thumbnail) UnmarshalText(text []byte) error {
+	var x xs.Base64Binary
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("Thumbnail: %q is not a valid value: %w", text, err)
+	}
+	v := Thumbnail(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
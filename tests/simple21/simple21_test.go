@@ -0,0 +1,93 @@
+package simple21
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xs"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple21(t *testing.T) {
+	data, err := os.ReadFile("simple21.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple21",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple21.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestValidate(t *testing.T) {
+	digest := sha256.Sum256([]byte("content"))
+	assert.NoError(t, Sha256(digest[:]).Validate())
+	assert.Error(t, Sha256(digest[:31]).Validate())
+
+	assert.NoError(t, Thumbnail("x").Validate())
+	assert.Error(t, Thumbnail{}.Validate())
+	assert.Error(t, Thumbnail("0123456789abcdefg").Validate())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	digest := sha256.Sum256([]byte("Hello, world"))
+	in := `<attachment xmlns="urn:caementarii:simple" digest=" ` + xs.HexBinary(digest[:]).String() + ` " salt="00ff">
+    <content>
+        SGVsbG8s
+        IHdvcmxk
+    </content>
+    <thumbnail>iVBORw==</thumbnail>
+</attachment>`
+	out := Attachment{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	salt := xs.HexBinary{0x00, 0xff}
+	thumbnail := Thumbnail{0x89, 'P', 'N', 'G'}
+	assert.Equal(t, Sha256(digest[:]), out.Digest)
+	assert.Equal(t, &salt, out.Salt)
+	assert.Equal(t, xs.Base64Binary("Hello, world"), out.Content)
+	assert.Equal(t, &thumbnail, out.Thumbnail)
+}
+
+func TestMarshaler(t *testing.T) {
+	digest := sha256.Sum256([]byte("Hi"))
+	in := Attachment{Digest: Sha256(digest[:]), Content: xs.Base64Binary("Hi")}
+
+	out, err := xml.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `<attachment xmlns="urn:caementarii:simple" digest="`+xs.HexBinary(digest[:]).String()+`"><content>SGk=</content></attachment>`, string(out))
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, in := range []string{
+		`<attachment xmlns="urn:caementarii:simple" digest="00"><content>SGk=</content></attachment>`,
+		`<attachment xmlns="urn:caementarii:simple" digest="0g"><content>SGk=</content></attachment>`,
+		`<attachment xmlns="urn:caementarii:simple" digest="00"><content>SGk</content></attachment>`,
+	} {
+		out := Attachment{}
+		assert.Error(t, xml.Unmarshal([]byte(in), &out), in)
+	}
+}
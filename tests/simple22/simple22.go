@@ -0,0 +1,264 @@
+package simple22
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+	"regexp"
+	"strconv"
+)
+
+type Address struct {
+	XMLName  xml.Name  `xml:"urn:caementarii:simple address"`
+	Building *Building `xml:"building,attr,omitempty"`
+	Street   string    `xml:"street"`
+	Floor    *Floor    `xml:"floor"`
+	Postcode Postcode  `xml:"postcode"`
+}
+
+type Building struct {
+	PositiveInteger *uint
+	Floor           *Floor
+	Date            *xs.Date
+	Token           *string
+}
+
+func (t Building) Which() string {
+	switch {
+	case t.PositiveInteger != nil:
+		return "positiveInteger"
+	case t.Floor != nil:
+		return "floor"
+	case t.Date != nil:
+		return "date"
+	case t.Token != nil:
+		return "token"
+	}
+	return ""
+}
+
+func (t Building) MarshalText() ([]byte, error) {
+	switch {
+	case t.PositiveInteger != nil:
+		return []byte(strconv.FormatUint(uint64(*t.PositiveInteger), 10)), nil
+	case t.Floor != nil:
+		return t.Floor.MarshalText()
+	case t.Date != nil:
+		return t.Date.MarshalText()
+	case t.Token != nil:
+		return []byte(*t.Token), nil
+	}
+	return nil, fmt.Errorf("Building: no member value is set")
+}
+
+func (t *Building) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	if x, err := strconv.ParseUint(s, 10, 64); err == nil && x >= 1 {
+		v := uint(x)
+		*t = Building{PositiveInteger: &v}
+		return nil
+	}
+	var m2 Floor
+	if err := m2.UnmarshalText(text); err == nil {
+		*t = Building{Floor: &m2}
+		return nil
+	}
+	var m3 xs.Date
+	if err := m3.UnmarshalText(text); err == nil {
+		*t = Building{Date: &m3}
+		return nil
+	}
+	v := xs.Collapse(string(text))
+	*t = Building{Token: &v}
+	return nil
+}
+
+type Floor struct {
+	Short  *int16
+	Floor2 *Floor2
+}
+
+func (t Floor) Which() string {
+	switch {
+	case t.Short != nil:
+		return "short"
+	case t.Floor2 != nil:
+		return "string"
+	}
+	return ""
+}
+
+func (t Floor) MarshalText() ([]byte, error) {
+	switch {
+	case t.Short != nil:
+		return []byte(strconv.FormatInt(int64(*t.Short), 10)), nil
+	case t.Floor2 != nil:
+		return []byte(*t.Floor2), nil
+	}
+	return nil, fmt.Errorf("Floor: no member value is set")
+}
+
+func (t *Floor) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	if x, err := strconv.ParseInt(s, 10, 16); err == nil {
+		v := int16(x)
+		*t = Floor{Short: &v}
+		return nil
+	}
+	var m2 Floor2
+	if err := m2.UnmarshalText(text); err == nil {
+		*t = Floor{Floor2: &m2}
+		return nil
+	}
+	return fmt.Errorf("Floor: %q is not a valid value of any member type", text)
+}
+
+type Floor2 string
+
+const (
+	Floor2Ground   Floor2 = "ground"
+	Floor2Basement Floor2 = "basement"
+)
+
+func (t Floor2) IsValid() bool {
+	switch t {
+	case Floor2Ground, Floor2Basement:
+		return true
+	}
+	return false
+}
+
+func (t Floor2) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Floor2: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Floor2) UnmarshalText(text []byte) error {
+	v := Floor2(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Postcode struct {
+	UkPostcode *UkPostcode
+	UsZip      *UsZip
+	Postcode2  *Postcode2
+}
+
+func (t Postcode) Which() string {
+	switch {
+	case t.UkPostcode != nil:
+		return "ukPostcode"
+	case t.UsZip != nil:
+		return "usZip"
+	case t.Postcode2 != nil:
+		return "token"
+	}
+	return ""
+}
+
+func (t Postcode) MarshalText() ([]byte, error) {
+	switch {
+	case t.UkPostcode != nil:
+		return []byte(*t.UkPostcode), nil
+	case t.UsZip != nil:
+		return []byte(*t.UsZip), nil
+	case t.Postcode2 != nil:
+		return []byte(*t.Postcode2), nil
+	}
+	return nil, fmt.Errorf("Postcode: no member value is set")
+}
+
+func (t *Postcode) UnmarshalText(text []byte) error {
+	var m1 UkPostcode
+	if err := m1.UnmarshalText(text); err == nil {
+		*t = Postcode{UkPostcode: &m1}
+		return nil
+	}
+	var m2 UsZip
+	if err := m2.UnmarshalText(text); err == nil {
+		*t = Postcode{UsZip: &m2}
+		return nil
+	}
+	var m3 Postcode2
+	if err := m3.UnmarshalText(text); err == nil {
+		*t = Postcode{Postcode2: &m3}
+		return nil
+	}
+	return fmt.Errorf("Postcode: %q is not a valid value of any member type", text)
+}
+
+type Postcode2 string
+
+const (
+	Postcode2None Postcode2 = "none"
+)
+
+func (t Postcode2) IsValid() bool {
+	switch t {
+	case Postcode2None:
+		return true
+	}
+	return false
+}
+
+func (t Postcode2) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Postcode2: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Postcode2) UnmarshalText(text []byte) error {
+	v := Postcode2(xs.Collapse(string(text)))
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+var patternUkPostcode = regexp.MustCompile(`^(?:[A-Z]{1,2}[0-9][A-Z0-9]? [0-9][A-Z]{2})$`)
+
+type UkPostcode string
+
+func (t UkPostcode) Validate() error {
+	if !patternUkPostcode.MatchString(string(t)) {
+		return fmt.Errorf("UkPostcode: %q does not match the pattern [A-Z]{1,2}[0-9][A-Z0-9]? [0-9][A-Z]{2}", string(t))
+	}
+	return nil
+}
+
+func (t *UkPostcode) UnmarshalText(text []byte) error {
+	v := UkPostcode(xs.Collapse(string(text)))
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+var patternUsZip = regexp.MustCompile(`^(?:[0-9]{5}(-[0-9]{4})?)$`)
+
+type UsZip string
+
+func (t UsZip) Validate() error {
+	if !patternUsZip.MatchString(string(t)) {
+		return fmt.Errorf("UsZip: %q does not match the pattern [0-9]{5}(-[0-9]{4})?", string(t))
+	}
+	return nil
+}
+
+func (t *UsZip) UnmarshalText(text []byte) error {
+	v := UsZip(xs.Collapse(string(text)))
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
@@ -0,0 +1,123 @@
+package simple22
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple22(t *testing.T) {
+	data, err := os.ReadFile("simple22.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple22",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple22.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+func TestUnmarshalText(t *testing.T) {
+	var p Postcode
+	assert.NoError(t, p.UnmarshalText([]byte(" SW1A  1AA ")))
+	assert.Equal(t, "ukPostcode", p.Which())
+	assert.Equal(t, UkPostcode("SW1A 1AA"), *p.UkPostcode)
+	assert.NoError(t, p.UnmarshalText([]byte("12345-6789")))
+	assert.Equal(t, "usZip", p.Which())
+	assert.Nil(t, p.UkPostcode)
+	assert.NoError(t, p.UnmarshalText([]byte("none")))
+	// The anonymous member type is reported by the name of its base type.
+	assert.Equal(t, "token", p.Which())
+	assert.NotNil(t, p.Postcode2)
+	assert.Error(t, p.UnmarshalText([]byte("1234")))
+
+	// Member types are tried in order, so 0 is not a positive integer but a floor.
+	var b Building
+	for in, which := range map[string]string{
+		"12":         "positiveInteger",
+		"0":          "floor",
+		"basement":   "floor",
+		"2024-01-31": "date",
+		"B":          "token",
+	} {
+		assert.NoError(t, b.UnmarshalText([]byte(in)), in)
+		assert.Equal(t, which, b.Which(), in)
+	}
+
+	var f Floor
+	assert.Error(t, f.UnmarshalText([]byte("roof")))
+	assert.Error(t, f.UnmarshalText([]byte("40000")))
+	assert.NoError(t, f.UnmarshalText([]byte("-1")))
+	assert.Equal(t, "short", f.Which())
+	assert.NoError(t, f.UnmarshalText([]byte("ground")))
+	assert.Equal(t, "string", f.Which())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<address xmlns="urn:caementarii:simple" building="-1">
+    <street>Baker Street</street>
+    <floor>ground</floor>
+    <postcode>NW1 6XE</postcode>
+</address>`
+	out := Address{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	basement := int16(-1)
+	ground := Floor2Ground
+	postcode := UkPostcode("NW1 6XE")
+	assert.Equal(t, &Building{Floor: &Floor{Short: &basement}}, out.Building)
+	assert.Equal(t, "Baker Street", out.Street)
+	assert.Equal(t, &Floor{Floor2: &ground}, out.Floor)
+	assert.Equal(t, Postcode{UkPostcode: &postcode}, out.Postcode)
+}
+
+func TestMarshaler(t *testing.T) {
+	zip := UsZip("10001")
+	floor := int16(3)
+	building := uint(221)
+	in := Address{
+		Building: &Building{PositiveInteger: &building},
+		Street:   "Fifth Avenue",
+		Floor:    &Floor{Short: &floor},
+		Postcode: Postcode{UsZip: &zip},
+	}
+
+	out, err := xml.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `<address xmlns="urn:caementarii:simple" building="221"><street>Fifth Avenue</street><floor>3</floor><postcode>10001</postcode></address>`, string(out))
+
+	_, err = xml.Marshal(Address{Street: "Fifth Avenue"})
+	assert.Error(t, err)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, in := range []string{
+		`<address xmlns="urn:caementarii:simple"><street>Baker Street</street><postcode>NW1</postcode></address>`,
+		`<address xmlns="urn:caementarii:simple"><street>Baker Street</street><floor>roof</floor><postcode>none</postcode></address>`,
+	} {
+		out := Address{}
+		assert.Error(t, xml.Unmarshal([]byte(in), &out), in)
+	}
+}
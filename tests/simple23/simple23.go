@@ -0,0 +1,285 @@
+package simple23
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+	"strconv"
+	"strings"
+)
+
+type Holidays []xs.Date
+
+func (t Holidays) MarshalText() ([]byte, error) {
+	items := make([]string, 0, len(t))
+	for _, x := range t {
+		text, err := x.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, string(text))
+	}
+	return []byte(strings.Join(items, " ")), nil
+}
+
+func (t *Holidays) UnmarshalText(text []byte) error {
+	fields := xs.Fields(string(text))
+	v := make(Holidays, 0, len(fields))
+	for _, s := range fields {
+		var x xs.Date
+		if err := x.UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("Holidays: %q is not a valid item: %w", s, err)
+		}
+		v = append(v, x)
+	}
+	*t = v
+	return nil
+}
+
+type Rgb []uint8
+
+func (t Rgb) Validate() error {
+	if n := len(t); n != 3 {
+		return fmt.Errorf("Rgb: list has %d items, but it must have 3", n)
+	}
+	return nil
+}
+
+func (t Rgb) MarshalText() ([]byte, error) {
+	items := make([]string, 0, len(t))
+	for _, x := range t {
+		items = append(items, strconv.FormatUint(uint64(x), 10))
+	}
+	return []byte(strings.Join(items, " ")), nil
+}
+
+func (t *Rgb) UnmarshalText(text []byte) error {
+	fields := xs.Fields(string(text))
+	v := make(Rgb, 0, len(fields))
+	for _, s := range fields {
+		x, err := strconv.ParseUint(s, 10, 8)
+		if err != nil {
+			return fmt.Errorf("Rgb: %q is not a valid item: %w", s, err)
+		}
+		v = append(v, uint8(x))
+	}
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Schedule struct {
+	XMLName  xml.Name    `xml:"urn:caementarii:simple schedule"`
+	Color    *Rgb        `xml:"color,attr,omitempty"`
+	Tags     *Tags       `xml:"tags,attr,omitempty"`
+	Days     WorkingDays `xml:"days"`
+	Slots    *Slots      `xml:"slots"`
+	Holidays *Holidays   `xml:"holidays"`
+}
+
+type Slots []Slots2
+
+func (t Slots) MarshalText() ([]byte, error) {
+	items := make([]string, 0, len(t))
+	for _, x := range t {
+		text, err := x.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, string(text))
+	}
+	return []byte(strings.Join(items, " ")), nil
+}
+
+func (t *Slots) UnmarshalText(text []byte) error {
+	fields := xs.Fields(string(text))
+	v := make(Slots, 0, len(fields))
+	for _, s := range fields {
+		var x Slots2
+		if err := x.UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("Slots: %q is not a valid item: %w", s, err)
+		}
+		v = append(v, x)
+	}
+	*t = v
+	return nil
+}
+
+type Slots2 struct {
+	Date   *xs.Date
+	Slots3 *Slots3
+}
+
+func (t Slots2) Which() string {
+	switch {
+	case t.Date != nil:
+		return "date"
+	case t.Slots3 != nil:
+		return "token"
+	}
+	return ""
+}
+
+func (t Slots2) MarshalText() ([]byte, error) {
+	switch {
+	case t.Date != nil:
+		return t.Date.MarshalText()
+	case t.Slots3 != nil:
+		return []byte(*t.Slots3), nil
+	}
+	return nil, fmt.Errorf("Slots2: no member value is set")
+}
+
+func (t *Slots2) UnmarshalText(text []byte) error {
+	var m1 xs.Date
+	if err := m1.UnmarshalText(text); err == nil {
+		*t = Slots2{Date: &m1}
+		return nil
+	}
+	var m2 Slots3
+	if err := m2.UnmarshalText(text); err == nil {
+		*t = Slots2{Slots3: &m2}
+		return nil
+	}
+	return fmt.Errorf("Slots2: %q is not a valid value of any member type", text)
+}
+
+type Slots3 string
+
+const (
+	Slots3Any Slots3 = "any"
+)
+
+func (t Slots3) IsValid() bool {
+	switch t {
+	case Slots3Any:
+		return true
+	}
+	return false
+}
+
+func (t Slots3) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Slots3: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Slots3) UnmarshalText(text []byte) error {
+	v := Slots3(xs.Collapse(string(text)))
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Tags []string
+
+func (t Tags) MarshalText() ([]byte, error) {
+	return []byte(strings.Join(t, " ")), nil
+}
+
+func (t *Tags) UnmarshalText(text []byte) error {
+	fields := xs.Fields(string(text))
+	v := Tags(fields)
+	*t = v
+	return nil
+}
+
+type Weekday string
+
+const (
+	WeekdayMon Weekday = "Mon"
+	WeekdayTue Weekday = "Tue"
+	WeekdayWed Weekday = "Wed"
+	WeekdayThu Weekday = "Thu"
+	WeekdayFri Weekday = "Fri"
+)
+
+func (t Weekday) IsValid() bool {
+	switch t {
+	case WeekdayMon, WeekdayTue, WeekdayWed, WeekdayThu, WeekdayFri:
+		return true
+	}
+	return false
+}
+
+func (t Weekday) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Weekday: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Weekday) UnmarshalText(text []byte) error {
+	v := Weekday(xs.Collapse(string(text)))
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Weekdays []Weekday
+
+func (t Weekdays) MarshalText() ([]byte, error) {
+	items := make([]string, 0, len(t))
+	for _, x := range t {
+		items = append(items, string(x))
+	}
+	return []byte(strings.Join(items, " ")), nil
+}
+
+func (t *Weekdays) UnmarshalText(text []byte) error {
+	fields := xs.Fields(string(text))
+	v := make(Weekdays, 0, len(fields))
+	for _, s := range fields {
+		var x Weekday
+		if err := x.UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("Weekdays: %q is not a valid item: %w", s, err)
+		}
+		v = append(v, x)
+	}
+	*t = v
+	return nil
+}
+
+type WorkingDays []Weekday
+
+func (t WorkingDays) Validate() error {
+	if n := len(t); n < 1 {
+		return fmt.Errorf("WorkingDays: list has %d items, but it must have at least 1", n)
+	}
+	if n := len(t); n > 3 {
+		return fmt.Errorf("WorkingDays: list has %d items, but it must have at most 3", n)
+	}
+	return nil
+}
+
+func (t WorkingDays) MarshalText() ([]byte, error) {
+	items := make([]string, 0, len(t))
+	for _, x := range t {
+		items = append(items, string(x))
+	}
+	return []byte(strings.Join(items, " ")), nil
+}
+
+func (t *WorkingDays) UnmarshalText(text []byte) error {
+	fields := xs.Fields(string(text))
+	v := make(WorkingDays, 0, len(fields))
+	for _, s := range fields {
+		var x Weekday
+		if err := x.UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("WorkingDays: %q is not a valid item: %w", s, err)
+		}
+		v = append(v, x)
+	}
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
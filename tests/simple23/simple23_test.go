@@ -0,0 +1,111 @@
+package simple23
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xs"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple23(t *testing.T) {
+	data, err := os.ReadFile("simple23.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple23",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple23.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, WorkingDays{WeekdayMon}.Validate())
+	assert.Error(t, WorkingDays{}.Validate())
+	assert.Error(t, WorkingDays{WeekdayMon, WeekdayTue, WeekdayWed, WeekdayThu}.Validate())
+
+	assert.NoError(t, Rgb{255, 128, 0}.Validate())
+	assert.Error(t, Rgb{255, 128}.Validate())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<schedule xmlns="urn:caementarii:simple" color=" 255  128 0 " tags="x y">
+    <days>
+        Mon
+        Wed
+    </days>
+    <slots>2024-05-01 any</slots>
+    <holidays>2024-12-25 2024-12-26</holidays>
+</schedule>`
+	out := Schedule{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	anySlot := Slots3Any
+	date, _ := xs.ParseDate("2024-05-01")
+	christmas, _ := xs.ParseDate("2024-12-25")
+	boxingDay, _ := xs.ParseDate("2024-12-26")
+	assert.Equal(t, &Rgb{255, 128, 0}, out.Color)
+	assert.Equal(t, &Tags{"x", "y"}, out.Tags)
+	assert.Equal(t, WorkingDays{WeekdayMon, WeekdayWed}, out.Days)
+	assert.Equal(t, &Slots{{Date: &date}, {Slots3: &anySlot}}, out.Slots)
+	assert.Equal(t, &Holidays{christmas, boxingDay}, out.Holidays)
+}
+
+func TestMarshaler(t *testing.T) {
+	date, _ := xs.ParseDate("2024-05-01")
+	in := Schedule{
+		Color: &Rgb{0, 0, 255},
+		Tags:  &Tags{"a", "b"},
+		Days:  WorkingDays{WeekdayTue, WeekdayFri},
+		Slots: &Slots{{Date: &date}},
+	}
+
+	out, err := xml.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `<schedule xmlns="urn:caementarii:simple" color="0 0 255" tags="a b"><days>Tue Fri</days><slots>2024-05-01</slots></schedule>`, string(out))
+}
+
+func TestUnmarshalNBSP(t *testing.T) {
+	// Items are separated by XML whitespace only, so NBSP is kept in an item.
+	out := Schedule{}
+	assert.NoError(t, xml.Unmarshal([]byte(`<schedule xmlns="urn:caementarii:simple" tags="a&#xA0;b&#x9;c"><days>Mon</days></schedule>`), &out))
+	assert.Equal(t, &Tags{"a\u00a0b", "c"}, out.Tags)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, in := range []string{
+		`<schedule xmlns="urn:caementarii:simple"><days></days></schedule>`,
+		`<schedule xmlns="urn:caementarii:simple"><days>Mon Sat</days></schedule>`,
+		`<schedule xmlns="urn:caementarii:simple"><days>Mon Tue Wed Thu</days></schedule>`,
+		`<schedule xmlns="urn:caementarii:simple" color="1 2"><days>Mon</days></schedule>`,
+		`<schedule xmlns="urn:caementarii:simple" color="1 2 256"><days>Mon</days></schedule>`,
+		`<schedule xmlns="urn:caementarii:simple"><days>Mon</days><slots>never</slots></schedule>`,
+		// NBSP doesn't separate items.
+		`<schedule xmlns="urn:caementarii:simple"><days>Mon&#xA0;Wed</days></schedule>`,
+	} {
+		out := Schedule{}
+		assert.Error(t, xml.Unmarshal([]byte(in), &out), in)
+	}
+}
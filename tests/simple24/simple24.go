@@ -0,0 +1,227 @@
+package simple24
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var patternCurrency = regexp.MustCompile(`^(?:[A-Z]{3})$`)
+
+type Currency string
+
+func (t Currency) Validate() error {
+	if !patternCurrency.MatchString(string(t)) {
+		return fmt.Errorf("Currency: %q does not match the pattern [A-Z]{3}", string(t))
+	}
+	return nil
+}
+
+func (t *Currency) UnmarshalText(text []byte) error {
+	v := Currency(xs.Collapse(string(text)))
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Dimension struct {
+	Measure
+	Axis *string `xml:"axis,attr,omitempty"`
+}
+
+type Grade struct {
+	Unit  string `xml:"unit,attr"`
+	Value Grade2 `xml:",chardata"`
+}
+
+type Grade2 int
+
+func (t Grade2) Validate() error {
+	if t > 10 {
+		return fmt.Errorf("Grade2: %v must be at most 10", t)
+	}
+	return nil
+}
+
+func (t *Grade2) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	x, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("Grade2: %q is not a valid value: %w", text, err)
+	}
+	v := Grade2(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Measure struct {
+	Unit  string     `xml:"unit,attr"`
+	Value xs.Decimal `xml:",chardata"`
+}
+
+type MeasureValue interface {
+	isMeasure()
+}
+
+func (Measure) isMeasure() {}
+
+func (Dimension) isMeasure() {}
+
+func (Grade) isMeasure() {}
+
+func (Weight) isMeasure() {}
+
+type AnyMeasure struct {
+	Value MeasureValue
+}
+
+func (t *AnyMeasure) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	name, ok, err := xsiType(&start)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		name = xml.Name{Space: "urn:caementarii:simple", Local: "measure"}
+	}
+	switch name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "measure"}:
+		var v Measure
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "dimension"}:
+		var v Dimension
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "grade"}:
+		var v Grade
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	case xml.Name{Space: "urn:caementarii:simple", Local: "weight"}:
+		var v Weight
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		t.Value = v
+		return nil
+	}
+	return fmt.Errorf("xsi:type {%s}%s is not allowed for measure", name.Space, name.Local)
+}
+
+func (t AnyMeasure) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch v := t.Value.(type) {
+	case nil:
+		return nil
+	case Measure, *Measure:
+		return e.EncodeElement(v, start)
+	case Dimension, *Dimension:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "dimension", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	case Grade, *Grade:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "grade", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	case Weight, *Weight:
+		start.Attr = append(start.Attr, xsiTypeAttrs(start.Name.Space, "urn:caementarii:simple", "weight", "ns1iafv9g")...)
+		return e.EncodeElement(v, start)
+	}
+	return fmt.Errorf("%T is not allowed for measure", t.Value)
+}
+
+type Product struct {
+	XMLName xml.Name `xml:"urn:caementarii:simple product"`
+	Price   struct {
+		Currency Currency   `xml:"currency,attr"`
+		Value    xs.Decimal `xml:",chardata"`
+	} `xml:"price"`
+	Weight Weight     `xml:"weight"`
+	Width  *Dimension `xml:"width"`
+	Grade  *Grade     `xml:"grade"`
+}
+
+type Weight struct {
+	Unit  string  `xml:"unit,attr"`
+	Value Weight2 `xml:",chardata"`
+}
+
+type Weight2 xs.Decimal
+
+func (t Weight2) Validate() error {
+	if xs.Decimal(t).Cmp(xs.MustParseDecimal("0")) <= 0 {
+		return fmt.Errorf("Weight2: %q must be greater than 0", xs.Decimal(t).String())
+	}
+	return nil
+}
+
+func (t Weight2) MarshalText() ([]byte, error) {
+	return xs.Decimal(t).MarshalText()
+}
+
+func (t *Weight2) UnmarshalText(text []byte) error {
+	var x xs.Decimal
+	if err := x.UnmarshalText(text); err != nil {
+		return fmt.Errorf("Weight2: %q is not a valid value: %w", text, err)
+	}
+	v := Weight2(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// xsiType returns the type name of the xsi:type attribute of an element and removes the attribute.
+//
+// The decoder of encoding/xml doesn't report namespaces declared by ancestors of the element, so the
+// prefix of the name is resolved only by declarations of the element itself. Decode by xs.NewDecoder,
+// which copies them from the ancestors: with xml.NewDecoder or xml.Unmarshal a prefix declared by an
+// ancestor is an error "prefix ... is not declared", and a name without a prefix has no namespace
+// unless the element declares a default one.
+func xsiType(start *xml.StartElement) (xml.Name, bool, error) {
+	for i, attr := range start.Attr {
+		if attr.Name.Space != xsiNamespace || attr.Name.Local != "type" {
+			continue
+		}
+		start.Attr = append(start.Attr[:i:i], start.Attr[i+1:]...)
+		prefix, local, found := strings.Cut(strings.TrimSpace(attr.Value), ":")
+		if !found {
+			prefix, local = "", prefix
+		}
+		for _, ns := range start.Attr {
+			if (prefix != "" && ns.Name.Space == "xmlns" && ns.Name.Local == prefix) || (prefix == "" && ns.Name.Space == "" && ns.Name.Local == "xmlns") {
+				return xml.Name{Space: ns.Value, Local: local}, true, nil
+			}
+		}
+		if prefix != "" {
+			return xml.Name{}, true, fmt.Errorf("xsi:type %q: prefix %s is not declared", attr.Value, prefix)
+		}
+		return xml.Name{Local: local}, true, nil
+	}
+	return xml.Name{}, false, nil
+}
+
+func xsiTypeAttrs(element string, space string, local string, prefix string) []xml.Attr {
+	attr := xml.Attr{Name: xml.Name{Space: xsiNamespace, Local: "type"}, Value: local}
+	if space == "" || space == element {
+		return []xml.Attr{attr}
+	}
+	attr.Value = prefix + ":" + local
+	return []xml.Attr{{Name: xml.Name{Local: "xmlns:" + prefix}, Value: space}, attr}
+}
@@ -0,0 +1,91 @@
+package simple24
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xs"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple24(t *testing.T) {
+	data, err := os.ReadFile("simple24.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple24",
+		Decimal: goxsd.DecimalExact,
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple24.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Weight2(xs.MustParseDecimal("0.5")).Validate())
+	assert.Error(t, Weight2(xs.MustParseDecimal("0")).Validate())
+
+	assert.NoError(t, Grade2(10).Validate())
+	assert.Error(t, Grade2(11).Validate())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	in := `<product xmlns="urn:caementarii:simple">
+    <price currency="EUR">12.50</price>
+    <weight unit="kg">0.75</weight>
+    <width unit="cm" axis="x">30</width>
+    <grade unit="point">7</grade>
+</product>`
+	out := Product{}
+
+	e := xml.Unmarshal([]byte(in), &out)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	axis := "x"
+	assert.Equal(t, Currency("EUR"), out.Price.Currency)
+	assert.Equal(t, "12.50", out.Price.Value.String())
+	assert.Equal(t, Weight{Unit: "kg", Value: Weight2(xs.MustParseDecimal("0.75"))}, out.Weight)
+	assert.Equal(t, &Dimension{Measure: Measure{Unit: "cm", Value: xs.MustParseDecimal("30")}, Axis: &axis}, out.Width)
+	assert.Equal(t, &Grade{Unit: "point", Value: 7}, out.Grade)
+}
+
+func TestMarshaler(t *testing.T) {
+	in := Product{Weight: Weight{Unit: "g", Value: Weight2(xs.MustParseDecimal("250"))}}
+	in.Price.Currency = "USD"
+	in.Price.Value = xs.MustParseDecimal("9.99")
+
+	out, err := xml.Marshal(in)
+	assert.NoError(t, err)
+	assert.Equal(t, `<product xmlns="urn:caementarii:simple"><price currency="USD">9.99</price><weight unit="g">250</weight></product>`, string(out))
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	for _, in := range []string{
+		`<product xmlns="urn:caementarii:simple"><price currency="EUR">free</price><weight unit="kg">1</weight></product>`,
+		`<product xmlns="urn:caementarii:simple"><price currency="euro">1</price><weight unit="kg">1</weight></product>`,
+		`<product xmlns="urn:caementarii:simple"><price currency="EUR">1</price><weight unit="kg">0</weight></product>`,
+		`<product xmlns="urn:caementarii:simple"><price currency="EUR">1</price><weight unit="kg">1</weight><grade unit="point">11</grade></product>`,
+	} {
+		out := Product{}
+		assert.Error(t, xml.Unmarshal([]byte(in), &out), in)
+	}
+}
@@ -0,0 +1,241 @@
+package simple25
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+type Empty struct {
+	XMLName xml.Name    `xml:"urn:caementarii:simple empty"`
+	Lang    *string     `xml:"lang,attr,omitempty"`
+	Items   []EmptyItem `xml:",any"`
+}
+
+func (t *Empty) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Empty
+	if err := xml.NewTokenDecoder(&xmlTokens{start, start.End()}).Decode((*alias)(t)); err != nil {
+		return err
+	}
+	t.Items = nil
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.CharData:
+			if n := len(t.Items); n > 0 && t.Items[n-1].Which() == "" {
+				t.Items[n-1].Text += string(tok)
+			} else {
+				t.Items = append(t.Items, EmptyItem{Text: string(tok)})
+			}
+		case xml.StartElement:
+			var item EmptyItem
+			if err := item.UnmarshalXML(d, tok); err != nil {
+				return err
+			}
+			t.Items = append(t.Items, item)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type EmptyItem struct {
+	Text string
+}
+
+func (t EmptyItem) Which() string {
+	return ""
+}
+
+func (t *EmptyItem) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.Skip()
+}
+
+func (t EmptyItem) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeToken(xml.CharData(t.Text))
+}
+
+type Inline struct {
+	Items []InlineItem `xml:",any"`
+}
+
+func (t *Inline) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Inline
+	if err := xml.NewTokenDecoder(&xmlTokens{start, start.End()}).Decode((*alias)(t)); err != nil {
+		return err
+	}
+	t.Items = nil
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.CharData:
+			if n := len(t.Items); n > 0 && t.Items[n-1].Which() == "" {
+				t.Items[n-1].Text += string(tok)
+			} else {
+				t.Items = append(t.Items, InlineItem{Text: string(tok)})
+			}
+		case xml.StartElement:
+			var item InlineItem
+			if err := item.UnmarshalXML(d, tok); err != nil {
+				return err
+			}
+			t.Items = append(t.Items, item)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type InlineItem struct {
+	Text   string
+	Em     *Inline
+	Strong *Inline
+	Code   *string
+}
+
+func (t InlineItem) Which() string {
+	switch {
+	case t.Em != nil:
+		return "em"
+	case t.Strong != nil:
+		return "strong"
+	case t.Code != nil:
+		return "code"
+	}
+	return ""
+}
+
+func (t *InlineItem) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch {
+	case start.Name.Local == "em":
+		return d.DecodeElement(&t.Em, &start)
+	case start.Name.Local == "strong":
+		return d.DecodeElement(&t.Strong, &start)
+	case start.Name.Local == "code":
+		return d.DecodeElement(&t.Code, &start)
+	}
+	return d.Skip()
+}
+
+func (t InlineItem) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch {
+	case t.Em != nil:
+		return e.EncodeElement(t.Em, xml.StartElement{Name: xml.Name{Space: "", Local: "em"}})
+	case t.Strong != nil:
+		return e.EncodeElement(t.Strong, xml.StartElement{Name: xml.Name{Space: "", Local: "strong"}})
+	case t.Code != nil:
+		return e.EncodeElement(t.Code, xml.StartElement{Name: xml.Name{Space: "", Local: "code"}})
+	}
+	return e.EncodeToken(xml.CharData(t.Text))
+}
+
+var nsNoteQName = xml.Name{Space: "urn:caementarii:simple", Local: "note"}
+
+type Note Inline
+
+func (t *Note) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*Inline)(t), &start)
+}
+
+func (t Note) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsNoteQName
+	return e.EncodeElement(Inline(t), start)
+}
+
+type Para struct {
+	XMLName xml.Name   `xml:"urn:caementarii:simple para"`
+	ID      *string    `xml:"id,attr,omitempty"`
+	Items   []ParaItem `xml:",any"`
+}
+
+func (t *Para) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Para
+	if err := xml.NewTokenDecoder(&xmlTokens{start, start.End()}).Decode((*alias)(t)); err != nil {
+		return err
+	}
+	t.Items = nil
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.CharData:
+			if n := len(t.Items); n > 0 && t.Items[n-1].Which() == "" {
+				t.Items[n-1].Text += string(tok)
+			} else {
+				t.Items = append(t.Items, ParaItem{Text: string(tok)})
+			}
+		case xml.StartElement:
+			var item ParaItem
+			if err := item.UnmarshalXML(d, tok); err != nil {
+				return err
+			}
+			t.Items = append(t.Items, item)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+type ParaItem struct {
+	Text   string
+	Em     *Inline
+	Strong *Inline
+	Link   *struct {
+		Href  string `xml:"href,attr"`
+		Value string `xml:",chardata"`
+	}
+}
+
+func (t ParaItem) Which() string {
+	switch {
+	case t.Em != nil:
+		return "em"
+	case t.Strong != nil:
+		return "strong"
+	case t.Link != nil:
+		return "link"
+	}
+	return ""
+}
+
+func (t *ParaItem) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch {
+	case start.Name.Local == "em":
+		return d.DecodeElement(&t.Em, &start)
+	case start.Name.Local == "strong":
+		return d.DecodeElement(&t.Strong, &start)
+	case start.Name.Local == "link":
+		return d.DecodeElement(&t.Link, &start)
+	}
+	return d.Skip()
+}
+
+func (t ParaItem) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch {
+	case t.Em != nil:
+		return e.EncodeElement(t.Em, xml.StartElement{Name: xml.Name{Space: "", Local: "em"}})
+	case t.Strong != nil:
+		return e.EncodeElement(t.Strong, xml.StartElement{Name: xml.Name{Space: "", Local: "strong"}})
+	case t.Link != nil:
+		return e.EncodeElement(t.Link, xml.StartElement{Name: xml.Name{Space: "", Local: "link"}})
+	}
+	return e.EncodeToken(xml.CharData(t.Text))
+}
+
+type xmlTokens []xml.Token
+
+func (t *xmlTokens) Token() (xml.Token, error) {
+	if len(*t) == 0 {
+		return nil, io.EOF
+	}
+	tok := (*t)[0]
+	*t = (*t)[1:]
+	return tok, nil
+}
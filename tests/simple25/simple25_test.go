@@ -0,0 +1,100 @@
+package simple25
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple25(t *testing.T) {
+	data, err := os.ReadFile("simple25.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple25",
+		Mixed:   goxsd.MixedOrdered,
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple25.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	var p Para
+	err := xml.Unmarshal([]byte(`<para xmlns="urn:caementarii:simple" id="p1">Read <em>the <strong>fine</strong> manual</em> at <link href="https://example.com">example</link>.</para>`), &p)
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", *p.ID)
+	if assert.Equal(t, 5, len(p.Items)) {
+		assert.Equal(t, "", p.Items[0].Which())
+		assert.Equal(t, "Read ", p.Items[0].Text)
+		assert.Equal(t, "em", p.Items[1].Which())
+		assert.Equal(t, []InlineItem{
+			{Text: "the "},
+			{Strong: &Inline{Items: []InlineItem{{Text: "fine"}}}},
+			{Text: " manual"},
+		}, p.Items[1].Em.Items)
+		assert.Equal(t, " at ", p.Items[2].Text)
+		assert.Equal(t, "link", p.Items[3].Which())
+		assert.Equal(t, "https://example.com", p.Items[3].Link.Href)
+		assert.Equal(t, "example", p.Items[3].Link.Value)
+		assert.Equal(t, ".", p.Items[4].Text)
+	}
+
+	var n Note
+	err = xml.Unmarshal([]byte(`<note xmlns="urn:caementarii:simple">a<!-- comment -->b<unknown>skipped</unknown><code>x &lt; y</code></note>`), &n)
+	assert.NoError(t, err)
+	assert.Equal(t, []InlineItem{{Text: "ab"}, {}, {Code: xsstring("x < y")}}, n.Items)
+
+	var e Empty
+	err = xml.Unmarshal([]byte(`<empty xmlns="urn:caementarii:simple" lang="en">just text</empty>`), &e)
+	assert.NoError(t, err)
+	assert.Equal(t, "en", *e.Lang)
+	assert.Equal(t, []EmptyItem{{Text: "just text"}}, e.Items)
+}
+
+func TestMarshaler(t *testing.T) {
+	p := Para{Items: []ParaItem{
+		{Text: "Read "},
+		{Em: &Inline{Items: []InlineItem{{Text: "the "}, {Strong: &Inline{Items: []InlineItem{{Text: "fine"}}}}, {Text: " manual"}}}},
+		{Text: " & enjoy"},
+	}}
+	data, err := xml.Marshal(p)
+	assert.NoError(t, err)
+	assert.Equal(t, `<para xmlns="urn:caementarii:simple">Read <em>the <strong>fine</strong> manual</em> &amp; enjoy</para>`, string(data))
+
+	n := Note{Items: []InlineItem{{Text: "use "}, {Code: xsstring("go vet")}}}
+	data, err = xml.Marshal(n)
+	assert.NoError(t, err)
+	assert.Equal(t, `<note xmlns="urn:caementarii:simple">use <code>go vet</code></note>`, string(data))
+}
+
+func TestRoundTrip(t *testing.T) {
+	src := `<para xmlns="urn:caementarii:simple" id="p2">one <strong>two</strong> three <em>four</em></para>`
+	var p Para
+	assert.NoError(t, xml.Unmarshal([]byte(src), &p))
+	data, err := xml.Marshal(p)
+	assert.NoError(t, err)
+	assert.Equal(t, src, string(data))
+}
+
+func xsstring(s string) *string {
+	return &s
+}
@@ -0,0 +1,186 @@
+package simple26
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+)
+
+type Any struct {
+	Value interface{}
+}
+
+func (t *Any) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "data"}:
+		v := new(Data)
+		t.Value = v
+		return d.DecodeElement(v, &start)
+	case xml.Name{Space: "urn:caementarii:simple", Local: "envelope"}:
+		v := new(Envelope)
+		t.Value = v
+		return d.DecodeElement(v, &start)
+	case xml.Name{Space: "urn:caementarii:simple", Local: "item"}:
+		v := new(Item)
+		t.Value = v
+		return d.DecodeElement(v, &start)
+	case xml.Name{Space: "urn:caementarii:simple", Local: "note"}:
+		v := new(Note)
+		t.Value = v
+		return d.DecodeElement(v, &start)
+	}
+	v := new(xs.AnyElement)
+	t.Value = v
+	return d.DecodeElement(v, &start)
+}
+
+func (t Any) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if t.Value == nil {
+		return nil
+	}
+	return e.Encode(t.Value)
+}
+
+type Data struct {
+	XMLName  xml.Name   `xml:"urn:caementarii:simple data"`
+	AnyAttrs []xml.Attr `xml:",any,attr"`
+	Any      []Any      `xml:",any"`
+}
+
+func (t *Data) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	attrs := make([]xml.Attr, 0, len(start.Attr))
+	for _, a := range start.Attr {
+		if a.Name.Space != "xmlns" && !(a.Name.Space == "" && a.Name.Local == "xmlns") {
+			attrs = append(attrs, a)
+		}
+	}
+	start.Attr = attrs
+	type alias Data
+	return d.DecodeElement((*alias)(t), &start)
+}
+
+type Envelope struct {
+	XMLName xml.Name `xml:"urn:caementarii:simple envelope"`
+	Header  *struct {
+		Any []xs.AnyElement `xml:",any"`
+	} `xml:"header"`
+	Body struct {
+		Any EnvelopeAny `xml:",any"`
+	} `xml:"body"`
+	Status struct {
+		Code *int32        `xml:"code"`
+		Any  *EnvelopeAny2 `xml:",any"`
+	} `xml:"status"`
+}
+
+type EnvelopeAny struct {
+	Value interface{}
+}
+
+func (t *EnvelopeAny) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name.Space {
+	case "urn:caementarii:simple":
+	default:
+		return fmt.Errorf("element %s of namespace %q is not allowed in EnvelopeAny", start.Name.Local, start.Name.Space)
+	}
+	switch start.Name {
+	case xml.Name{Space: "urn:caementarii:simple", Local: "data"}:
+		v := new(Data)
+		t.Value = v
+		return d.DecodeElement(v, &start)
+	case xml.Name{Space: "urn:caementarii:simple", Local: "envelope"}:
+		v := new(Envelope)
+		t.Value = v
+		return d.DecodeElement(v, &start)
+	case xml.Name{Space: "urn:caementarii:simple", Local: "item"}:
+		v := new(Item)
+		t.Value = v
+		return d.DecodeElement(v, &start)
+	case xml.Name{Space: "urn:caementarii:simple", Local: "note"}:
+		v := new(Note)
+		t.Value = v
+		return d.DecodeElement(v, &start)
+	}
+	return fmt.Errorf("element %s of namespace %q is not declared", start.Name.Local, start.Name.Space)
+}
+
+func (t EnvelopeAny) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if t.Value == nil {
+		return nil
+	}
+	return e.Encode(t.Value)
+}
+
+type EnvelopeAny2 struct {
+	Value interface{}
+}
+
+func (t *EnvelopeAny2) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name.Space {
+	case "urn:caementarii:ext":
+	default:
+		return fmt.Errorf("element %s of namespace %q is not allowed in EnvelopeAny2", start.Name.Local, start.Name.Space)
+	}
+	v := new(xs.AnyElement)
+	t.Value = v
+	return d.DecodeElement(v, &start)
+}
+
+func (t EnvelopeAny2) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if t.Value == nil {
+		return nil
+	}
+	return e.Encode(t.Value)
+}
+
+type Extensible struct {
+	Name string          `xml:"name"`
+	Any  []ExtensibleAny `xml:",any"`
+}
+
+type ExtensibleAny struct {
+	Value interface{}
+}
+
+func (t *ExtensibleAny) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name.Space {
+	case "", "urn:caementarii:simple":
+		return fmt.Errorf("element %s of namespace %q is not allowed in ExtensibleAny", start.Name.Local, start.Name.Space)
+	}
+	v := new(xs.AnyElement)
+	t.Value = v
+	return d.DecodeElement(v, &start)
+}
+
+func (t ExtensibleAny) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if t.Value == nil {
+		return nil
+	}
+	return e.Encode(t.Value)
+}
+
+var nsItemQName = xml.Name{Space: "urn:caementarii:simple", Local: "item"}
+
+type Item Extensible
+
+func (t *Item) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*Extensible)(t), &start)
+}
+
+func (t Item) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsItemQName
+	return e.EncodeElement(Extensible(t), start)
+}
+
+var nsNoteQName = xml.Name{Space: "urn:caementarii:simple", Local: "note"}
+
+type Note string
+
+func (t *Note) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*string)(t), &start)
+}
+
+func (t Note) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsNoteQName
+	return e.EncodeElement(string(t), start)
+}
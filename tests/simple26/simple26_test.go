@@ -0,0 +1,100 @@
+package simple26
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xs"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple26(t *testing.T) {
+	data, err := os.ReadFile("simple26.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple26",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple26.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestUnmarshaler(t *testing.T) {
+	var item Item
+	err := xml.Unmarshal([]byte(`<item xmlns="urn:caementarii:simple"><name>lamp</name><x:color xmlns:x="urn:caementarii:ext" x:shade="dark">red</x:color></item>`), &item)
+	assert.NoError(t, err)
+	assert.Equal(t, "lamp", item.Name)
+	if assert.Equal(t, 1, len(item.Any)) {
+		color := item.Any[0].Value.(*xs.AnyElement)
+		assert.Equal(t, xml.Name{Space: "urn:caementarii:ext", Local: "color"}, color.XMLName)
+		assert.Equal(t, []xml.Attr{{Name: xml.Name{Space: "urn:caementarii:ext", Local: "shade"}, Value: "dark"}}, color.Attr)
+		assert.Equal(t, []xml.Token{xml.CharData("red")}, color.Content)
+	}
+
+	var env Envelope
+	err = xml.Unmarshal([]byte(`<envelope xmlns="urn:caementarii:simple"><header><trace id="1"><hop/></trace></header><body><note>hello</note></body><status><e:failed xmlns:e="urn:caementarii:ext"/></status></envelope>`), &env)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(env.Header.Any)) {
+		assert.Equal(t, "trace", env.Header.Any[0].XMLName.Local)
+	}
+	note := Note("hello")
+	assert.Equal(t, &note, env.Body.Any.Value)
+	assert.Nil(t, env.Status.Code)
+	assert.Equal(t, "failed", env.Status.Any.Value.(*xs.AnyElement).XMLName.Local)
+
+	var data Data
+	err = xml.Unmarshal([]byte(`<data xmlns="urn:caementarii:simple" id="7"><note>known</note><other xmlns="urn:other">unknown</other></data>`), &data)
+	assert.NoError(t, err)
+	assert.Equal(t, []xml.Attr{{Name: xml.Name{Local: "id"}, Value: "7"}}, data.AnyAttrs)
+	if assert.Equal(t, 2, len(data.Any)) {
+		known := Note("known")
+		assert.Equal(t, &known, data.Any[0].Value)
+		assert.Equal(t, "other", data.Any[1].Value.(*xs.AnyElement).XMLName.Local)
+	}
+}
+
+func TestMarshaler(t *testing.T) {
+	item := Item{Name: "lamp", Any: []ExtensibleAny{{Value: &xs.AnyElement{
+		XMLName: xml.Name{Space: "urn:caementarii:ext", Local: "color"},
+		Content: []xml.Token{xml.CharData("red")},
+	}}}}
+	data, err := xml.Marshal(item)
+	assert.NoError(t, err)
+	assert.Equal(t, `<item xmlns="urn:caementarii:simple"><name>lamp</name><color xmlns="urn:caementarii:ext">red</color></item>`, string(data))
+
+	note := Note("hello")
+	env := Envelope{}
+	env.Body.Any.Value = &note
+	env.Status.Code = new(int32)
+	data, err = xml.Marshal(env)
+	assert.NoError(t, err)
+	assert.Equal(t, `<envelope xmlns="urn:caementarii:simple"><body><note xmlns="urn:caementarii:simple">hello</note></body><status><code>0</code></status></envelope>`, string(data))
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	var item Item
+	assert.Error(t, xml.Unmarshal([]byte(`<item xmlns="urn:caementarii:simple"><name>lamp</name><color>red</color></item>`), &item))
+
+	var env Envelope
+	assert.Error(t, xml.Unmarshal([]byte(`<envelope xmlns="urn:caementarii:simple"><body><unknown/></body><status><code>1</code></status></envelope>`), &env))
+	assert.Error(t, xml.Unmarshal([]byte(`<envelope xmlns="urn:caementarii:simple"><body><x:note xmlns:x="urn:other"/></body><status><code>1</code></status></envelope>`), &env))
+	assert.Error(t, xml.Unmarshal([]byte(`<envelope xmlns="urn:caementarii:simple"><body><note/></body><status><failed/></status></envelope>`), &env))
+}
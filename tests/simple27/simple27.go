@@ -0,0 +1,110 @@
+package simple27
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+)
+
+type Address struct {
+	City    string `xml:"city"`
+	Country Code   `xml:"country"`
+}
+
+var patternCode = regexp.MustCompile(`^(?:[A-Z]{2})$`)
+
+type Code string
+
+func (t Code) Validate() error {
+	if !patternCode.MatchString(string(t)) {
+		return fmt.Errorf("Code: %q does not match the pattern [A-Z]{2}", string(t))
+	}
+	return nil
+}
+
+func (t *Code) UnmarshalText(text []byte) error {
+	v := Code(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Contact struct {
+	Phone Phone `xml:"phone"`
+}
+
+type Directory struct {
+	XMLName xml.Name `xml:"urn:simple27 directory"`
+	Version *int32   `xml:"version,attr,omitempty"`
+	ID      *string  `xml:"id,attr,omitempty"`
+	Person  []Person `xml:"person"`
+	Contact *Contact `xml:"contact"`
+	Address *Address `xml:"address"`
+	Order   *Order   `xml:"urn:simple27 order"`
+}
+
+type Order struct {
+	XMLName xml.Name `xml:"urn:simple27 order"`
+	Status  *Status  `xml:"status,attr,omitempty"`
+	Code    Code     `xml:"code"`
+}
+
+type Person struct {
+	Name  string  `xml:"name"`
+	Age   *int32  `xml:"age"`
+	Note  *string `xml:"note"`
+	Email *string `xml:"email"`
+}
+
+var patternPhone = regexp.MustCompile(`^(?:\+[0-9]+)$`)
+
+type Phone string
+
+func (t Phone) Validate() error {
+	if !patternPhone.MatchString(string(t)) {
+		return fmt.Errorf("Phone: %q does not match the pattern \\+[0-9]+", string(t))
+	}
+	return nil
+}
+
+func (t *Phone) UnmarshalText(text []byte) error {
+	v := Phone(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Status string
+
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+)
+
+func (t Status) IsValid() bool {
+	switch t {
+	case StatusOpen, StatusClosed:
+		return true
+	}
+	return false
+}
+
+func (t Status) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Status: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Status) UnmarshalText(text []byte) error {
+	v := Status(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
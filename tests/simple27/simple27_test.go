@@ -0,0 +1,94 @@
+package simple27
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/resolver"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func readSchema(location string) (*xsd.Schema, error) {
+	data, err := os.ReadFile(location)
+	if err != nil {
+		return nil, err
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func TestSimple27(t *testing.T) {
+	r := resolver.New(nil)
+	s, err := r.Load("simple27.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName:        "simple27",
+		ImportResolver: r.Resolve,
+	}
+	err = g.Generate(s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple27.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestRedefinedNotFound(t *testing.T) {
+	var s xsd.Schema
+	err := xml.Unmarshal([]byte(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:simple27">
+		<xs:redefine schemaLocation="simple27-base.xsd"><xs:group name="Missing"><xs:sequence/></xs:group></xs:redefine>
+		<xs:element name="root" type="xs:string"/>
+	</xs:schema>`), &s)
+	assert.NoError(t, err)
+
+	g := goxsd.Generator{
+		PkgName: "simple27",
+		ImportResolver: func(namespace string, schemaLocation string) (*xsd.Schema, error) {
+			return readSchema(schemaLocation)
+		},
+	}
+	assert.Error(t, g.Generate(&s, new(bytes.Buffer)))
+}
+
+func TestUnmarshaler(t *testing.T) {
+	var d Directory
+	err := xml.Unmarshal([]byte(`<directory xmlns="urn:simple27" id="d1" version="2">
+		<person><name>Ann</name><age>30</age><note>admin</note><email>ann@example.com</email></person>
+		<contact><phone>+123</phone></contact>
+		<address><city>Riga</city><country>LV</country></address>
+		<order status="open"><code>EU</code></order>
+	</directory>`), &d)
+	assert.NoError(t, err)
+	assert.Equal(t, "d1", *d.ID)
+	assert.Equal(t, int32(2), *d.Version)
+	if assert.Equal(t, 1, len(d.Person)) {
+		assert.Equal(t, "Ann", d.Person[0].Name)
+		assert.Equal(t, int32(30), *d.Person[0].Age)
+		assert.Equal(t, "admin", *d.Person[0].Note)
+		assert.Equal(t, "ann@example.com", *d.Person[0].Email)
+	}
+	assert.Equal(t, Phone("+123"), d.Contact.Phone)
+	assert.Equal(t, Code("LV"), d.Address.Country)
+	assert.Equal(t, StatusOpen, *d.Order.Status)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	var d Directory
+	assert.Error(t, xml.Unmarshal([]byte(`<directory xmlns="urn:simple27"><person><name>Ann</name></person><contact><phone>123</phone></contact></directory>`), &d))
+	// The overridden enumeration is replaced.
+	assert.Error(t, xml.Unmarshal([]byte(`<directory xmlns="urn:simple27"><person><name>Ann</name></person><order status="new"><code>EU</code></order></directory>`), &d))
+}
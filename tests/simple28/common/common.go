@@ -0,0 +1,58 @@
+package common
+
+import (
+	"encoding/xml"
+	"fmt"
+	xmlns "github.com/realmfoo/caementarii/tests/simple28/xml"
+)
+
+type Address struct {
+	Space   *xmlns.Space `xml:"space,attr,omitempty"`
+	City    string       `xml:"city"`
+	Country CountryCode  `xml:"country"`
+}
+
+type CountryCode string
+
+const (
+	CountryCodeDE CountryCode = "DE"
+	CountryCodeFR CountryCode = "FR"
+	CountryCodeLV CountryCode = "LV"
+)
+
+func (t CountryCode) IsValid() bool {
+	switch t {
+	case CountryCodeDE, CountryCodeFR, CountryCodeLV:
+		return true
+	}
+	return false
+}
+
+func (t CountryCode) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("CountryCode: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *CountryCode) UnmarshalText(text []byte) error {
+	v := CountryCode(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+var nsNoteQName = xml.Name{Space: "urn:simple28:common", Local: "note"}
+
+type Note string
+
+func (t *Note) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*string)(t), &start)
+}
+
+func (t Note) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsNoteQName
+	return e.EncodeElement(string(t), start)
+}
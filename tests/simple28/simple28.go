@@ -0,0 +1,49 @@
+package simple28
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/tests/simple28/common"
+	xmlns "github.com/realmfoo/caementarii/tests/simple28/xml"
+	"github.com/realmfoo/caementarii/xs"
+	"strings"
+)
+
+type Countries []common.CountryCode
+
+func (t Countries) MarshalText() ([]byte, error) {
+	items := make([]string, 0, len(t))
+	for _, x := range t {
+		items = append(items, string(x))
+	}
+	return []byte(strings.Join(items, " ")), nil
+}
+
+func (t *Countries) UnmarshalText(text []byte) error {
+	fields := xs.Fields(string(text))
+	v := make(Countries, 0, len(fields))
+	for _, s := range fields {
+		var x common.CountryCode
+		if err := x.UnmarshalText([]byte(s)); err != nil {
+			return fmt.Errorf("Countries: %q is not a valid item: %w", s, err)
+		}
+		v = append(v, x)
+	}
+	*t = v
+	return nil
+}
+
+type Order struct {
+	XMLName      xml.Name           `xml:"urn:simple28:orders order"`
+	Lang         *xmlns.Lang        `xml:"lang,attr,omitempty"`
+	ShipTo       ShippingAddress    `xml:"shipTo"`
+	BillTo       *common.Address    `xml:"billTo"`
+	Origin       common.CountryCode `xml:"origin"`
+	Destinations *Countries         `xml:"destinations"`
+	Note         []common.Note      `xml:"urn:simple28:common note"`
+}
+
+type ShippingAddress struct {
+	common.Address
+	Recipient string `xml:"recipient"`
+}
@@ -0,0 +1,115 @@
+package simple28
+
+import (
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/resolver"
+	"github.com/realmfoo/caementarii/tests/simple28/common"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+const importPath = "github.com/realmfoo/caementarii/tests/simple28"
+
+func TestSimple28(t *testing.T) {
+	r := resolver.New(nil)
+	s, err := r.Load("simple28.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := goxsd.Generator{
+		PkgName:        "simple28",
+		ImportPath:     importPath,
+		ImportResolver: r.Resolve,
+	}
+	packages, err := g.GeneratePackages(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 3, len(packages))
+
+	for importPath, file := range map[string]string{
+		importPath:             "simple28.go",
+		importPath + "/common": "common/common.go",
+		importPath + "/xml":    "xml/xml.go",
+	} {
+		expected, _ := os.ReadFile(file)
+		assert.Equal(t, string(expected), string(packages[importPath]))
+	}
+}
+
+// schemas returns a resolver of schema documents by their locations.
+func schemas(documents map[string]string) func(string, string) (*xsd.Schema, error) {
+	return func(namespace string, schemaLocation string) (*xsd.Schema, error) {
+		s := xsd.Schema{}
+		err := xml.Unmarshal([]byte(documents[schemaLocation]), &s)
+		return &s, err
+	}
+}
+
+func TestImportCycle(t *testing.T) {
+	documents := map[string]string{
+		"a.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:b="urn:b" targetNamespace="urn:a">
+			<xs:import namespace="urn:b" schemaLocation="b.xsd"/>
+			<xs:complexType name="A"><xs:sequence><xs:element name="b" type="b:B"/></xs:sequence></xs:complexType>
+		</xs:schema>`,
+		"b.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:a="urn:a" targetNamespace="urn:b">
+			<xs:import namespace="urn:a" schemaLocation="a.xsd"/>
+			<xs:complexType name="B"><xs:sequence><xs:element name="a" type="a:A" minOccurs="0"/></xs:sequence></xs:complexType>
+		</xs:schema>`,
+	}
+	s, err := schemas(documents)("urn:a", "a.xsd")
+	assert.NoError(t, err)
+
+	g := goxsd.Generator{PkgName: "a", ImportPath: "example.com/a", ImportResolver: schemas(documents)}
+	_, err = g.GeneratePackages(s)
+	assert.Error(t, err)
+
+	// Namespaces which import each other could share a package.
+	g = goxsd.Generator{
+		PkgName:        "a",
+		ImportPath:     "example.com/a",
+		Packages:       map[string]string{"urn:b": "example.com/a"},
+		ImportResolver: schemas(documents),
+	}
+	packages, err := g.GeneratePackages(s)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(packages))
+	source := string(packages["example.com/a"])
+	assert.True(t, strings.Contains(source, "type A struct"))
+	assert.True(t, strings.Contains(source, "type B struct"))
+}
+
+func TestUnmarshaler(t *testing.T) {
+	var order Order
+	err := xml.Unmarshal([]byte(`<order xmlns="urn:simple28:orders" xmlns:c="urn:simple28:common">
+		<shipTo><city>Riga</city><country>LV</country><recipient>Ann</recipient></shipTo>
+		<origin>DE</origin>
+		<destinations>FR LV</destinations>
+		<c:note>fragile</c:note>
+	</order>`), &order)
+	assert.NoError(t, err)
+	assert.Equal(t, "Riga", order.ShipTo.City)
+	assert.Equal(t, common.CountryCodeLV, order.ShipTo.Country)
+	assert.Equal(t, "Ann", order.ShipTo.Recipient)
+	assert.Equal(t, common.CountryCodeDE, order.Origin)
+	assert.Equal(t, Countries{common.CountryCodeFR, common.CountryCodeLV}, *order.Destinations)
+	assert.Equal(t, []common.Note{"fragile"}, order.Note)
+
+	assert.Error(t, xml.Unmarshal([]byte(`<order xmlns="urn:simple28:orders"><shipTo><city>Riga</city><country>XX</country><recipient>Ann</recipient></shipTo><origin>DE</origin></order>`), &order))
+}
+
+func TestMarshaler(t *testing.T) {
+	order := Order{
+		ShipTo: ShippingAddress{Address: common.Address{City: "Riga", Country: common.CountryCodeLV}, Recipient: "Ann"},
+		Origin: common.CountryCodeDE,
+		Note:   []common.Note{"fragile"},
+	}
+	data, err := xml.Marshal(order)
+	assert.NoError(t, err)
+	assert.Equal(t, `<order xmlns="urn:simple28:orders"><shipTo><city>Riga</city><country>LV</country><recipient>Ann</recipient></shipTo><origin>DE</origin><note xmlns="urn:simple28:common">fragile</note></order>`, string(data))
+}
@@ -0,0 +1,96 @@
+package xml
+
+import (
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+)
+
+type Lang struct {
+	Lang2 *Lang2
+}
+
+func (t Lang) Which() string {
+	switch {
+	case t.Lang2 != nil:
+		return "string"
+	}
+	return ""
+}
+
+func (t Lang) MarshalText() ([]byte, error) {
+	switch {
+	case t.Lang2 != nil:
+		return []byte(*t.Lang2), nil
+	}
+	return nil, fmt.Errorf("Lang: no member value is set")
+}
+
+func (t *Lang) UnmarshalText(text []byte) error {
+	var m1 Lang2
+	if err := m1.UnmarshalText(text); err == nil {
+		*t = Lang{Lang2: &m1}
+		return nil
+	}
+	return fmt.Errorf("Lang: %q is not a valid value of any member type", text)
+}
+
+type Lang2 string
+
+const (
+	Lang2Empty Lang2 = ""
+)
+
+func (t Lang2) IsValid() bool {
+	switch t {
+	case Lang2Empty:
+		return true
+	}
+	return false
+}
+
+func (t Lang2) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Lang2: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Lang2) UnmarshalText(text []byte) error {
+	v := Lang2(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type Space string
+
+const (
+	SpaceDefault  Space = "default"
+	SpacePreserve Space = "preserve"
+)
+
+func (t Space) IsValid() bool {
+	switch t {
+	case SpaceDefault, SpacePreserve:
+		return true
+	}
+	return false
+}
+
+func (t Space) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Space: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Space) UnmarshalText(text []byte) error {
+	v := Space(xs.Collapse(string(text)))
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
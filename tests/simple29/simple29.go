@@ -0,0 +1,118 @@
+package simple29
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+)
+
+var nsNoteQName = xml.Name{Space: "urn:simple29", Local: "note"}
+
+type Note string
+
+func (t *Note) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*string)(t), &start)
+}
+
+func (t Note) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsNoteQName
+	return e.EncodeElement(string(t), start)
+}
+
+var nsNote2QName = xml.Name{Space: "urn:simple29:other", Local: "note"}
+
+type Note2 string
+
+func (t *Note2) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*string)(t), &start)
+}
+
+func (t Note2) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsNote2QName
+	return e.EncodeElement(string(t), start)
+}
+
+type OrderLine struct {
+	XMLName        xml.Name  `xml:"urn:simple29 order-line"`
+	Item           string    `xml:"Item"`
+	Item2          []string  `xml:"item"`
+	Type           Status    `xml:"type"`
+	Model          *X3dModel `xml:"model"`
+	Note           Note      `xml:"urn:simple29 note"`
+	Note2          *Note2    `xml:"urn:simple29:other note"`
+	XMLNameElement *string   `xml:"xmlName"`
+}
+
+var nsOrderLine2QName = xml.Name{Space: "urn:simple29:other", Local: "order_line"}
+
+type OrderLine2 string
+
+func (t *OrderLine2) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*string)(t), &start)
+}
+
+func (t OrderLine2) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsOrderLine2QName
+	return e.EncodeElement(string(t), start)
+}
+
+type Remark struct {
+	Note Note2 `xml:"urn:simple29:other note"`
+}
+
+type Status string
+
+const (
+	StatusOpen2   Status = "open"
+	StatusOnHold  Status = "on-hold"
+	StatusOnHold2 Status = "on_hold"
+	StatusClosed  Status = "closed"
+)
+
+func (t Status) IsValid() bool {
+	switch t {
+	case StatusOpen2, StatusOnHold, StatusOnHold2, StatusClosed:
+		return true
+	}
+	return false
+}
+
+func (t Status) Validate() error {
+	if !t.IsValid() {
+		return fmt.Errorf("Status: %q is not a valid value", string(t))
+	}
+	return nil
+}
+
+func (t *Status) UnmarshalText(text []byte) error {
+	v := Status(text)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+type StatusOpen struct {
+	Since xs.Date `xml:"since"`
+}
+
+var nsTypeQName = xml.Name{Space: "urn:simple29", Local: "type"}
+
+type Type Status
+
+func (t *Type) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*Status)(t), &start)
+}
+
+func (t Type) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = nsTypeQName
+	return e.EncodeElement(Status(t), start)
+}
+
+type X3dModel struct {
+	IDAttr string  `xml:"id,attr"`
+	UserID *string `xml:"userId,attr,omitempty"`
+	XMLURL string  `xml:"xmlUrl"`
+	ID     string  `xml:"id"`
+}
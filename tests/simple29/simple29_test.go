@@ -0,0 +1,111 @@
+package simple29
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/resolver"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+const importPath = "github.com/realmfoo/caementarii/tests/simple29"
+
+func TestSimple29(t *testing.T) {
+	r := resolver.New(nil)
+	s, err := r.Load("simple29.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both namespaces share the package, so their components of the same name are numbered.
+	g := goxsd.Generator{
+		PkgName:        "simple29",
+		ImportPath:     importPath,
+		Packages:       map[string]string{"urn:simple29:other": importPath},
+		ImportResolver: r.Resolve,
+	}
+	packages, err := g.GeneratePackages(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(packages))
+
+	expected, _ := os.ReadFile("simple29.go")
+	assert.Equal(t, string(expected), string(packages[importPath]))
+
+	// The output doesn't depend on the order of maps.
+	for i := 0; i < 5; i++ {
+		s, err := resolver.New(nil).Load("simple29.xsd")
+		assert.NoError(t, err)
+		g := goxsd.Generator{PkgName: "simple29", ImportPath: importPath, Packages: g.Packages, ImportResolver: r.Resolve}
+		packages, err := g.GeneratePackages(s)
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), string(packages[importPath]))
+	}
+}
+
+func TestNames(t *testing.T) {
+	r := resolver.New(nil)
+	s, err := r.Load("simple29.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := goxsd.Generator{
+		PkgName:        "simple29",
+		ImportResolver: r.Resolve,
+		Names: map[xml.Name]string{
+			{Space: "urn:simple29", Local: "order-line"}: "Line",
+			{Space: "urn:simple29", Local: "3dModel"}:    "model-3d",
+			{Local: "item"}:   "Items",
+			{Local: "userId"}: "Owner",
+		},
+	}
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Generate(s, buf))
+
+	// Fields are compared regardless of their alignment.
+	source := strings.Join(strings.Fields(buf.String()), " ")
+	assert.True(t, strings.Contains(source, "type Line struct {"), source)
+	assert.True(t, strings.Contains(source, "type Model3d struct {"), source)
+	assert.True(t, strings.Contains(source, "Model *Model3d `xml:\"model\"`"), source)
+	assert.True(t, strings.Contains(source, "Owner *string `xml:\"userId,attr,omitempty\"`"), source)
+	assert.True(t, strings.Contains(source, "ItemsElement []string `xml:\"item\"`"), source)
+}
+
+func TestUnmarshal(t *testing.T) {
+	data := `<order-line xmlns="urn:simple29" xmlns:o="urn:simple29:other">
+		<Item>first</Item>
+		<item>second</item>
+		<item>third</item>
+		<type>on_hold</type>
+		<model id="m1" userId="u1"><xmlUrl>http://example.com/m1</xmlUrl><id>42</id></model>
+		<note>mine</note>
+		<o:note>theirs</o:note>
+		<xmlName>x</xmlName>
+	</order-line>`
+
+	var out OrderLine
+	assert.NoError(t, xml.Unmarshal([]byte(data), &out))
+	assert.Equal(t, "first", out.Item)
+	assert.Equal(t, []string{"second", "third"}, out.Item2)
+	assert.Equal(t, StatusOnHold2, out.Type)
+	assert.Equal(t, "m1", out.Model.IDAttr)
+	assert.Equal(t, "u1", *out.Model.UserID)
+	assert.Equal(t, "http://example.com/m1", out.Model.XMLURL)
+	assert.Equal(t, "42", out.Model.ID)
+	assert.Equal(t, Note("mine"), out.Note)
+	assert.Equal(t, Note2("theirs"), *out.Note2)
+	assert.Equal(t, "x", *out.XMLNameElement)
+
+	// A field is named apart from the other fields of its struct only.
+	var remark Remark
+	assert.NoError(t, xml.Unmarshal([]byte(`<remark><note xmlns="urn:simple29:other">theirs</note></remark>`), &remark))
+	assert.Equal(t, Note2("theirs"), remark.Note)
+
+	assert.Error(t, xml.Unmarshal([]byte(`<type xmlns="urn:simple29">pending</type>`), new(Type)))
+	assert.True(t, StatusOpen2.IsValid())
+}
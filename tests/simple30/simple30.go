@@ -0,0 +1,40 @@
+package simple30
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/realmfoo/caementarii/xs"
+)
+
+type Catalog struct {
+	XMLName xml.Name `xml:"urn:simple30 catalog"`
+	Item    []Item   `xml:"item"`
+}
+
+type Item struct {
+	Name  string `xml:"name"`
+	Price Price  `xml:"price"`
+}
+
+type Price float64
+
+func (t Price) Validate() error {
+	if t < 0 {
+		return fmt.Errorf("Price: %v must be at least 0", t)
+	}
+	return nil
+}
+
+func (t *Price) UnmarshalText(text []byte) error {
+	s := xs.TrimSpace(string(text))
+	x, err := xs.ParseDouble(s)
+	if err != nil {
+		return fmt.Errorf("Price: %q is not a valid value: %w", text, err)
+	}
+	v := Price(x)
+	if err := v.Validate(); err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
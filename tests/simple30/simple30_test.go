@@ -0,0 +1,91 @@
+package simple30
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/resolver"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSimple30(t *testing.T) {
+	r := resolver.New(nil)
+	s, err := r.Load("simple30.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName:        "simple30",
+		ImportResolver: r.Resolve,
+	}
+	err = g.Generate(s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple30.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+// schemas returns a resolver of schema documents by their locations, which decodes a document on every reference.
+func schemas(documents map[string]string) func(string, string) (*xsd.Schema, error) {
+	return func(namespace string, schemaLocation string) (*xsd.Schema, error) {
+		s := xsd.Schema{}
+		err := xml.Unmarshal([]byte(documents[schemaLocation]), &s)
+		s.Location = schemaLocation
+		return &s, err
+	}
+}
+
+func TestRedefineCycle(t *testing.T) {
+	documents := map[string]string{
+		"http://example.com/a.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:a">
+			<xs:include schemaLocation="b.xsd"/>
+			<xs:element name="root" type="xs:string"/>
+		</xs:schema>`,
+		"http://example.com/b.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:a="urn:a" targetNamespace="urn:a">
+			<xs:redefine schemaLocation="c.xsd">
+				<xs:simpleType name="Code"><xs:restriction base="a:Code"><xs:maxLength value="2"/></xs:restriction></xs:simpleType>
+			</xs:redefine>
+			<xs:element name="code" type="a:Code"/>
+		</xs:schema>`,
+		"http://example.com/c.xsd": `<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:a">
+			<xs:override schemaLocation="a.xsd">
+				<xs:element name="root" type="xs:int"/>
+			</xs:override>
+			<xs:simpleType name="Code"><xs:restriction base="xs:string"/></xs:simpleType>
+		</xs:schema>`,
+	}
+	s, err := schemas(documents)("urn:a", "http://example.com/a.xsd")
+	assert.NoError(t, err)
+
+	// The override of a.xsd refers back to a document which includes c.xsd, so it adds nothing.
+	g := goxsd.Generator{PkgName: "a", ImportResolver: schemas(documents)}
+	buf := new(bytes.Buffer)
+	assert.NoError(t, g.Generate(s, buf))
+	source := strings.Join(strings.Fields(buf.String()), " ")
+	assert.True(t, strings.Contains(source, "type Root string"), source)
+	assert.True(t, strings.Contains(source, "type CodeType string"), source)
+	assert.True(t, strings.Contains(source, "n > 2 {"), source)
+}
+
+func TestUnmarshaler(t *testing.T) {
+	var c Catalog
+	err := xml.Unmarshal([]byte(`<catalog xmlns="urn:simple30">
+		<item><name>pen</name><price>1.5</price></item>
+		<item><name>ink</name><price>3</price></item>
+	</catalog>`), &c)
+	assert.NoError(t, err)
+	if assert.Equal(t, 2, len(c.Item)) {
+		assert.Equal(t, "pen", c.Item[0].Name)
+		assert.Equal(t, Price(3), c.Item[1].Price)
+	}
+	assert.Error(t, xml.Unmarshal([]byte(`<catalog xmlns="urn:simple30"><item><name>pen</name><price>-1</price></item></catalog>`), &c))
+}
@@ -0,0 +1,167 @@
+package simple31
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+type Note struct {
+	XMLName xml.Name `xml:"urn:caementarii:simple note"`
+	Text    *string  `xml:"text"`
+	Ref     *string  `xml:"ref"`
+	Tag     []string `xml:"tag"`
+}
+
+func (t Note) Which() string {
+	switch {
+	case t.Text != nil:
+		return "text"
+	case t.Ref != nil || len(t.Tag) > 0:
+		return "ref"
+	}
+	return ""
+}
+
+func (t Note) Validate() error {
+	n := 0
+	if t.Text != nil {
+		n++
+	}
+	if t.Ref != nil || len(t.Tag) > 0 {
+		n++
+	}
+	if n > 1 {
+		return fmt.Errorf("at most one of text, ref must be present in Note, got %d", n)
+	}
+	return nil
+}
+
+func (t Note) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	type alias Note
+	return e.Encode(alias(t))
+}
+
+func (t *Note) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Note
+	if err := d.DecodeElement((*alias)(t), &start); err != nil {
+		return err
+	}
+	return t.Validate()
+}
+
+type Person struct {
+	XMLName xml.Name `xml:"urn:caementarii:simple person"`
+	Name    string   `xml:"name"`
+	Email   *string  `xml:"email"`
+	Phone   *string  `xml:"phone"`
+}
+
+func (t Person) Which() string {
+	switch {
+	case t.Email != nil:
+		return "email"
+	case t.Phone != nil:
+		return "phone"
+	}
+	return ""
+}
+
+func (t Person) Validate() error {
+	n := 0
+	if t.Email != nil {
+		n++
+	}
+	if t.Phone != nil {
+		n++
+	}
+	if n > 1 {
+		return fmt.Errorf("at most one of email, phone must be present in Person, got %d", n)
+	}
+	return nil
+}
+
+func (t Person) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	type alias Person
+	return e.Encode(alias(t))
+}
+
+func (t *Person) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Person
+	if err := d.DecodeElement((*alias)(t), &start); err != nil {
+		return err
+	}
+	return t.Validate()
+}
+
+type Shape struct {
+	XMLName   xml.Name `xml:"urn:caementarii:simple shape"`
+	Circle    *int32   `xml:"circle"`
+	Square    *int32   `xml:"square"`
+	Rectangle *string  `xml:"rectangle"`
+}
+
+func (t Shape) Which() string {
+	switch {
+	case t.Circle != nil:
+		return "circle"
+	case t.Square != nil || t.Rectangle != nil:
+		return "square"
+	}
+	return ""
+}
+
+func (t Shape) Which2() string {
+	switch {
+	case t.Square != nil:
+		return "square"
+	case t.Rectangle != nil:
+		return "rectangle"
+	}
+	return ""
+}
+
+func (t Shape) Validate() error {
+	n := 0
+	if t.Circle != nil {
+		n++
+	}
+	if t.Square != nil || t.Rectangle != nil {
+		n++
+	}
+	if n != 1 {
+		return fmt.Errorf("exactly one of circle, square must be present in Shape, got %d", n)
+	}
+	n = 0
+	if t.Square != nil {
+		n++
+	}
+	if t.Rectangle != nil {
+		n++
+	}
+	if n > 1 {
+		return fmt.Errorf("at most one of square, rectangle must be present in Shape, got %d", n)
+	}
+	return nil
+}
+
+func (t Shape) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	type alias Shape
+	return e.Encode(alias(t))
+}
+
+func (t *Shape) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type alias Shape
+	if err := d.DecodeElement((*alias)(t), &start); err != nil {
+		return err
+	}
+	return t.Validate()
+}
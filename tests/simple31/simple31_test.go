@@ -0,0 +1,87 @@
+package simple31
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/realmfoo/caementarii"
+	"github.com/realmfoo/caementarii/xsd"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestSimple31(t *testing.T) {
+	data, err := os.ReadFile("simple31.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := xsd.Schema{}
+	err = xml.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+
+	g := goxsd.Generator{
+		PkgName: "simple31",
+	}
+	err = g.Generate(&s, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := os.ReadFile("simple31.go")
+	assert.Equal(t, string(expected), buf.String())
+}
+
+func TestOptionalChoice(t *testing.T) {
+	// The choice is in an optional sequence, so neither of its branches is required.
+	var p Person
+	assert.NoError(t, xml.Unmarshal([]byte(`<person xmlns="urn:caementarii:simple"><name>name</name></person>`), &p))
+	assert.Equal(t, "", p.Which())
+	_, err := xml.Marshal(p)
+	assert.NoError(t, err)
+
+	assert.NoError(t, xml.Unmarshal([]byte(`<person xmlns="urn:caementarii:simple"><name>name</name><phone>123</phone></person>`), &p))
+	assert.Equal(t, "phone", p.Which())
+
+	err = xml.Unmarshal([]byte(`<person xmlns="urn:caementarii:simple"><name>name</name><email>a@b.c</email><phone>123</phone></person>`), &Person{})
+	assert.Error(t, err)
+}
+
+func TestEmptiableBranch(t *testing.T) {
+	// The second branch may have no elements, so the choice may be missing.
+	var n Note
+	assert.NoError(t, xml.Unmarshal([]byte(`<note xmlns="urn:caementarii:simple"/>`), &n))
+	assert.Equal(t, "", n.Which())
+	assert.NoError(t, xml.Unmarshal([]byte(`<note xmlns="urn:caementarii:simple"><tag>a</tag></note>`), &n))
+	assert.Equal(t, "ref", n.Which())
+
+	err := xml.Unmarshal([]byte(`<note xmlns="urn:caementarii:simple"><text>a</text><tag>a</tag></note>`), &Note{})
+	assert.Error(t, err)
+}
+
+func TestNestedChoice(t *testing.T) {
+	var s Shape
+	assert.NoError(t, xml.Unmarshal([]byte(`<shape xmlns="urn:caementarii:simple"><rectangle>1x2</rectangle></shape>`), &s))
+	assert.Equal(t, "square", s.Which())
+	assert.Equal(t, "rectangle", s.Which2())
+
+	// Both branches of the inner choice are present.
+	_, err := xml.Marshal(Shape{Square: xsint(1), Rectangle: xsstring("1x2")})
+	assert.Error(t, err)
+	assert.Error(t, xml.Unmarshal([]byte(`<shape xmlns="urn:caementarii:simple"><square>1</square><rectangle>1x2</rectangle></shape>`), &Shape{}))
+
+	assert.Error(t, xml.Unmarshal([]byte(`<shape xmlns="urn:caementarii:simple"><circle>1</circle><square>1</square></shape>`), &Shape{}))
+	assert.Error(t, xml.Unmarshal([]byte(`<shape xmlns="urn:caementarii:simple"></shape>`), &Shape{}))
+}
+
+func xsstring(s string) *string {
+	return &s
+}
+
+func xsint(i int32) *int32 {
+	return &i
+}
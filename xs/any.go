@@ -0,0 +1,80 @@
+package xs
+
+import (
+	"encoding/xml"
+)
+
+// AnyElement is an element matched by an element wildcard. Its content is kept as a sequence of tokens, so the element
+// is encoded back as it was decoded.
+//
+// Namespace declarations are dropped from attributes, since names of elements and attributes are already resolved to
+// their namespaces, and the encoder declares them again where they are used.
+type AnyElement struct {
+	XMLName xml.Name
+	Attr    []xml.Attr
+	// Content is a sequence of tokens between the start and the end tags of the element.
+	Content []xml.Token
+}
+
+func (a *AnyElement) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	a.XMLName = start.Name
+	a.Attr = stripNamespaceDecls(start.Attr)
+	a.Content = nil
+	for depth := 0; ; {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			t.Attr = stripNamespaceDecls(t.Attr)
+			tok = t
+		case xml.EndElement:
+			if depth == 0 {
+				return nil
+			}
+			depth--
+		}
+		a.Content = append(a.Content, xml.CopyToken(tok))
+	}
+}
+
+// MarshalXML encodes the element with its own name, so the name of the start element is ignored.
+func (a AnyElement) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(xml.StartElement{Name: a.XMLName, Attr: a.Attr}); err != nil {
+		return err
+	}
+
+	// The encoder declares a default namespace for a qualified element, but it doesn't undeclare it for an
+	// unqualified element inside of it.
+	spaces := []string{a.XMLName.Space}
+	for _, tok := range a.Content {
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space == "" && spaces[len(spaces)-1] != "" {
+				t.Attr = append([]xml.Attr{{Name: xml.Name{Local: "xmlns"}}}, t.Attr...)
+			}
+			spaces = append(spaces, t.Name.Space)
+			tok = t
+		case xml.EndElement:
+			spaces = spaces[:len(spaces)-1]
+		}
+		if err := e.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(xml.EndElement{Name: a.XMLName})
+}
+
+// stripNamespaceDecls returns attributes without namespace declarations.
+func stripNamespaceDecls(attrs []xml.Attr) []xml.Attr {
+	r := make([]xml.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		if a.Name.Space != "xmlns" && !(a.Name.Space == "" && a.Name.Local == "xmlns") {
+			r = append(r, a)
+		}
+	}
+	return r
+}
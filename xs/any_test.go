@@ -0,0 +1,43 @@
+package xs
+
+import (
+	"encoding/xml"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAnyElement(t *testing.T) {
+	var a AnyElement
+	err := xml.Unmarshal([]byte(`<ext xmlns="urn:ext" xmlns:x="urn:x" id="1" x:lang="en">text<!-- note --><item x:ref="a">one</item><plain xmlns="">two</plain><x:tail/></ext>`), &a)
+	assert.NoError(t, err)
+	assert.Equal(t, xml.Name{Space: "urn:ext", Local: "ext"}, a.XMLName)
+	assert.Equal(t, []xml.Attr{
+		{Name: xml.Name{Local: "id"}, Value: "1"},
+		{Name: xml.Name{Space: "urn:x", Local: "lang"}, Value: "en"},
+	}, a.Attr)
+	assert.Equal(t, 10, len(a.Content))
+	assert.Equal(t, xml.CharData("text"), a.Content[0])
+	assert.Equal(t, xml.Comment(" note "), a.Content[1])
+
+	data, err := xml.Marshal(a)
+	assert.NoError(t, err)
+
+	// The document is the same, though namespace prefixes are not.
+	var b AnyElement
+	assert.NoError(t, xml.Unmarshal(data, &b))
+	assert.Equal(t, a, b)
+}
+
+func TestAnyElementUnqualified(t *testing.T) {
+	a := AnyElement{
+		XMLName: xml.Name{Space: "urn:ext", Local: "ext"},
+		Content: []xml.Token{
+			xml.StartElement{Name: xml.Name{Local: "plain"}},
+			xml.CharData("text"),
+			xml.EndElement{Name: xml.Name{Local: "plain"}},
+		},
+	}
+	data, err := xml.Marshal(a)
+	assert.NoError(t, err)
+	assert.Equal(t, `<ext xmlns="urn:ext"><plain xmlns="">text</plain></ext>`, string(data))
+}
@@ -0,0 +1,59 @@
+package xs
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Base64Binary is an xs:base64Binary value, which is encoded by the standard base64 encoding.
+type Base64Binary []byte
+
+// String returns the canonical form of the data, which has no whitespace.
+func (b Base64Binary) String() string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func (b Base64Binary) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText decodes the data. XML whitespace is allowed anywhere, so line-wrapped data could be decoded too, but
+// other Unicode spaces are not.
+func (b *Base64Binary) UnmarshalText(text []byte) error {
+	s := strings.Map(func(r rune) rune {
+		if isSpace(r) {
+			return -1
+		}
+		return r
+	}, string(text))
+	v, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("xs: invalid base64Binary: %w", err)
+	}
+	*b = v
+	return nil
+}
+
+// HexBinary is an xs:hexBinary value.
+type HexBinary []byte
+
+// String returns the canonical form of the data, which uses upper case digits.
+func (b HexBinary) String() string {
+	return strings.ToUpper(hex.EncodeToString(b))
+}
+
+func (b HexBinary) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+// UnmarshalText decodes the data. Leading and trailing XML whitespace is ignored.
+func (b *HexBinary) UnmarshalText(text []byte) error {
+	v, err := hex.DecodeString(TrimSpace(string(text)))
+	if err != nil {
+		return fmt.Errorf("xs: invalid hexBinary: %w", err)
+	}
+	*b = v
+	return nil
+}
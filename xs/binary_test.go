@@ -0,0 +1,38 @@
+package xs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestBase64Binary(t *testing.T) {
+	var b Base64Binary
+	assert.NoError(t, b.UnmarshalText([]byte("  SGVs\n bG8s\r\n\tIHdv cmxk ")))
+	assert.Equal(t, Base64Binary("Hello, world"), b)
+	text, err := b.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "SGVsbG8sIHdvcmxk", string(text))
+
+	assert.NoError(t, b.UnmarshalText([]byte("")))
+	assert.Equal(t, Base64Binary{}, b)
+
+	assert.Error(t, b.UnmarshalText([]byte("SGVsbG8")))
+	assert.Error(t, b.UnmarshalText([]byte("SGV*bG8=")))
+	// Only XML whitespace is allowed.
+	assert.Error(t, b.UnmarshalText([]byte("SGVs\u00a0bG8s")))
+	assert.Error(t, b.UnmarshalText([]byte("SGVsbG8s\u2028")))
+}
+
+func TestHexBinary(t *testing.T) {
+	var b HexBinary
+	assert.NoError(t, b.UnmarshalText([]byte(" 0fb7\n")))
+	assert.Equal(t, HexBinary{0x0f, 0xb7}, b)
+	text, err := b.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "0FB7", string(text))
+
+	assert.Error(t, b.UnmarshalText([]byte("0fb")))
+	assert.Error(t, b.UnmarshalText([]byte("0f b7")))
+	assert.Error(t, b.UnmarshalText([]byte("0g")))
+	assert.Error(t, b.UnmarshalText([]byte("\u00a00fb7")))
+}
@@ -0,0 +1,524 @@
+// Package xs provides Go types for XML Schema built-in datatypes which have no suitable counterpart in the standard
+// library. Generated code refers to them, and they implement encoding.TextMarshaler and encoding.TextUnmarshaler, so
+// they could be used both in attributes and in elements.
+package xs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Components of the date/time lexical forms.
+const (
+	hasYear = 1 << iota
+	hasMonth
+	hasDay
+	hasTime
+)
+
+// DateTime is an xs:dateTime value. A value without a timezone keeps its clock in UTC.
+type DateTime struct {
+	Time time.Time
+	// Timezone reports whether the value has a timezone.
+	Timezone bool
+}
+
+// ParseDateTime parses the lexical form of xs:dateTime, e.g. 2002-10-10T12:00:00.5-05:00.
+func ParseDateTime(s string) (DateTime, error) {
+	t, tz, err := parseDateTime("dateTime", s, hasYear|hasMonth|hasDay|hasTime)
+	return DateTime{Time: t, Timezone: tz}, err
+}
+
+func (t DateTime) String() string {
+	return formatDateTime(t.Time, t.Timezone, hasYear|hasMonth|hasDay|hasTime)
+}
+
+func (t DateTime) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *DateTime) UnmarshalText(text []byte) error {
+	v, err := ParseDateTime(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// DateTimeStamp is an xs:dateTimeStamp value, which is an xs:dateTime with a required timezone.
+type DateTimeStamp struct {
+	Time time.Time
+}
+
+// ParseDateTimeStamp parses the lexical form of xs:dateTimeStamp, e.g. 2002-10-10T12:00:00Z.
+func ParseDateTimeStamp(s string) (DateTimeStamp, error) {
+	t, tz, err := parseDateTime("dateTimeStamp", s, hasYear|hasMonth|hasDay|hasTime)
+	if err == nil && !tz {
+		err = fmt.Errorf("xs: dateTimeStamp %q has no timezone", s)
+	}
+	return DateTimeStamp{Time: t}, err
+}
+
+func (t DateTimeStamp) String() string {
+	return formatDateTime(t.Time, true, hasYear|hasMonth|hasDay|hasTime)
+}
+
+func (t DateTimeStamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *DateTimeStamp) UnmarshalText(text []byte) error {
+	v, err := ParseDateTimeStamp(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// Date is an xs:date value. The clock of Time is midnight.
+type Date struct {
+	Time time.Time
+	// Timezone reports whether the value has a timezone.
+	Timezone bool
+}
+
+// ParseDate parses the lexical form of xs:date, e.g. 2002-10-10+13:00.
+func ParseDate(s string) (Date, error) {
+	t, tz, err := parseDateTime("date", s, hasYear|hasMonth|hasDay)
+	return Date{Time: t, Timezone: tz}, err
+}
+
+func (t Date) String() string {
+	return formatDateTime(t.Time, t.Timezone, hasYear|hasMonth|hasDay)
+}
+
+func (t Date) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Date) UnmarshalText(text []byte) error {
+	v, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// Time is an xs:time value. Only the clock of Time is significant, its date is 2000-01-01.
+type Time struct {
+	Time time.Time
+	// Timezone reports whether the value has a timezone.
+	Timezone bool
+}
+
+// ParseTime parses the lexical form of xs:time, e.g. 13:20:00-05:00.
+func ParseTime(s string) (Time, error) {
+	t, tz, err := parseDateTime("time", s, hasTime)
+	return Time{Time: t, Timezone: tz}, err
+}
+
+func (t Time) String() string {
+	return formatDateTime(t.Time, t.Timezone, hasTime)
+}
+
+func (t Time) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Time) UnmarshalText(text []byte) error {
+	v, err := ParseTime(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// GYearMonth is an xs:gYearMonth value. Only the year and the month of Time are significant, its day is the first
+// one of the month.
+type GYearMonth struct {
+	Time time.Time
+	// Timezone reports whether the value has a timezone.
+	Timezone bool
+}
+
+// ParseGYearMonth parses the lexical form of xs:gYearMonth, e.g. 1999-05.
+func ParseGYearMonth(s string) (GYearMonth, error) {
+	t, tz, err := parseDateTime("gYearMonth", s, hasYear|hasMonth)
+	return GYearMonth{Time: t, Timezone: tz}, err
+}
+
+func (t GYearMonth) String() string {
+	return formatDateTime(t.Time, t.Timezone, hasYear|hasMonth)
+}
+
+func (t GYearMonth) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *GYearMonth) UnmarshalText(text []byte) error {
+	v, err := ParseGYearMonth(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// GYear is an xs:gYear value. Only the year of Time is significant, its day is January 1.
+type GYear struct {
+	Time time.Time
+	// Timezone reports whether the value has a timezone.
+	Timezone bool
+}
+
+// ParseGYear parses the lexical form of xs:gYear, e.g. 1999.
+func ParseGYear(s string) (GYear, error) {
+	t, tz, err := parseDateTime("gYear", s, hasYear)
+	return GYear{Time: t, Timezone: tz}, err
+}
+
+func (t GYear) String() string {
+	return formatDateTime(t.Time, t.Timezone, hasYear)
+}
+
+func (t GYear) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *GYear) UnmarshalText(text []byte) error {
+	v, err := ParseGYear(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// GMonthDay is an xs:gMonthDay value. Only the month and the day of Time are significant, its year is the leap year
+// 2000, so February 29 is allowed.
+type GMonthDay struct {
+	Time time.Time
+	// Timezone reports whether the value has a timezone.
+	Timezone bool
+}
+
+// ParseGMonthDay parses the lexical form of xs:gMonthDay, e.g. --12-25.
+func ParseGMonthDay(s string) (GMonthDay, error) {
+	t, tz, err := parseDateTime("gMonthDay", s, hasMonth|hasDay)
+	return GMonthDay{Time: t, Timezone: tz}, err
+}
+
+func (t GMonthDay) String() string {
+	return formatDateTime(t.Time, t.Timezone, hasMonth|hasDay)
+}
+
+func (t GMonthDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *GMonthDay) UnmarshalText(text []byte) error {
+	v, err := ParseGMonthDay(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// GDay is an xs:gDay value. Only the day of Time is significant, its month is January 2000.
+type GDay struct {
+	Time time.Time
+	// Timezone reports whether the value has a timezone.
+	Timezone bool
+}
+
+// ParseGDay parses the lexical form of xs:gDay, e.g. ---25.
+func ParseGDay(s string) (GDay, error) {
+	t, tz, err := parseDateTime("gDay", s, hasDay)
+	return GDay{Time: t, Timezone: tz}, err
+}
+
+func (t GDay) String() string {
+	return formatDateTime(t.Time, t.Timezone, hasDay)
+}
+
+func (t GDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *GDay) UnmarshalText(text []byte) error {
+	v, err := ParseGDay(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// GMonth is an xs:gMonth value. Only the month of Time is significant, its year is 2000 and its day is the first
+// one of the month.
+type GMonth struct {
+	Time time.Time
+	// Timezone reports whether the value has a timezone.
+	Timezone bool
+}
+
+// ParseGMonth parses the lexical form of xs:gMonth, e.g. --12.
+func ParseGMonth(s string) (GMonth, error) {
+	t, tz, err := parseDateTime("gMonth", s, hasMonth)
+	return GMonth{Time: t, Timezone: tz}, err
+}
+
+func (t GMonth) String() string {
+	return formatDateTime(t.Time, t.Timezone, hasMonth)
+}
+
+func (t GMonth) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *GMonth) UnmarshalText(text []byte) error {
+	v, err := ParseGMonth(string(text))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// scanner reads components of a lexical form.
+type scanner struct {
+	s   string
+	pos int
+}
+
+func (p *scanner) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+// lit skips a character if it's the next one.
+func (p *scanner) lit(c byte) bool {
+	if p.pos < len(p.s) && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// digits reads at least min and at most max decimal digits.
+func (p *scanner) digits(min int, max int) (string, bool) {
+	start := p.pos
+	for p.pos < len(p.s) && p.pos-start < max && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.s[start:p.pos], p.pos-start >= min
+}
+
+// number reads exactly n decimal digits.
+func (p *scanner) number(n int) (int, bool) {
+	d, ok := p.digits(n, n)
+	if !ok {
+		return 0, false
+	}
+	v, _ := strconv.Atoi(d)
+	return v, true
+}
+
+// year reads an optionally negative year of at least four digits, which has no leading zeros if it's longer.
+func (p *scanner) year() (int, bool) {
+	negative := p.lit('-')
+	d, ok := p.digits(4, 9)
+	if !ok || (len(d) > 4 && d[0] == '0') {
+		return 0, false
+	}
+	v, _ := strconv.Atoi(d)
+	if negative {
+		v = -v
+	}
+	return v, true
+}
+
+// fraction reads an optional fraction of a second as nanoseconds. Digits beyond nanoseconds are dropped.
+func (p *scanner) fraction() (int, bool) {
+	if !p.lit('.') {
+		return 0, true
+	}
+	d, ok := p.digits(1, len(p.s))
+	if !ok {
+		return 0, false
+	}
+	if len(d) > 9 {
+		d = d[:9]
+	}
+	v, _ := strconv.Atoi(d + strings.Repeat("0", 9-len(d)))
+	return v, true
+}
+
+// timezone reads an optional timezone, which is Z or an offset from -14:00 to +14:00.
+func (p *scanner) timezone() (*time.Location, bool, bool) {
+	if p.lit('Z') {
+		return time.UTC, true, true
+	}
+	sign := 1
+	switch {
+	case p.lit('+'):
+	case p.lit('-'):
+		sign = -1
+	default:
+		return time.UTC, false, true
+	}
+	h, ok := p.number(2)
+	if !ok || !p.lit(':') {
+		return nil, false, false
+	}
+	m, ok := p.number(2)
+	if !ok || h > 14 || m > 59 || (h == 14 && m > 0) {
+		return nil, false, false
+	}
+	return time.FixedZone("", sign*(h*3600+m*60)), true, true
+}
+
+// parseDateTime parses a lexical form which consists of the given components and an optional timezone. Components
+// which are absent are taken from 2000-01-01T00:00:00.
+func parseDateTime(typeName string, s string, parts int) (time.Time, bool, error) {
+	fail := func() (time.Time, bool, error) {
+		return time.Time{}, false, fmt.Errorf("xs: %q is not a valid %s", s, typeName)
+	}
+
+	p := &scanner{s: TrimSpace(s)}
+	year, month, day := 2000, 1, 1
+	hour, minute, second, nsec := 0, 0, 0, 0
+	ok := true
+
+	if parts&hasYear != 0 {
+		year, ok = p.year()
+		if ok && parts&hasMonth != 0 {
+			ok = p.lit('-')
+			if ok {
+				month, ok = p.number(2)
+			}
+		}
+		if ok && parts&hasDay != 0 {
+			ok = p.lit('-')
+			if ok {
+				day, ok = p.number(2)
+			}
+		}
+	} else if parts&(hasMonth|hasDay) != 0 {
+		// --MM, --MM-DD and ---DD
+		ok = p.lit('-') && p.lit('-')
+		if ok && parts&hasMonth != 0 {
+			month, ok = p.number(2)
+		}
+		if ok && parts&hasDay != 0 {
+			ok = p.lit('-')
+			if ok {
+				day, ok = p.number(2)
+			}
+		}
+	}
+	if !ok || month < 1 || month > 12 || day < 1 || day > daysIn(time.Month(month), year) {
+		return fail()
+	}
+
+	if parts&hasTime != 0 {
+		if parts&hasDay != 0 {
+			ok = p.lit('T')
+		}
+		if ok {
+			hour, ok = p.number(2)
+		}
+		ok = ok && p.lit(':')
+		if ok {
+			minute, ok = p.number(2)
+		}
+		ok = ok && p.lit(':')
+		if ok {
+			second, ok = p.number(2)
+		}
+		if ok {
+			nsec, ok = p.fraction()
+		}
+		if !ok || minute > 59 || second > 59 || hour > 24 || (hour == 24 && (minute > 0 || second > 0 || nsec > 0)) {
+			return fail()
+		}
+	}
+
+	loc, tz, ok := p.timezone()
+	if !ok || !p.eof() {
+		return fail()
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, minute, second, nsec, loc)
+	if hour == 24 && parts&hasDay == 0 {
+		// 24:00:00 of a time is the midnight of the same day.
+		t = t.AddDate(0, 0, -1)
+	}
+	return t, tz, nil
+}
+
+// formatDateTime formats the given components of a time and its timezone if it has one.
+func formatDateTime(t time.Time, tz bool, parts int) string {
+	var b strings.Builder
+
+	if parts&hasYear != 0 {
+		year := t.Year()
+		if year < 0 {
+			b.WriteByte('-')
+			year = -year
+		}
+		fmt.Fprintf(&b, "%04d", year)
+		if parts&hasMonth != 0 {
+			fmt.Fprintf(&b, "-%02d", int(t.Month()))
+		}
+		if parts&hasDay != 0 {
+			fmt.Fprintf(&b, "-%02d", t.Day())
+		}
+	} else if parts&(hasMonth|hasDay) != 0 {
+		b.WriteString("--")
+		if parts&hasMonth != 0 {
+			fmt.Fprintf(&b, "%02d", int(t.Month()))
+		}
+		if parts&hasDay != 0 {
+			fmt.Fprintf(&b, "-%02d", t.Day())
+		}
+	}
+
+	if parts&hasTime != 0 {
+		if parts&hasDay != 0 {
+			b.WriteByte('T')
+		}
+		fmt.Fprintf(&b, "%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+		if ns := t.Nanosecond(); ns > 0 {
+			b.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", ns), "0"))
+		}
+	}
+
+	if tz {
+		_, offset := t.Zone()
+		if offset == 0 {
+			b.WriteByte('Z')
+		} else {
+			sign := byte('+')
+			if offset < 0 {
+				sign = '-'
+				offset = -offset
+			}
+			b.WriteByte(sign)
+			fmt.Fprintf(&b, "%02d:%02d", offset/3600, offset/60%60)
+		}
+	}
+
+	return b.String()
+}
+
+// daysIn returns the number of days in a month of a year of the proleptic Gregorian calendar.
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,104 @@
+package xs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDateTimeRoundTrip(t *testing.T) {
+	cases := []struct {
+		parse  func(string) (string, error)
+		values []string
+	}{
+		{func(s string) (string, error) { v, err := ParseDateTime(s); return v.String(), err }, []string{
+			"2002-10-10T12:00:00", "2002-10-10T12:00:00.5-05:00", "2002-10-10T17:00:00Z", "-0044-03-15T12:00:00+14:00",
+			"12002-10-10T12:00:00Z", "2000-02-29T23:59:59.000000001",
+		}},
+		{func(s string) (string, error) { v, err := ParseDateTimeStamp(s); return v.String(), err }, []string{
+			"2002-10-10T12:00:00Z", "2002-10-10T12:00:00-05:00",
+		}},
+		{func(s string) (string, error) { v, err := ParseDate(s); return v.String(), err }, []string{
+			"2002-10-10", "2002-10-10+13:00", "0000-01-01Z",
+		}},
+		{func(s string) (string, error) { v, err := ParseTime(s); return v.String(), err }, []string{
+			"13:20:00", "13:20:00.25-05:00", "00:00:00Z",
+		}},
+		{func(s string) (string, error) { v, err := ParseGYearMonth(s); return v.String(), err }, []string{
+			"1999-05", "1999-05Z",
+		}},
+		{func(s string) (string, error) { v, err := ParseGYear(s); return v.String(), err }, []string{
+			"1999", "-0001+01:00",
+		}},
+		{func(s string) (string, error) { v, err := ParseGMonthDay(s); return v.String(), err }, []string{
+			"--12-25", "--02-29Z",
+		}},
+		{func(s string) (string, error) { v, err := ParseGDay(s); return v.String(), err }, []string{
+			"---25", "---31-10:30",
+		}},
+		{func(s string) (string, error) { v, err := ParseGMonth(s); return v.String(), err }, []string{
+			"--12", "--01Z",
+		}},
+	}
+
+	for _, c := range cases {
+		for _, s := range c.values {
+			v, err := c.parse(s)
+			if assert.NoError(t, err, s) {
+				assert.Equal(t, s, v)
+			}
+		}
+	}
+}
+
+func TestDateTimeCanonical(t *testing.T) {
+	v, err := ParseDateTime(" 2002-10-10T24:00:00.000+00:00 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "2002-10-11T00:00:00Z", v.String())
+	assert.True(t, v.Timezone)
+
+	tm, err := ParseTime("24:00:00")
+	assert.NoError(t, err)
+	assert.Equal(t, "00:00:00", tm.String())
+	assert.False(t, tm.Timezone)
+
+	d, err := ParseDate("2002-10-10-05:00")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2002, 10, 10, 5, 0, 0, 0, time.UTC), d.Time.UTC())
+}
+
+func TestDateTimeErrors(t *testing.T) {
+	cases := []struct {
+		parse  func(string) error
+		values []string
+	}{
+		{func(s string) error { _, err := ParseDateTime(s); return err }, []string{
+			"2002-10-10", "2002-10-10 12:00:00", "2002-10-10T24:00:01", "2002-02-29T00:00:00", "02002-10-10T12:00:00",
+			"2002-10-10T12:00:00+14:30", "2002-10-10T12:00:00.", "2002-10-10T12:60:00", "2002-13-10T12:00:00",
+		}},
+		{func(s string) error { _, err := ParseDateTimeStamp(s); return err }, []string{"2002-10-10T12:00:00"}},
+		{func(s string) error { _, err := ParseDate(s); return err }, []string{"2002-04-31", "2002-10-10T00:00:00"}},
+		{func(s string) error { _, err := ParseTime(s); return err }, []string{"25:00:00", "13:20", "13:20:00+5:00"}},
+		{func(s string) error { _, err := ParseGYearMonth(s); return err }, []string{"1999", "1999-00"}},
+		{func(s string) error { _, err := ParseGYear(s); return err }, []string{"99", "1999-01"}},
+		{func(s string) error { _, err := ParseGMonthDay(s); return err }, []string{"--02-30", "12-25"}},
+		{func(s string) error { _, err := ParseGDay(s); return err }, []string{"---32", "--25"}},
+		{func(s string) error { _, err := ParseGMonth(s); return err }, []string{"--13", "--12--"}},
+	}
+
+	for _, c := range cases {
+		for _, s := range c.values {
+			assert.Error(t, c.parse(s), s)
+		}
+	}
+}
+
+func TestDateTimeText(t *testing.T) {
+	var v DateTime
+	assert.NoError(t, v.UnmarshalText([]byte("2002-10-10T12:00:00-05:00")))
+	text, err := v.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "2002-10-10T12:00:00-05:00", string(text))
+
+	assert.Error(t, v.UnmarshalText([]byte("yesterday")))
+}
@@ -0,0 +1,303 @@
+package xs
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Decimal is an xs:decimal value of arbitrary precision. It keeps the number of fraction digits of its lexical form,
+// so 10.50 is formatted back as 10.50. The zero value is 0.
+type Decimal struct {
+	// unscaled is the value multiplied by 10^scale, nil is 0. It's never modified, so copies of a Decimal may share it.
+	unscaled *big.Int
+	scale    int
+}
+
+// NewDecimal returns a decimal which is unscaled multiplied by 10^-scale, e.g. NewDecimal(big.NewInt(1050), 2) is
+// 10.50. A nil unscaled is 0, and a negative scale is taken as 0.
+func NewDecimal(unscaled *big.Int, scale int) Decimal {
+	if scale < 0 {
+		scale = 0
+	}
+	if unscaled == nil {
+		return Decimal{scale: scale}
+	}
+	return Decimal{unscaled: new(big.Int).Set(unscaled), scale: scale}
+}
+
+// ParseDecimal parses the lexical form of xs:decimal, e.g. -1.23, +100000.00 or .5.
+func ParseDecimal(s string) (Decimal, error) {
+	p := &scanner{s: TrimSpace(s)}
+	negative := false
+	if p.lit('-') {
+		negative = true
+	} else {
+		p.lit('+')
+	}
+	integer, _ := p.digits(0, len(p.s))
+	fraction := ""
+	if p.lit('.') {
+		fraction, _ = p.digits(0, len(p.s))
+	}
+	if (integer == "" && fraction == "") || !p.eof() {
+		return Decimal{}, fmt.Errorf("xs: %q is not a valid decimal", s)
+	}
+
+	unscaled, _ := new(big.Int).SetString(integer+fraction, 10)
+	if negative {
+		unscaled.Neg(unscaled)
+	}
+	return Decimal{unscaled: unscaled, scale: len(fraction)}, nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics if the lexical form is not valid.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func (d Decimal) int() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// String returns the decimal with as many fraction digits as it has been created with.
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	if len(digits) <= d.scale {
+		digits = strings.Repeat("0", d.scale-len(digits)+1) + digits
+	}
+
+	var b strings.Builder
+	if d.int().Sign() < 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(digits[:len(digits)-d.scale])
+	if d.scale > 0 {
+		b.WriteByte('.')
+		b.WriteString(digits[len(digits)-d.scale:])
+	}
+	return b.String()
+}
+
+// Canonical returns the canonical form of the decimal, which has no trailing zeros in the fraction, e.g. 10.5 for
+// 10.50 and 1 for 1.0.
+func (d Decimal) Canonical() string {
+	return d.normalize().String()
+}
+
+// normalize returns the same value with no trailing zeros in the fraction.
+func (d Decimal) normalize() Decimal {
+	unscaled, scale := new(big.Int).Set(d.int()), d.scale
+	ten := big.NewInt(10)
+	q, r := new(big.Int), new(big.Int)
+	for scale > 0 {
+		q.QuoRem(unscaled, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		unscaled.Set(q)
+		scale--
+	}
+	return Decimal{unscaled: unscaled, scale: scale}
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the decimal.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// Cmp compares decimals and returns -1, 0 or +1.
+func (d Decimal) Cmp(e Decimal) int {
+	a, b := d.int(), e.int()
+	if d.scale < e.scale {
+		a = new(big.Int).Mul(a, pow10(e.scale-d.scale))
+	} else if d.scale > e.scale {
+		b = new(big.Int).Mul(b, pow10(d.scale-e.scale))
+	}
+	return a.Cmp(b)
+}
+
+// Rat returns the decimal as a new big.Rat.
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.int(), pow10(d.scale))
+}
+
+// Float64 returns the nearest float64 value of the decimal.
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// TotalDigits returns the number of significant digits of the decimal, as the totalDigits facet counts them: 0.05
+// has 2 digits, 100 has 3 digits and 1.50 has 2 digits.
+func (d Decimal) TotalDigits() int {
+	n := d.normalize()
+	digits := len(new(big.Int).Abs(n.int()).String())
+	if digits < n.scale {
+		return n.scale
+	}
+	return digits
+}
+
+// FractionDigits returns the number of fraction digits of the decimal without trailing zeros, as the fractionDigits
+// facet counts them.
+func (d Decimal) FractionDigits() int {
+	return d.normalize().scale
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// pow10 returns 10^n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// Rat is a big.Rat which is encoded by the lexical form of xs:decimal, while big.Rat itself is encoded as a fraction.
+// A nil Rat is 0.
+type Rat struct {
+	*big.Rat
+}
+
+func (r Rat) rat() *big.Rat {
+	if r.Rat == nil {
+		return new(big.Rat)
+	}
+	return r.Rat
+}
+
+// IsDecimal reports whether the number has a finite decimal representation, i.e. its denominator has no prime
+// factors but 2 and 5.
+func (r Rat) IsDecimal() bool {
+	_, ok := r.decimal()
+	return ok
+}
+
+// Decimal returns the number as a decimal. If it has no finite decimal representation, it's rounded to 20 fraction
+// digits.
+func (r Rat) Decimal() Decimal {
+	d, ok := r.decimal()
+	if !ok {
+		d, _ = ParseDecimal(r.rat().FloatString(20))
+	}
+	return d
+}
+
+func (r Rat) decimal() (Decimal, bool) {
+	x := r.rat()
+	denom := new(big.Int).Set(x.Denom())
+	q, m := new(big.Int), new(big.Int)
+	twos, fives := 0, 0
+	for _, f := range []struct {
+		factor int64
+		count  *int
+	}{{2, &twos}, {5, &fives}} {
+		for {
+			q.QuoRem(denom, big.NewInt(f.factor), m)
+			if m.Sign() != 0 {
+				break
+			}
+			denom.Set(q)
+			*f.count++
+		}
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return Decimal{}, false
+	}
+	scale := twos
+	if fives > scale {
+		scale = fives
+	}
+	unscaled := new(big.Int).Mul(x.Num(), pow10(scale))
+	return Decimal{unscaled: unscaled.Quo(unscaled, x.Denom()), scale: scale}, true
+}
+
+// String returns the canonical decimal form of the number, or a fraction if it has no finite decimal representation.
+func (r Rat) String() string {
+	if d, ok := r.decimal(); ok {
+		return d.String()
+	}
+	return r.rat().RatString()
+}
+
+func (r Rat) MarshalText() ([]byte, error) {
+	d, ok := r.decimal()
+	if !ok {
+		return nil, fmt.Errorf("xs: %s has no finite decimal representation", r.rat().RatString())
+	}
+	return d.MarshalText()
+}
+
+func (r *Rat) UnmarshalText(text []byte) error {
+	d, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	r.Rat = d.Rat()
+	return nil
+}
+
+// Int is a big.Int which is encoded by the lexical form of xs:integer, while big.Int itself accepts base prefixes,
+// e.g. 010 is 8 for it. A nil Int is 0.
+type Int struct {
+	*big.Int
+}
+
+// ParseInt parses the lexical form of xs:integer, e.g. -1, 0 or +100000.
+func ParseInt(s string) (Int, error) {
+	p := &scanner{s: TrimSpace(s)}
+	if !p.lit('-') {
+		p.lit('+')
+	}
+	if _, ok := p.digits(1, len(p.s)); !ok || !p.eof() {
+		return Int{}, fmt.Errorf("xs: %q is not a valid integer", s)
+	}
+	v, _ := new(big.Int).SetString(strings.TrimPrefix(p.s, "+"), 10)
+	return Int{v}, nil
+}
+
+func (i Int) int() *big.Int {
+	if i.Int == nil {
+		return new(big.Int)
+	}
+	return i.Int
+}
+
+// Decimal returns the integer as a decimal.
+func (i Int) Decimal() Decimal {
+	return Decimal{unscaled: new(big.Int).Set(i.int())}
+}
+
+func (i Int) String() string {
+	return i.int().String()
+}
+
+func (i Int) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+func (i *Int) UnmarshalText(text []byte) error {
+	v, err := ParseInt(string(text))
+	if err != nil {
+		return err
+	}
+	*i = v
+	return nil
+}
@@ -0,0 +1,106 @@
+package xs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+)
+
+func TestDecimalRoundTrip(t *testing.T) {
+	cases := []struct {
+		in, out, canonical string
+	}{
+		{"10.50", "10.50", "10.5"},
+		{"-1.23", "-1.23", "-1.23"},
+		{"+100000.00", "100000.00", "100000"},
+		{".5", "0.5", "0.5"},
+		{"5.", "5", "5"},
+		{"-0.0", "0.0", "0"},
+		{"007", "7", "7"},
+		{"0.000000000000000000000000000001", "0.000000000000000000000000000001", "0.000000000000000000000000000001"},
+		{"123456789012345678901234567890.12", "123456789012345678901234567890.12", "123456789012345678901234567890.12"},
+	}
+
+	for _, c := range cases {
+		d, err := ParseDecimal(c.in)
+		if assert.NoError(t, err, c.in) {
+			assert.Equal(t, c.out, d.String(), c.in)
+			assert.Equal(t, c.canonical, d.Canonical(), c.in)
+		}
+	}
+
+	assert.Equal(t, "0", Decimal{}.String())
+	assert.Equal(t, "-0.05", NewDecimal(big.NewInt(-5), 2).String())
+	assert.Equal(t, "0.00", NewDecimal(nil, 2).String())
+}
+
+func TestDecimalErrors(t *testing.T) {
+	for _, s := range []string{"", ".", "+", "1e5", "1.2.3", "--1", "1,5", "NaN"} {
+		_, err := ParseDecimal(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestDecimalFacets(t *testing.T) {
+	cases := []struct {
+		in             string
+		totalDigits    int
+		fractionDigits int
+	}{
+		{"0", 1, 0},
+		{"0.05", 2, 2},
+		{"100", 3, 0},
+		{"1.50", 2, 1},
+		{"-123.456", 6, 3},
+	}
+
+	for _, c := range cases {
+		d := MustParseDecimal(c.in)
+		assert.Equal(t, c.totalDigits, d.TotalDigits(), c.in)
+		assert.Equal(t, c.fractionDigits, d.FractionDigits(), c.in)
+	}
+
+	assert.Equal(t, 0, MustParseDecimal("1.50").Cmp(MustParseDecimal("1.5")))
+	assert.Equal(t, -1, MustParseDecimal("-2").Cmp(MustParseDecimal("1.5")))
+	assert.Equal(t, 1, MustParseDecimal("0.1").Cmp(Decimal{}))
+	assert.Equal(t, 0.1, MustParseDecimal("0.10").Float64())
+}
+
+func TestRat(t *testing.T) {
+	var r Rat
+	assert.NoError(t, r.UnmarshalText([]byte("1.25")))
+	assert.Equal(t, big.NewRat(5, 4), r.Rat)
+	text, err := r.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "1.25", string(text))
+
+	assert.Equal(t, "0", Rat{}.String())
+	assert.Equal(t, "0.05", Rat{big.NewRat(1, 20)}.String())
+	assert.True(t, Rat{big.NewRat(-7, 8)}.IsDecimal())
+	assert.False(t, Rat{big.NewRat(1, 3)}.IsDecimal())
+	assert.Equal(t, "1/3", Rat{big.NewRat(1, 3)}.String())
+	_, err = Rat{big.NewRat(1, 3)}.MarshalText()
+	assert.Error(t, err)
+}
+
+func TestInt(t *testing.T) {
+	for in, out := range map[string]string{"010": "10", "+5": "5", " -123456789012345678901234567890 ": "-123456789012345678901234567890", "-0": "0"} {
+		i, err := ParseInt(in)
+		if assert.NoError(t, err, in) {
+			assert.Equal(t, out, i.String(), in)
+		}
+	}
+	for _, in := range []string{"", "+", "0x10", "1.0", "1_000"} {
+		_, err := ParseInt(in)
+		assert.Error(t, err, in)
+	}
+
+	var i Int
+	assert.Equal(t, "0", i.String())
+	assert.NoError(t, i.UnmarshalText([]byte("42")))
+	assert.Equal(t, big.NewInt(42), i.Int)
+	assert.Equal(t, 0, i.Decimal().Cmp(MustParseDecimal("42.0")))
+	text, err := i.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "42", string(text))
+}
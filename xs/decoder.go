@@ -0,0 +1,88 @@
+package xs
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// xsiNamespace is the namespace of the xsi:type attribute.
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// NewDecoder returns a decoder reading from r, which lets xsi:type attributes refer to namespaces declared by
+// ancestors of their elements.
+//
+// The encoding/xml package resolves names of elements and attributes, but it doesn't report namespaces in scope to
+// UnmarshalXML methods, so a QName in the value of xsi:type could be resolved only by declarations of the element
+// itself. The decoder copies the declaration of the prefix of the QName to the element if it's declared by an
+// ancestor.
+func NewDecoder(r io.Reader) *xml.Decoder {
+	return NewTokenDecoder(xml.NewDecoder(r))
+}
+
+// NewTokenDecoder is like NewDecoder, but it reads tokens from another token reader, e.g. a decoder which is not
+// strict.
+func NewTokenDecoder(r xml.TokenReader) *xml.Decoder {
+	return xml.NewTokenDecoder(&scopeReader{r: r})
+}
+
+// scopeReader tracks namespace declarations of open elements.
+type scopeReader struct {
+	r xml.TokenReader
+	// scopes are namespaces declared by open elements by their prefixes, the default namespace has no prefix.
+	scopes []map[string]string
+}
+
+func (s *scopeReader) Token() (xml.Token, error) {
+	tok, err := s.r.Token()
+	switch t := tok.(type) {
+	case xml.StartElement:
+		declared := map[string]string{}
+		for _, attr := range t.Attr {
+			if attr.Name.Space == "xmlns" {
+				declared[attr.Name.Local] = attr.Value
+			} else if attr.Name.Space == "" && attr.Name.Local == "xmlns" {
+				declared[""] = attr.Value
+			}
+		}
+		s.scopes = append(s.scopes, declared)
+
+		for _, attr := range t.Attr {
+			if attr.Name.Local != "type" || (attr.Name.Space != xsiNamespace && s.lookup(attr.Name.Space) != xsiNamespace) {
+				continue
+			}
+			prefix, _, found := strings.Cut(strings.TrimSpace(attr.Value), ":")
+			if !found {
+				prefix = ""
+			}
+			if _, ok := declared[prefix]; ok {
+				break
+			}
+			if space := s.lookup(prefix); space != "" {
+				name := xml.Name{Space: "xmlns", Local: prefix}
+				if prefix == "" {
+					name = xml.Name{Local: "xmlns"}
+				}
+				// The attributes could be shared with the token reader, so they are copied.
+				t.Attr = append(t.Attr[:len(t.Attr):len(t.Attr)], xml.Attr{Name: name, Value: space})
+			}
+			break
+		}
+		return t, err
+	case xml.EndElement:
+		if len(s.scopes) > 0 {
+			s.scopes = s.scopes[:len(s.scopes)-1]
+		}
+	}
+	return tok, err
+}
+
+// lookup returns the namespace of a prefix in scope, or an empty string if it isn't declared.
+func (s *scopeReader) lookup(prefix string) string {
+	for i := len(s.scopes) - 1; i >= 0; i-- {
+		if space, ok := s.scopes[i][prefix]; ok {
+			return space
+		}
+	}
+	return ""
+}
@@ -0,0 +1,35 @@
+package xs
+
+import (
+	"encoding/xml"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type typed struct {
+	Type  string     `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+	Attrs []xml.Attr `xml:",any,attr"`
+}
+
+type typedDocument struct {
+	Items []typed `xml:"item"`
+}
+
+func TestNewDecoder(t *testing.T) {
+	in := `<doc xmlns="urn:doc" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:a="urn:a">` +
+		`<item xsi:type="a:one"/>` +
+		`<item xmlns:a="urn:other" xsi:type="a:two"/>` +
+		`<item xsi:type="three"/>` +
+		`<item xsi:type="b:four"/>` +
+		`</doc>`
+	var out typedDocument
+	assert.NoError(t, NewDecoder(strings.NewReader(in)).Decode(&out))
+
+	// A declaration of an ancestor is copied to the element, unless the element declares the prefix itself.
+	assert.Equal(t, []xml.Attr{{Name: xml.Name{Space: "xmlns", Local: "a"}, Value: "urn:a"}}, out.Items[0].Attrs)
+	assert.Equal(t, []xml.Attr{{Name: xml.Name{Space: "xmlns", Local: "a"}, Value: "urn:other"}}, out.Items[1].Attrs)
+	assert.Equal(t, []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: "urn:doc"}}, out.Items[2].Attrs)
+	assert.Equal(t, 0, len(out.Items[3].Attrs))
+	assert.Equal(t, "b:four", out.Items[3].Type)
+}
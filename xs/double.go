@@ -0,0 +1,50 @@
+package xs
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// ParseDouble parses the lexical form of xs:double and xs:float, e.g. -1.5E2, .5, INF or NaN. Unlike strconv.ParseFloat,
+// it doesn't accept hexadecimal numbers, underscores and other spellings of infinity. A number which is too large is
+// rounded to infinity.
+func ParseDouble(s string) (float64, error) {
+	s = TrimSpace(s)
+	switch s {
+	case "INF", "+INF":
+		return math.Inf(1), nil
+	case "-INF":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+
+	p := &scanner{s: s}
+	if !p.lit('-') {
+		p.lit('+')
+	}
+	integer, _ := p.digits(0, len(p.s))
+	fraction := ""
+	if p.lit('.') {
+		fraction, _ = p.digits(0, len(p.s))
+	}
+	valid := integer != "" || fraction != ""
+	if p.lit('e') || p.lit('E') {
+		if !p.lit('-') {
+			p.lit('+')
+		}
+		_, ok := p.digits(1, len(p.s))
+		valid = valid && ok
+	}
+	if !valid || !p.eof() {
+		return 0, fmt.Errorf("xs: %q is not a valid double", s)
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, fmt.Errorf("xs: %q is not a valid double", s)
+	}
+	return v, nil
+}
@@ -0,0 +1,40 @@
+package xs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestParseDouble(t *testing.T) {
+	cases := []struct {
+		in  string
+		out float64
+	}{
+		{"1.5", 1.5},
+		{" -1.5E2 ", -150},
+		{"+.5e-1", 0.05},
+		{"5.", 5},
+		{"007", 7},
+		{"INF", math.Inf(1)},
+		{"-INF", math.Inf(-1)},
+		{"1e400", math.Inf(1)},
+	}
+	for _, c := range cases {
+		v, err := ParseDouble(c.in)
+		if assert.NoError(t, err, c.in) {
+			assert.Equal(t, c.out, v, c.in)
+		}
+	}
+
+	v, err := ParseDouble("NaN")
+	assert.NoError(t, err)
+	assert.True(t, math.IsNaN(v))
+}
+
+func TestParseDoubleErrors(t *testing.T) {
+	for _, s := range []string{"", ".", "+", "e5", "1e", "0x1p4", "1_000", "inf", "Infinity", "nan", "1.5 2", "12abc"} {
+		_, err := ParseDouble(s)
+		assert.Error(t, err, s)
+	}
+}
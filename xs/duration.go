@@ -0,0 +1,224 @@
+package xs
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is an xs:duration value, which is also used for xs:dayTimeDuration and xs:yearMonthDuration. Years are
+// kept as twelve months and days as 24 hours, both parts of a negative duration are negative.
+type Duration struct {
+	Months int
+	// Seconds is the day-time part of the duration.
+	Seconds time.Duration
+}
+
+// ParseDuration parses the lexical form of xs:duration, e.g. P1Y2M3DT4H5M6.7S or -PT1H.
+func ParseDuration(s string) (Duration, error) {
+	fail := func() (Duration, error) {
+		return Duration{}, fmt.Errorf("xs: %q is not a valid duration", s)
+	}
+
+	p := &scanner{s: TrimSpace(s)}
+	negative := p.lit('-')
+	if !p.lit('P') {
+		return fail()
+	}
+
+	var months, nsec int64
+	empty := true
+	// add adds a number of units to a total, it reports false on overflow.
+	add := func(n string, unit int64, total *int64) bool {
+		v, err := strconv.ParseInt(n, 10, 64)
+		if err != nil || v > math.MaxInt64/unit || *total > math.MaxInt64-v*unit {
+			return false
+		}
+		*total += v * unit
+		empty = false
+		return true
+	}
+
+	for _, c := range []struct {
+		designator byte
+		unit       int64
+		total      *int64
+	}{{'Y', 12, &months}, {'M', 1, &months}, {'D', int64(24 * time.Hour), &nsec}} {
+		if n, ok := p.designated(c.designator); ok && !add(n, c.unit, c.total) {
+			return fail()
+		}
+	}
+
+	if p.lit('T') {
+		empty = true
+		for _, c := range []struct {
+			designator byte
+			unit       int64
+		}{{'H', int64(time.Hour)}, {'M', int64(time.Minute)}} {
+			if n, ok := p.designated(c.designator); ok && !add(n, c.unit, &nsec) {
+				return fail()
+			}
+		}
+		if n, fraction, ok := p.seconds(); ok {
+			if !add(n, int64(time.Second), &nsec) || !add(fraction, 1, &nsec) {
+				return fail()
+			}
+		}
+	}
+
+	if empty || !p.eof() {
+		return fail()
+	}
+
+	d := Duration{Months: int(months), Seconds: time.Duration(nsec)}
+	if negative {
+		d.Months, d.Seconds = -d.Months, -d.Seconds
+	}
+	return d, nil
+}
+
+// designated reads a number followed by a designator. If there is no such component, nothing is read.
+func (p *scanner) designated(designator byte) (string, bool) {
+	start := p.pos
+	n, ok := p.digits(1, len(p.s))
+	if ok && p.lit(designator) {
+		return n, true
+	}
+	p.pos = start
+	return "", false
+}
+
+// seconds reads a number of seconds with an optional fraction, which is returned in nanoseconds. If there is no
+// seconds component, nothing is read.
+func (p *scanner) seconds() (string, string, bool) {
+	start := p.pos
+	n, _ := p.digits(0, len(p.s))
+	fraction := ""
+	if p.lit('.') {
+		fraction, _ = p.digits(0, len(p.s))
+	}
+	if (n == "" && fraction == "") || !p.lit('S') {
+		p.pos = start
+		return "", "", false
+	}
+	if n == "" {
+		n = "0"
+	}
+	if len(fraction) > 9 {
+		fraction = fraction[:9]
+	}
+	return n, fraction + strings.Repeat("0", 9-len(fraction)), true
+}
+
+// String returns the canonical form of the duration.
+func (d Duration) String() string {
+	var b strings.Builder
+
+	months, nsec := d.Months, d.Seconds
+	if months < 0 || nsec < 0 {
+		b.WriteByte('-')
+		months, nsec = -months, -nsec
+	}
+	b.WriteByte('P')
+	if months == 0 && nsec == 0 {
+		b.WriteString("T0S")
+		return b.String()
+	}
+
+	if y := months / 12; y > 0 {
+		fmt.Fprintf(&b, "%dY", y)
+	}
+	if m := months % 12; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+	}
+	if days := nsec / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+	}
+	if nsec %= 24 * time.Hour; nsec > 0 {
+		b.WriteByte('T')
+		if h := nsec / time.Hour; h > 0 {
+			fmt.Fprintf(&b, "%dH", h)
+		}
+		if m := nsec / time.Minute % 60; m > 0 {
+			fmt.Fprintf(&b, "%dM", m)
+		}
+		if s := nsec % time.Minute; s > 0 {
+			fmt.Fprintf(&b, "%d", s/time.Second)
+			if ns := s % time.Second; ns > 0 {
+				b.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", ns), "0"))
+			}
+			b.WriteByte('S')
+		}
+	}
+
+	return b.String()
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// AddTo adds the duration to a time as XML Schema does: the months are added first, and the day is limited by the
+// number of days of the resulting month, then the day-time part is added. E.g. 2000-01-31 plus P1M is 2000-02-29.
+func (d Duration) AddTo(t time.Time) time.Time {
+	year, month, day := t.Date()
+	months := int(month) - 1 + d.Months
+	years := months / 12
+	if months%12 < 0 {
+		years--
+	}
+	year += years
+	month = time.Month(months-years*12) + 1
+	if n := daysIn(month, year); day > n {
+		day = n
+	}
+	hour, min, sec := t.Clock()
+	return time.Date(year, month, day, hour, min, sec, t.Nanosecond(), t.Location()).Add(d.Seconds)
+}
+
+// referenceTimes are the dateTimes at which XML Schema compares durations.
+var referenceTimes = []time.Time{
+	time.Date(1696, 9, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1697, 2, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1903, 3, 1, 0, 0, 0, 0, time.UTC),
+	time.Date(1903, 7, 1, 0, 0, 0, 0, time.UTC),
+}
+
+// Compare compares durations by the partial order of XML Schema. It returns -1, 0 or +1, and reports false if the
+// durations are incomparable, e.g. P1M and P30D.
+func (d Duration) Compare(e Duration) (int, bool) {
+	if d == e {
+		return 0, true
+	}
+	r := 0
+	for i, t := range referenceTimes {
+		a, b := d.AddTo(t), e.AddTo(t)
+		c := 0
+		if a.Before(b) {
+			c = -1
+		} else if a.After(b) {
+			c = 1
+		}
+		if c == 0 || (i > 0 && c != r) {
+			return 0, false
+		}
+		r = c
+	}
+	return r, true
+}
+
+// Add returns the dateTime plus a duration, see Duration.AddTo. A dateTime without a timezone stays without it.
+func (t DateTime) Add(d Duration) DateTime {
+	return DateTime{Time: d.AddTo(t.Time), Timezone: t.Timezone}
+}
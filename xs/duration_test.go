@@ -0,0 +1,91 @@
+package xs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDurationRoundTrip(t *testing.T) {
+	cases := []struct {
+		in        string
+		canonical string
+	}{
+		{"P1Y2M3DT4H5M6.7S", "P1Y2M3DT4H5M6.7S"},
+		{"-PT1H", "-PT1H"},
+		{"P14M", "P1Y2M"},
+		{"PT36H", "P1DT12H"},
+		{"PT0S", "PT0S"},
+		{"P0Y", "PT0S"},
+		{"PT1.S", "PT1S"},
+		{"PT.5S", "PT0.5S"},
+		{" P1D ", "P1D"},
+		{"PT90M", "PT1H30M"},
+	}
+
+	for _, c := range cases {
+		d, err := ParseDuration(c.in)
+		if assert.NoError(t, err, c.in) {
+			assert.Equal(t, c.canonical, d.String(), c.in)
+		}
+	}
+
+	d, err := ParseDuration("-P1Y1DT1S")
+	assert.NoError(t, err)
+	assert.Equal(t, Duration{Months: -12, Seconds: -(24*time.Hour + time.Second)}, d)
+}
+
+func TestDurationErrors(t *testing.T) {
+	for _, s := range []string{"", "P", "PT", "P1YT", "1Y", "P-1Y", "P1D2M", "P1.5Y", "PT1H1H", "P99999999999999999999Y", "PT.S"} {
+		_, err := ParseDuration(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestDurationAddTo(t *testing.T) {
+	cases := []struct {
+		start    string
+		duration string
+		end      string
+	}{
+		{"2000-01-12T12:13:14Z", "P1Y3M5DT7H10M3.3S", "2001-04-17T19:23:17.3Z"},
+		{"2000-01-31T00:00:00", "P1M", "2000-02-29T00:00:00"},
+		{"2000-03-31T00:00:00", "-P1M", "2000-02-29T00:00:00"},
+		{"2000-01-01T00:00:00+02:00", "-PT1S", "1999-12-31T23:59:59+02:00"},
+		{"2000-01-15T00:00:00", "-P13M", "1998-12-15T00:00:00"},
+	}
+
+	for _, c := range cases {
+		start, err := ParseDateTime(c.start)
+		assert.NoError(t, err)
+		d, err := ParseDuration(c.duration)
+		assert.NoError(t, err)
+		assert.Equal(t, c.end, start.Add(d).String(), c.start+" + "+c.duration)
+	}
+}
+
+func TestDurationCompare(t *testing.T) {
+	cases := []struct {
+		a, b string
+		r    int
+		ok   bool
+	}{
+		{"P1Y", "P364D", 1, true},
+		{"P1Y", "P365D", 0, false},
+		{"P1Y", "P366D", 0, false},
+		{"P1Y", "P367D", -1, true},
+		{"P1M", "P27D", 1, true},
+		{"P1M", "P30D", 0, false},
+		{"P1M", "P32D", -1, true},
+		{"P1D", "PT24H", 0, true},
+		{"-P1D", "PT1S", -1, true},
+	}
+
+	for _, c := range cases {
+		a, _ := ParseDuration(c.a)
+		b, _ := ParseDuration(c.b)
+		r, ok := a.Compare(b)
+		assert.Equal(t, c.ok, ok, c.a+" <> "+c.b)
+		assert.Equal(t, c.r, r, c.a+" <> "+c.b)
+	}
+}
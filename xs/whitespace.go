@@ -0,0 +1,38 @@
+package xs
+
+import "strings"
+
+// XML whitespace is #x20, #x9, #xA and #xD only. Other Unicode spaces, such as NBSP, are part of values, unlike in
+// strings.Fields and strings.TrimSpace.
+const whitespace = " \t\n\r"
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// Fields splits a value of a list type into its items, which are separated by XML whitespace.
+func Fields(s string) []string {
+	return strings.FieldsFunc(s, isSpace)
+}
+
+// TrimSpace returns a value without leading and trailing XML whitespace.
+func TrimSpace(s string) string {
+	return strings.Trim(s, whitespace)
+}
+
+// Replace normalizes a value by the whiteSpace facet with the value replace: every #x9, #xA and #xD is replaced by
+// #x20.
+func Replace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isSpace(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+// Collapse normalizes a value by the whiteSpace facet with the value collapse: sequences of XML whitespace are
+// collapsed to a single #x20, and leading and trailing whitespace is removed.
+func Collapse(s string) string {
+	return strings.Join(Fields(s), " ")
+}
@@ -0,0 +1,15 @@
+package xs
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestWhitespace(t *testing.T) {
+	// NBSP and other Unicode spaces are not XML whitespace.
+	assert.Equal(t, []string{"a\u00a0b", "c\u2028"}, Fields(" a\u00a0b\t\r\nc\u2028 "))
+	assert.Equal(t, 0, len(Fields(" \t\r\n")))
+	assert.Equal(t, "\u00a0a\u00a0", TrimSpace("\t\u00a0a\u00a0\r\n"))
+	assert.Equal(t, "  a\u00a0b ", Replace("\t\ra\u00a0b\n"))
+	assert.Equal(t, "a\u00a0 b", Collapse("\t\ra\u00a0\n  b\n "))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"strconv"
+	"strings"
 )
 
 var unbounded = math.MaxInt32
@@ -19,6 +20,12 @@ type QName = string
 // A list of QName
 type ListOfQName []QName
 
+// UnmarshalXMLAttr splits a whitespace-separated list of QNames.
+func (l *ListOfQName) UnmarshalXMLAttr(attr xml.Attr) error {
+	*l = strings.Fields(attr.Value)
+	return nil
+}
+
 // anyURI represents an Internationalized Resource Identifier Reference (IRI).
 type anyURI = string
 
@@ -39,6 +46,9 @@ type fullDerivationSet string
 
 type Schema struct {
 	XMLAttrs []xml.Attr `xml:"-"`
+	// Location is a URI of the schema document, if it's known. Relative locations of the documents it refers to
+	// are resolved against it.
+	Location string `xml:"-"`
 
 	AttributeFormDefault  string `xml:"attributeFormDefault,attr"`
 	BlockDefault          string `xml:"blockDefault,attr"`
@@ -192,6 +202,13 @@ func unmarshalNestedParticleGroupChoice(d *xml.Decoder, tok xml.Token) (NestedPa
 				r = x
 
 			//    <xs:element ref="xs:any"/>
+			case xml.Name{Space: "http://www.w3.org/2001/XMLSchema", Local: "any"}:
+				x := &Any{}
+				if err = d.DecodeElement(x, &t); err != nil {
+					return nil, tok, err
+				}
+				r = x
+
 			default:
 				d.Skip()
 			}
@@ -327,6 +344,22 @@ func skipToStartElement(d *xml.Decoder, tok xml.Token) (xml.Token, error) {
 	}
 }
 
+// skipToElement skips all tokens until xml.StartElement or xml.EndElement.
+func skipToElement(d *xml.Decoder, tok xml.Token) (xml.Token, error) {
+	var err error
+	for {
+		switch tok.(type) {
+		case xml.StartElement, xml.EndElement:
+			return tok, nil
+		}
+
+		tok, err = d.Token()
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
 func (s *Schema) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	//s.Xmlns = make(map[string]string)
 	//s.XMLName = start.Name
@@ -658,10 +691,9 @@ type SimpleType struct {
 	Id string `xml:"id,attr"`
 
 	Restriction *struct {
-		Base QName  `xml:"base,attr"`
-		Id   string `xml:"id,attr"`
+		Base QName `xml:"base,attr"`
 
-		Annotation *Annotation `xml:"annotation"`
+		XMLSimpleRestrictionModel
 	} `xml:"restriction"`
 	List *struct {
 		Id       string `xml:"id,attr"`
@@ -693,7 +725,7 @@ type Element struct {
 	Name              string  `xml:"name,attr"`
 	Nillable          bool    `xml:"nillable,attr"`
 	Ref               string  `xml:"ref,attr"`
-	SubstitutionGroup string  `xml:"substitutionGroup"`
+	SubstitutionGroup string  `xml:"substitutionGroup,attr"`
 	TargetNamespace   string  `xml:"targetNamespace,attr"`
 	Type              QName   `xml:"type,attr"`
 
@@ -738,7 +770,14 @@ type Keyref struct {
 type XMLDefaultOpenContent struct {
 }
 
+// Include is a reference to a schema document of the same target namespace, or of no target namespace, whose
+// components become components of the including schema.
 type Include struct {
+	// Annotated
+	Annotation *Annotation `xml:"annotation"`
+	Id         string      `xml:"id,attr"`
+
+	SchemaLocation anyURI `xml:"schemaLocation,attr"`
 }
 
 type Import struct {
@@ -750,10 +789,32 @@ type Import struct {
 	SchemaLocation anyURI `xml:"schemaLocation,attr"`
 }
 
+// Redefine includes a schema document and replaces its simple and complex types, model groups and attribute groups
+// with their redefinitions, which are derived from or refer to the original components.
 type Redefine struct {
+	Id             string `xml:"id,attr"`
+	SchemaLocation anyURI `xml:"schemaLocation,attr"`
+
+	Annotations     []Annotation            `xml:"annotation"`
+	SimpleTypes     []XMLTopLevelSimpleType `xml:"simpleType"`
+	ComplexTypes    []ComplexType           `xml:"complexType"`
+	Groups          []Group                 `xml:"group"`
+	AttributeGroups []AttributeGroup        `xml:"attributeGroup"`
 }
 
+// Override includes a schema document and replaces its top-level components with the components of the same names.
 type Override struct {
+	Id             string `xml:"id,attr"`
+	SchemaLocation anyURI `xml:"schemaLocation,attr"`
+
+	Annotations     []Annotation            `xml:"annotation"`
+	SimpleTypes     []XMLTopLevelSimpleType `xml:"simpleType"`
+	ComplexTypes    []ComplexType           `xml:"complexType"`
+	Groups          []Group                 `xml:"group"`
+	AttributeGroups []AttributeGroup        `xml:"attributeGroup"`
+	Elements        []Element               `xml:"element"`
+	Attributes      []Attribute             `xml:"attribute"`
+	Notations       []Notation              `xml:"notation"`
 }
 
 type ComplexType struct {
@@ -763,7 +824,7 @@ type ComplexType struct {
 	Id                     string `xml:"id,attr"`
 	Mixed                  *bool  `xml:"mixed,attr"`
 	Name                   string `xml:"name,attr"`
-	DefaultAttributesApply bool   `xml:"defaultAttributesApply,attr"`
+	DefaultAttributesApply *bool  `xml:"defaultAttributesApply,attr"`
 
 	Annotation      *Annotation      `xml:"annotation"`
 	SimpleContent   *SimpleContent   `xml:"simpleContent"`
@@ -771,6 +832,7 @@ type ComplexType struct {
 	Group           *Group           `xml:"group"`
 	Attributes      []Attribute      `xml:"attribute"`
 	AttributeGroups []AttributeGroup `xml:"attributeGroup"`
+	AnyAttribute    *AnyAttribute    `xml:"anyAttribute"`
 	Assert          *Assert          `xml:"assert"`
 
 	TypeDefParticleGroup
@@ -794,6 +856,46 @@ func (t ComplexType) GetAttributes() []Attribute {
 	return t.Attributes
 }
 
+// GetAttributeGroups returns references to attribute groups declared on the type
+// or on its content derivation.
+func (t ComplexType) GetAttributeGroups() []AttributeGroup {
+	if t.ComplexContent != nil {
+		if t.ComplexContent.Extension != nil {
+			return t.ComplexContent.Extension.AttributeGroups
+		}
+		return t.ComplexContent.Restriction.AttributeGroups
+	}
+
+	if t.SimpleContent != nil {
+		if t.SimpleContent.Extension != nil {
+			return t.SimpleContent.Extension.AttributeGroups
+		}
+		return t.SimpleContent.Restriction.AttributeGroups
+	}
+
+	return t.AttributeGroups
+}
+
+// GetAnyAttribute returns an attribute wildcard declared on the type or on its
+// content derivation.
+func (t ComplexType) GetAnyAttribute() *AnyAttribute {
+	if t.ComplexContent != nil {
+		if t.ComplexContent.Extension != nil {
+			return t.ComplexContent.Extension.AnyAttribute
+		}
+		return t.ComplexContent.Restriction.AnyAttribute
+	}
+
+	if t.SimpleContent != nil {
+		if t.SimpleContent.Extension != nil {
+			return t.SimpleContent.Extension.AnyAtttribute
+		}
+		return t.SimpleContent.Restriction.AnyAtttribute
+	}
+
+	return t.AnyAttribute
+}
+
 type TypeDefParticleGroup struct {
 	All      *All      `xml:"all"`
 	Choice   *Choice   `xml:"choice"`
@@ -805,12 +907,9 @@ type SimpleContent struct {
 
 	Annotation  *Annotation `xml:"annotation"`
 	Restriction *struct {
-		Base QName  `xml:"base,attr"`
-		Id   string `xml:"id,attr"`
+		Base QName `xml:"base,attr"`
 
-		Annotation      *Annotation      `xml:"annotation"`
-		SimpleType      *SimpleType      `xml:"simpleType"`
-		FacetOrAny      []interface{}    `xml:"-"`
+		XMLSimpleRestrictionModel
 		Choice          *Choice          `xml:"choice"`
 		Sequence        *Sequence        `xml:"sequence"`
 		Attributes      []Attribute      `xml:"attribute"`
@@ -843,6 +942,7 @@ type ComplexContent struct {
 		Group           *Group           `xml:"group"`
 		Attributes      []Attribute      `xml:"attribute"`
 		AttributeGroups []AttributeGroup `xml:"attributeGroup"`
+		AnyAttribute    *AnyAttribute    `xml:"anyAttribute"`
 		Assert          *Assert          `xml:"assert"`
 
 		TypeDefParticleGroup
@@ -855,6 +955,7 @@ type ComplexContent struct {
 		Group           *Group           `xml:"group"`
 		Attributes      []Attribute      `xml:"attribute"`
 		AttributeGroups []AttributeGroup `xml:"attributeGroup"`
+		AnyAttribute    *AnyAttribute    `xml:"anyAttribute"`
 		Assert          *Assert          `xml:"assert"`
 
 		TypeDefParticleGroup
@@ -871,7 +972,16 @@ type AnyAttribute struct {
 	Annotation *Annotation `xml:"annotation"`
 }
 
+// AttributeGroup is either a named attribute group definition or a reference to it.
 type AttributeGroup struct {
+	Id   string `xml:"id,attr"`
+	Name string `xml:"name,attr"`
+	Ref  QName  `xml:"ref,attr"`
+
+	Annotation      *Annotation      `xml:"annotation"`
+	Attributes      []Attribute      `xml:"attribute"`
+	AttributeGroups []AttributeGroup `xml:"attributeGroup"`
+	AnyAttribute    *AnyAttribute    `xml:"anyAttribute"`
 }
 
 type Notation struct {
@@ -881,7 +991,8 @@ type Notation struct {
 // TypeDefParticleGroup
 
 type (
-	Sequence struct {
+	// ExplicitGroup is a group type for <sequence> and <choice> model groups.
+	ExplicitGroup struct {
 		XMLAttrs []xml.Attr `xml:"-"`
 
 		Id        string `xml:"id,attr"`
@@ -890,23 +1001,57 @@ type (
 
 		Annotation *Annotation `xml:"annotation"`
 		Content    []NestedParticle
+	}
+
+	Sequence struct {
+		ExplicitGroup
 
 		nestedParticle
 	}
 
 	Choice struct {
+		ExplicitGroup
+
 		nestedParticle
 	}
 
+	// All is a model group which elements may occur in any order.
 	All struct {
+		ExplicitGroup
 	}
 
+	// Group is either a named model group definition or a reference to it.
 	Group struct {
+		Id        string  `xml:"id,attr"`
+		Name      string  `xml:"name,attr"`
+		Ref       QName   `xml:"ref,attr"`
+		MaxOccurs *string `xml:"maxOccurs,attr"`
+		MinOccurs *int    `xml:"minOccurs,attr"`
+
+		Annotation *Annotation `xml:"annotation"`
+
+		TypeDefParticleGroup
+
+		nestedParticle
+	}
+
+	// Any is an element wildcard.
+	Any struct {
+		Id              string  `xml:"id,attr"`
+		MaxOccurs       *string `xml:"maxOccurs,attr"`
+		MinOccurs       *int    `xml:"minOccurs,attr"`
+		Namespace       string  `xml:"namespace,attr"`
+		NotNamespace    string  `xml:"notNamespace,attr"`
+		NotQName        string  `xml:"notQName,attr"`
+		ProcessContents string  `xml:"processContents,attr"`
+
+		Annotation *Annotation `xml:"annotation"`
+
 		nestedParticle
 	}
 )
 
-func (s *Sequence) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+func (s *ExplicitGroup) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var err error
 
 	// Setup Defaults
@@ -943,27 +1088,22 @@ func (s *Sequence) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		return err
 	}
 
-	tok, err = skipToStartElement(d, tok)
+	tok, err = skipToElement(d, tok)
 	if err != nil {
 		return err
 	}
 
 	// <xs:element ref="xs:annotation" minOccurs="0"/>
-	for {
-		if (start.Name != xml.Name{Space: "http://www.w3.org/2001/XMLSchema", Local: "annotation"}) {
-			break
-		}
-
+	if t, ok := tok.(xml.StartElement); ok && (t.Name == xml.Name{Space: "http://www.w3.org/2001/XMLSchema", Local: "annotation"}) {
 		s.Annotation = &Annotation{}
-		if err = d.DecodeElement(s.Annotation, &start); err != nil {
+		if err = d.DecodeElement(s.Annotation, &t); err != nil {
 			return err
 		}
 
-		tok, err = skipToStartElement(d, tok)
+		tok, err = d.Token()
 		if err != nil {
 			return err
 		}
-		start = tok.(xml.StartElement)
 	}
 
 	// <xs:group ref="xs:nestedParticle" minOccurs="0" maxOccurs="unbounded"/>